@@ -30,6 +30,10 @@ type HeartbeatRequest struct {
 	CpuUsage      float32                `protobuf:"fixed32,2,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`      // Useful for the dashboard
 	RamUsage      float32                `protobuf:"fixed32,3,opt,name=ram_usage,json=ramUsage,proto3" json:"ram_usage,omitempty"`      // Useful for the dashboard
 	ActiveJobs    int32                  `protobuf:"varint,4,opt,name=active_jobs,json=activeJobs,proto3" json:"active_jobs,omitempty"` // Tells Go how busy the worker is
+	JobIds        []string               `protobuf:"bytes,5,rep,name=job_ids,json=jobIds,proto3" json:"job_ids,omitempty"`              // Jobs the worker is running; their leases are renewed
+	Hostname      string                 `protobuf:"bytes,6,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Capabilities  []string               `protobuf:"bytes,7,rep,name=capabilities,proto3" json:"capabilities,omitempty"` // Task types the worker can run, e.g. "COOKING"
+	MaxJobs       int32                  `protobuf:"varint,8,opt,name=max_jobs,json=maxJobs,proto3" json:"max_jobs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -92,11 +96,41 @@ func (x *HeartbeatRequest) GetActiveJobs() int32 {
 	return 0
 }
 
+func (x *HeartbeatRequest) GetJobIds() []string {
+	if x != nil {
+		return x.JobIds
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetMaxJobs() int32 {
+	if x != nil {
+		return x.MaxJobs
+	}
+	return 0
+}
+
 type HeartbeatResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"` // Go can return 'false' if worker needs to restart
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Status          bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`                                           // Go can return 'false' if worker needs to restart
+	CancelledJobIds []string               `protobuf:"bytes,2,rep,name=cancelled_job_ids,json=cancelledJobIds,proto3" json:"cancelled_job_ids,omitempty"` // Jobs the worker must stop: cancelled or no longer leased to it
+	LeaseSeconds    int32                  `protobuf:"varint,3,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`           // Heartbeat well within this or the jobs are given to another worker
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *HeartbeatResponse) Reset() {
@@ -136,6 +170,20 @@ func (x *HeartbeatResponse) GetStatus() bool {
 	return false
 }
 
+func (x *HeartbeatResponse) GetCancelledJobIds() []string {
+	if x != nil {
+		return x.CancelledJobIds
+	}
+	return nil
+}
+
+func (x *HeartbeatResponse) GetLeaseSeconds() int32 {
+	if x != nil {
+		return x.LeaseSeconds
+	}
+	return 0
+}
+
 type HelloRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -221,6 +269,12 @@ type WorkerInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
 	SystemLoad    string                 `protobuf:"bytes,2,opt,name=system_load,json=systemLoad,proto3" json:"system_load,omitempty"`
+	Hostname      string                 `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Capabilities  []string               `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty"`       // Task types the worker can run, e.g. "COOKING"
+	MaxJobs       int32                  `protobuf:"varint,5,opt,name=max_jobs,json=maxJobs,proto3" json:"max_jobs,omitempty"` // Jobs the worker runs at once
+	ActiveJobs    int32                  `protobuf:"varint,6,opt,name=active_jobs,json=activeJobs,proto3" json:"active_jobs,omitempty"`
+	CpuUsage      float32                `protobuf:"fixed32,7,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"` // Percent; busy workers are not given more jobs
+	RamUsage      float32                `protobuf:"fixed32,8,opt,name=ram_usage,json=ramUsage,proto3" json:"ram_usage,omitempty"` // Percent
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -269,12 +323,60 @@ func (x *WorkerInfo) GetSystemLoad() string {
 	return ""
 }
 
+func (x *WorkerInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetMaxJobs() int32 {
+	if x != nil {
+		return x.MaxJobs
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetActiveJobs() int32 {
+	if x != nil {
+		return x.ActiveJobs
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetCpuUsage() float32 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetRamUsage() float32 {
+	if x != nil {
+		return x.RamUsage
+	}
+	return 0
+}
+
 // Data sent from the Go server to tell Rust what to do.
 type Job struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	VideoPath     string                 `protobuf:"bytes,2,opt,name=video_path,json=videoPath,proto3" json:"video_path,omitempty"`
 	TargetFormat  string                 `protobuf:"bytes,3,opt,name=target_format,json=targetFormat,proto3" json:"target_format,omitempty"`
+	TaskType      string                 `protobuf:"bytes,4,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	VideoId       string                 `protobuf:"bytes,5,opt,name=video_id,json=videoId,proto3" json:"video_id,omitempty"`
+	Attempt       int32                  `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	LeaseSeconds  int32                  `protobuf:"varint,7,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	VideoSize     int64                  `protobuf:"varint,8,opt,name=video_size,json=videoSize,proto3" json:"video_size,omitempty"` // Bytes; a worker compares it to its copy of video_path
+	Storyboard    *StoryboardSettings    `protobuf:"bytes,9,opt,name=storyboard,proto3" json:"storyboard,omitempty"`                 // How to cook the storyboard of a COOKING job
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -330,19 +432,148 @@ func (x *Job) GetTargetFormat() string {
 	return ""
 }
 
+func (x *Job) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *Job) GetVideoId() string {
+	if x != nil {
+		return x.VideoId
+	}
+	return ""
+}
+
+func (x *Job) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Job) GetLeaseSeconds() int32 {
+	if x != nil {
+		return x.LeaseSeconds
+	}
+	return 0
+}
+
+func (x *Job) GetVideoSize() int64 {
+	if x != nil {
+		return x.VideoSize
+	}
+	return 0
+}
+
+func (x *Job) GetStoryboard() *StoryboardSettings {
+	if x != nil {
+		return x.Storyboard
+	}
+	return nil
+}
+
+// Storyboard settings of the cooking profile a job uses.
+type StoryboardSettings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TileWidth     int32                  `protobuf:"varint,1,opt,name=tile_width,json=tileWidth,proto3" json:"tile_width,omitempty"`
+	TileHeight    int32                  `protobuf:"varint,2,opt,name=tile_height,json=tileHeight,proto3" json:"tile_height,omitempty"`
+	Columns       int32                  `protobuf:"varint,3,opt,name=columns,proto3" json:"columns,omitempty"`
+	Rows          int32                  `protobuf:"varint,4,opt,name=rows,proto3" json:"rows,omitempty"`
+	IntervalSec   float64                `protobuf:"fixed64,5,opt,name=interval_sec,json=intervalSec,proto3" json:"interval_sec,omitempty"` // One tile every this many seconds, 0 for one per keyframe
+	Quality       int32                  `protobuf:"varint,6,opt,name=quality,proto3" json:"quality,omitempty"`                             // JPEG quality of the sprite sheets, 1-100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoryboardSettings) Reset() {
+	*x = StoryboardSettings{}
+	mi := &file_ovaproto_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoryboardSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoryboardSettings) ProtoMessage() {}
+
+func (x *StoryboardSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoryboardSettings.ProtoReflect.Descriptor instead.
+func (*StoryboardSettings) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StoryboardSettings) GetTileWidth() int32 {
+	if x != nil {
+		return x.TileWidth
+	}
+	return 0
+}
+
+func (x *StoryboardSettings) GetTileHeight() int32 {
+	if x != nil {
+		return x.TileHeight
+	}
+	return 0
+}
+
+func (x *StoryboardSettings) GetColumns() int32 {
+	if x != nil {
+		return x.Columns
+	}
+	return 0
+}
+
+func (x *StoryboardSettings) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *StoryboardSettings) GetIntervalSec() float64 {
+	if x != nil {
+		return x.IntervalSec
+	}
+	return 0
+}
+
+func (x *StoryboardSettings) GetQuality() int32 {
+	if x != nil {
+		return x.Quality
+	}
+	return 0
+}
+
 // Data for progress tracking.
 type ProgressUpdate struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	JobId          string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	Percentage     float32                `protobuf:"fixed32,2,opt,name=percentage,proto3" json:"percentage,omitempty"`
 	BytesProcessed int64                  `protobuf:"varint,3,opt,name=bytes_processed,json=bytesProcessed,proto3" json:"bytes_processed,omitempty"`
+	WorkerId       string                 `protobuf:"bytes,4,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Message        string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"` // Optional line for the task log
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ProgressUpdate) Reset() {
 	*x = ProgressUpdate{}
-	mi := &file_ovaproto_proto_msgTypes[6]
+	mi := &file_ovaproto_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -354,7 +585,7 @@ func (x *ProgressUpdate) String() string {
 func (*ProgressUpdate) ProtoMessage() {}
 
 func (x *ProgressUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_ovaproto_proto_msgTypes[6]
+	mi := &file_ovaproto_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -367,7 +598,7 @@ func (x *ProgressUpdate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProgressUpdate.ProtoReflect.Descriptor instead.
 func (*ProgressUpdate) Descriptor() ([]byte, []int) {
-	return file_ovaproto_proto_rawDescGZIP(), []int{6}
+	return file_ovaproto_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ProgressUpdate) GetJobId() string {
@@ -391,30 +622,46 @@ func (x *ProgressUpdate) GetBytesProcessed() int64 {
 	return 0
 }
 
-// Simple confirmation message.
-type StreamResponse struct {
+func (x *ProgressUpdate) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ProgressUpdate) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Go's answer to a progress update.
+type ProgressAck struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Progress      float32                `protobuf:"fixed32,2,opt,name=progress,proto3" json:"progress,omitempty"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Accepted      bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`   // False when the job is no longer leased to the worker
+	Cancelled     bool                   `protobuf:"varint,3,opt,name=cancelled,proto3" json:"cancelled,omitempty"` // The job was cancelled: stop it and call FailJob
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamResponse) Reset() {
-	*x = StreamResponse{}
-	mi := &file_ovaproto_proto_msgTypes[7]
+func (x *ProgressAck) Reset() {
+	*x = ProgressAck{}
+	mi := &file_ovaproto_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamResponse) String() string {
+func (x *ProgressAck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamResponse) ProtoMessage() {}
+func (*ProgressAck) ProtoMessage() {}
 
-func (x *StreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_ovaproto_proto_msgTypes[7]
+func (x *ProgressAck) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -425,67 +672,592 @@ func (x *StreamResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
-func (*StreamResponse) Descriptor() ([]byte, []int) {
-	return file_ovaproto_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ProgressAck.ProtoReflect.Descriptor instead.
+func (*ProgressAck) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *StreamResponse) GetSuccess() bool {
+func (x *ProgressAck) GetJobId() string {
 	if x != nil {
-		return x.Success
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ProgressAck) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
 	}
 	return false
 }
 
-func (x *StreamResponse) GetProgress() float32 {
+func (x *ProgressAck) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+func (x *ProgressAck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Asks for the video of a leased job, from offset on to resume a broken download.
+type VideoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VideoRequest) Reset() {
+	*x = VideoRequest{}
+	mi := &file_ovaproto_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoRequest) ProtoMessage() {}
+
+func (x *VideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoRequest.ProtoReflect.Descriptor instead.
+func (*VideoRequest) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VideoRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *VideoRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *VideoRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type VideoChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        int64                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VideoChunk) Reset() {
+	*x = VideoChunk{}
+	mi := &file_ovaproto_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VideoChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoChunk) ProtoMessage() {}
+
+func (x *VideoChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoChunk.ProtoReflect.Descriptor instead.
+func (*VideoChunk) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VideoChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *VideoChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// A piece of an output file. Chunks of one file are sent in order, starting at offset 0.
+type ArtifactChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"` // Plain file name, e.g. "thumbnails.vtt"
+	Offset        int64                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data          []byte                 `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactChunk) Reset() {
+	*x = ArtifactChunk{}
+	mi := &file_ovaproto_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactChunk) ProtoMessage() {}
+
+func (x *ArtifactChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactChunk.ProtoReflect.Descriptor instead.
+func (*ArtifactChunk) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ArtifactChunk) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ArtifactChunk) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ArtifactChunk) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ArtifactChunk) GetOffset() int64 {
 	if x != nil {
-		return x.Progress
+		return x.Offset
 	}
 	return 0
 }
 
+func (x *ArtifactChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UploadSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         int32                  `protobuf:"varint,1,opt,name=files,proto3" json:"files,omitempty"`
+	Bytes         int64                  `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadSummary) Reset() {
+	*x = UploadSummary{}
+	mi := &file_ovaproto_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadSummary) ProtoMessage() {}
+
+func (x *UploadSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadSummary.ProtoReflect.Descriptor instead.
+func (*UploadSummary) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UploadSummary) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *UploadSummary) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+type CompleteJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Artifacts     []string               `protobuf:"bytes,3,rep,name=artifacts,proto3" json:"artifacts,omitempty"`                                                                       // Files the worker uploaded; Go checks they all arrived
+	Outputs       map[string]string      `protobuf:"bytes,4,rep,name=outputs,proto3" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Output metadata recorded in the task log, e.g. sprite count
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteJobRequest) Reset() {
+	*x = CompleteJobRequest{}
+	mi := &file_ovaproto_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteJobRequest) ProtoMessage() {}
+
+func (x *CompleteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteJobRequest.ProtoReflect.Descriptor instead.
+func (*CompleteJobRequest) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CompleteJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *CompleteJobRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *CompleteJobRequest) GetArtifacts() []string {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *CompleteJobRequest) GetOutputs() map[string]string {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+type FailJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Retryable     bool                   `protobuf:"varint,4,opt,name=retryable,proto3" json:"retryable,omitempty"`     // False when trying again cannot help
+	Interrupted   bool                   `protobuf:"varint,5,opt,name=interrupted,proto3" json:"interrupted,omitempty"` // The worker is shutting down; the task is queued again without using up an attempt
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailJobRequest) Reset() {
+	*x = FailJobRequest{}
+	mi := &file_ovaproto_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailJobRequest) ProtoMessage() {}
+
+func (x *FailJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailJobRequest.ProtoReflect.Descriptor instead.
+func (*FailJobRequest) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FailJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *FailJobRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *FailJobRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FailJobRequest) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *FailJobRequest) GetInterrupted() bool {
+	if x != nil {
+		return x.Interrupted
+	}
+	return false
+}
+
+type JobAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // Task status after the call, e.g. "COMPLETED" or "PENDING" for a retry
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobAck) Reset() {
+	*x = JobAck{}
+	mi := &file_ovaproto_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobAck) ProtoMessage() {}
+
+func (x *JobAck) ProtoReflect() protoreflect.Message {
+	mi := &file_ovaproto_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobAck.ProtoReflect.Descriptor instead.
+func (*JobAck) Descriptor() ([]byte, []int) {
+	return file_ovaproto_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *JobAck) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobAck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 var File_ovaproto_proto protoreflect.FileDescriptor
 
 const file_ovaproto_proto_rawDesc = "" +
 	"\n" +
-	"\x0eovaproto.proto\x12\aovagrpc\"\x8a\x01\n" +
+	"\x0eovaproto.proto\x12\aovagrpc\"\xfe\x01\n" +
 	"\x10HeartbeatRequest\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1b\n" +
 	"\tcpu_usage\x18\x02 \x01(\x02R\bcpuUsage\x12\x1b\n" +
 	"\tram_usage\x18\x03 \x01(\x02R\bramUsage\x12\x1f\n" +
 	"\vactive_jobs\x18\x04 \x01(\x05R\n" +
-	"activeJobs\"+\n" +
+	"activeJobs\x12\x17\n" +
+	"\ajob_ids\x18\x05 \x03(\tR\x06jobIds\x12\x1a\n" +
+	"\bhostname\x18\x06 \x01(\tR\bhostname\x12\"\n" +
+	"\fcapabilities\x18\a \x03(\tR\fcapabilities\x12\x19\n" +
+	"\bmax_jobs\x18\b \x01(\x05R\amaxJobs\"|\n" +
 	"\x11HeartbeatResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\bR\x06status\"\x0e\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12*\n" +
+	"\x11cancelled_job_ids\x18\x02 \x03(\tR\x0fcancelledJobIds\x12#\n" +
+	"\rlease_seconds\x18\x03 \x01(\x05R\fleaseSeconds\"\x0e\n" +
 	"\fHelloRequest\")\n" +
 	"\rHelloResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"J\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x80\x02\n" +
 	"\n" +
 	"WorkerInfo\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1f\n" +
 	"\vsystem_load\x18\x02 \x01(\tR\n" +
-	"systemLoad\"`\n" +
+	"systemLoad\x12\x1a\n" +
+	"\bhostname\x18\x03 \x01(\tR\bhostname\x12\"\n" +
+	"\fcapabilities\x18\x04 \x03(\tR\fcapabilities\x12\x19\n" +
+	"\bmax_jobs\x18\x05 \x01(\x05R\amaxJobs\x12\x1f\n" +
+	"\vactive_jobs\x18\x06 \x01(\x05R\n" +
+	"activeJobs\x12\x1b\n" +
+	"\tcpu_usage\x18\a \x01(\x02R\bcpuUsage\x12\x1b\n" +
+	"\tram_usage\x18\b \x01(\x02R\bramUsage\"\xb3\x02\n" +
 	"\x03Job\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1d\n" +
 	"\n" +
 	"video_path\x18\x02 \x01(\tR\tvideoPath\x12#\n" +
-	"\rtarget_format\x18\x03 \x01(\tR\ftargetFormat\"p\n" +
+	"\rtarget_format\x18\x03 \x01(\tR\ftargetFormat\x12\x1b\n" +
+	"\ttask_type\x18\x04 \x01(\tR\btaskType\x12\x19\n" +
+	"\bvideo_id\x18\x05 \x01(\tR\avideoId\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\x12#\n" +
+	"\rlease_seconds\x18\a \x01(\x05R\fleaseSeconds\x12\x1d\n" +
+	"\n" +
+	"video_size\x18\b \x01(\x03R\tvideoSize\x12;\n" +
+	"\n" +
+	"storyboard\x18\t \x01(\v2\x1b.ovagrpc.StoryboardSettingsR\n" +
+	"storyboard\"\xbf\x01\n" +
+	"\x12StoryboardSettings\x12\x1d\n" +
+	"\n" +
+	"tile_width\x18\x01 \x01(\x05R\ttileWidth\x12\x1f\n" +
+	"\vtile_height\x18\x02 \x01(\x05R\n" +
+	"tileHeight\x12\x18\n" +
+	"\acolumns\x18\x03 \x01(\x05R\acolumns\x12\x12\n" +
+	"\x04rows\x18\x04 \x01(\x05R\x04rows\x12!\n" +
+	"\finterval_sec\x18\x05 \x01(\x01R\vintervalSec\x12\x18\n" +
+	"\aquality\x18\x06 \x01(\x05R\aquality\"\xa7\x01\n" +
 	"\x0eProgressUpdate\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1e\n" +
 	"\n" +
 	"percentage\x18\x02 \x01(\x02R\n" +
 	"percentage\x12'\n" +
-	"\x0fbytes_processed\x18\x03 \x01(\x03R\x0ebytesProcessed\"F\n" +
-	"\x0eStreamResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1a\n" +
-	"\bprogress\x18\x02 \x01(\x02R\bprogress2\x82\x02\n" +
+	"\x0fbytes_processed\x18\x03 \x01(\x03R\x0ebytesProcessed\x12\x1b\n" +
+	"\tworker_id\x18\x04 \x01(\tR\bworkerId\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"x\n" +
+	"\vProgressAck\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\bR\baccepted\x12\x1c\n" +
+	"\tcancelled\x18\x03 \x01(\bR\tcancelled\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"Z\n" +
+	"\fVideoRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\"8\n" +
+	"\n" +
+	"VideoChunk\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\x83\x01\n" +
+	"\rArtifactChunk\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x05 \x01(\fR\x04data\";\n" +
+	"\rUploadSummary\x12\x14\n" +
+	"\x05files\x18\x01 \x01(\x05R\x05files\x12\x14\n" +
+	"\x05bytes\x18\x02 \x01(\x03R\x05bytes\"\xe6\x01\n" +
+	"\x12CompleteJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x1c\n" +
+	"\tartifacts\x18\x03 \x03(\tR\tartifacts\x12B\n" +
+	"\aoutputs\x18\x04 \x03(\v2(.ovagrpc.CompleteJobRequest.OutputsEntryR\aoutputs\x1a:\n" +
+	"\fOutputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9a\x01\n" +
+	"\x0eFailJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1c\n" +
+	"\tretryable\x18\x04 \x01(\bR\tretryable\x12 \n" +
+	"\vinterrupted\x18\x05 \x01(\bR\vinterrupted\"7\n" +
+	"\x06JobAck\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status2\xf7\x03\n" +
 	"\n" +
 	"OvaService\x12/\n" +
 	"\n" +
 	"GetNextJob\x12\x13.ovagrpc.WorkerInfo\x1a\f.ovagrpc.Job\x12B\n" +
 	"\tHeartbeat\x12\x19.ovagrpc.HeartbeatRequest\x1a\x1a.ovagrpc.HeartbeatResponse\x129\n" +
-	"\bSayHello\x12\x15.ovagrpc.HelloRequest\x1a\x16.ovagrpc.HelloResponse\x12D\n" +
-	"\x0eStreamProgress\x12\x17.ovagrpc.ProgressUpdate\x1a\x17.ovagrpc.StreamResponse0\x01B\x17Z\x15ovacli/proto;ovaprotob\x06proto3"
+	"\bSayHello\x12\x15.ovagrpc.HelloRequest\x1a\x16.ovagrpc.HelloResponse\x12C\n" +
+	"\x0eStreamProgress\x12\x17.ovagrpc.ProgressUpdate\x1a\x14.ovagrpc.ProgressAck(\x010\x01\x12=\n" +
+	"\rDownloadVideo\x12\x15.ovagrpc.VideoRequest\x1a\x13.ovagrpc.VideoChunk0\x01\x12C\n" +
+	"\x0fUploadArtifacts\x12\x16.ovagrpc.ArtifactChunk\x1a\x16.ovagrpc.UploadSummary(\x01\x12;\n" +
+	"\vCompleteJob\x12\x1b.ovagrpc.CompleteJobRequest\x1a\x0f.ovagrpc.JobAck\x123\n" +
+	"\aFailJob\x12\x17.ovagrpc.FailJobRequest\x1a\x0f.ovagrpc.JobAckB\x17Z\x15ovacli/proto;ovaprotob\x06proto3"
 
 var (
 	file_ovaproto_proto_rawDescOnce sync.Once
@@ -499,31 +1271,50 @@ func file_ovaproto_proto_rawDescGZIP() []byte {
 	return file_ovaproto_proto_rawDescData
 }
 
-var file_ovaproto_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_ovaproto_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_ovaproto_proto_goTypes = []any{
-	(*HeartbeatRequest)(nil),  // 0: ovagrpc.HeartbeatRequest
-	(*HeartbeatResponse)(nil), // 1: ovagrpc.HeartbeatResponse
-	(*HelloRequest)(nil),      // 2: ovagrpc.HelloRequest
-	(*HelloResponse)(nil),     // 3: ovagrpc.HelloResponse
-	(*WorkerInfo)(nil),        // 4: ovagrpc.WorkerInfo
-	(*Job)(nil),               // 5: ovagrpc.Job
-	(*ProgressUpdate)(nil),    // 6: ovagrpc.ProgressUpdate
-	(*StreamResponse)(nil),    // 7: ovagrpc.StreamResponse
+	(*HeartbeatRequest)(nil),   // 0: ovagrpc.HeartbeatRequest
+	(*HeartbeatResponse)(nil),  // 1: ovagrpc.HeartbeatResponse
+	(*HelloRequest)(nil),       // 2: ovagrpc.HelloRequest
+	(*HelloResponse)(nil),      // 3: ovagrpc.HelloResponse
+	(*WorkerInfo)(nil),         // 4: ovagrpc.WorkerInfo
+	(*Job)(nil),                // 5: ovagrpc.Job
+	(*StoryboardSettings)(nil), // 6: ovagrpc.StoryboardSettings
+	(*ProgressUpdate)(nil),     // 7: ovagrpc.ProgressUpdate
+	(*ProgressAck)(nil),        // 8: ovagrpc.ProgressAck
+	(*VideoRequest)(nil),       // 9: ovagrpc.VideoRequest
+	(*VideoChunk)(nil),         // 10: ovagrpc.VideoChunk
+	(*ArtifactChunk)(nil),      // 11: ovagrpc.ArtifactChunk
+	(*UploadSummary)(nil),      // 12: ovagrpc.UploadSummary
+	(*CompleteJobRequest)(nil), // 13: ovagrpc.CompleteJobRequest
+	(*FailJobRequest)(nil),     // 14: ovagrpc.FailJobRequest
+	(*JobAck)(nil),             // 15: ovagrpc.JobAck
+	nil,                        // 16: ovagrpc.CompleteJobRequest.OutputsEntry
 }
 var file_ovaproto_proto_depIdxs = []int32{
-	4, // 0: ovagrpc.OvaService.GetNextJob:input_type -> ovagrpc.WorkerInfo
-	0, // 1: ovagrpc.OvaService.Heartbeat:input_type -> ovagrpc.HeartbeatRequest
-	2, // 2: ovagrpc.OvaService.SayHello:input_type -> ovagrpc.HelloRequest
-	6, // 3: ovagrpc.OvaService.StreamProgress:input_type -> ovagrpc.ProgressUpdate
-	5, // 4: ovagrpc.OvaService.GetNextJob:output_type -> ovagrpc.Job
-	1, // 5: ovagrpc.OvaService.Heartbeat:output_type -> ovagrpc.HeartbeatResponse
-	3, // 6: ovagrpc.OvaService.SayHello:output_type -> ovagrpc.HelloResponse
-	7, // 7: ovagrpc.OvaService.StreamProgress:output_type -> ovagrpc.StreamResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6,  // 0: ovagrpc.Job.storyboard:type_name -> ovagrpc.StoryboardSettings
+	16, // 1: ovagrpc.CompleteJobRequest.outputs:type_name -> ovagrpc.CompleteJobRequest.OutputsEntry
+	4,  // 2: ovagrpc.OvaService.GetNextJob:input_type -> ovagrpc.WorkerInfo
+	0,  // 3: ovagrpc.OvaService.Heartbeat:input_type -> ovagrpc.HeartbeatRequest
+	2,  // 4: ovagrpc.OvaService.SayHello:input_type -> ovagrpc.HelloRequest
+	7,  // 5: ovagrpc.OvaService.StreamProgress:input_type -> ovagrpc.ProgressUpdate
+	9,  // 6: ovagrpc.OvaService.DownloadVideo:input_type -> ovagrpc.VideoRequest
+	11, // 7: ovagrpc.OvaService.UploadArtifacts:input_type -> ovagrpc.ArtifactChunk
+	13, // 8: ovagrpc.OvaService.CompleteJob:input_type -> ovagrpc.CompleteJobRequest
+	14, // 9: ovagrpc.OvaService.FailJob:input_type -> ovagrpc.FailJobRequest
+	5,  // 10: ovagrpc.OvaService.GetNextJob:output_type -> ovagrpc.Job
+	1,  // 11: ovagrpc.OvaService.Heartbeat:output_type -> ovagrpc.HeartbeatResponse
+	3,  // 12: ovagrpc.OvaService.SayHello:output_type -> ovagrpc.HelloResponse
+	8,  // 13: ovagrpc.OvaService.StreamProgress:output_type -> ovagrpc.ProgressAck
+	10, // 14: ovagrpc.OvaService.DownloadVideo:output_type -> ovagrpc.VideoChunk
+	12, // 15: ovagrpc.OvaService.UploadArtifacts:output_type -> ovagrpc.UploadSummary
+	15, // 16: ovagrpc.OvaService.CompleteJob:output_type -> ovagrpc.JobAck
+	15, // 17: ovagrpc.OvaService.FailJob:output_type -> ovagrpc.JobAck
+	10, // [10:18] is the sub-list for method output_type
+	2,  // [2:10] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_ovaproto_proto_init() }
@@ -537,7 +1328,7 @@ func file_ovaproto_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ovaproto_proto_rawDesc), len(file_ovaproto_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
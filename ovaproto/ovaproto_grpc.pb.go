@@ -21,10 +21,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OvaService_GetNextJob_FullMethodName     = "/ovagrpc.OvaService/GetNextJob"
-	OvaService_Heartbeat_FullMethodName      = "/ovagrpc.OvaService/Heartbeat"
-	OvaService_SayHello_FullMethodName       = "/ovagrpc.OvaService/SayHello"
-	OvaService_StreamProgress_FullMethodName = "/ovagrpc.OvaService/StreamProgress"
+	OvaService_GetNextJob_FullMethodName      = "/ovagrpc.OvaService/GetNextJob"
+	OvaService_Heartbeat_FullMethodName       = "/ovagrpc.OvaService/Heartbeat"
+	OvaService_SayHello_FullMethodName        = "/ovagrpc.OvaService/SayHello"
+	OvaService_StreamProgress_FullMethodName  = "/ovagrpc.OvaService/StreamProgress"
+	OvaService_DownloadVideo_FullMethodName   = "/ovagrpc.OvaService/DownloadVideo"
+	OvaService_UploadArtifacts_FullMethodName = "/ovagrpc.OvaService/UploadArtifacts"
+	OvaService_CompleteJob_FullMethodName     = "/ovagrpc.OvaService/CompleteJob"
+	OvaService_FailJob_FullMethodName         = "/ovagrpc.OvaService/FailJob"
 )
 
 // OvaServiceClient is the client API for OvaService service.
@@ -33,12 +37,23 @@ const (
 //
 // The service defines the "actions" your Go server and Rust worker can perform.
 type OvaServiceClient interface {
-	// Unary RPC: Rust asks for a job, Go sends one back.
+	// Unary RPC: Rust asks for a job, Go leases one from the task queue.
+	// An empty job_id means there is no work for this worker right now.
 	GetNextJob(ctx context.Context, in *WorkerInfo, opts ...grpc.CallOption) (*Job, error)
+	// Keeps the worker registered and renews the leases of the jobs it runs.
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	SayHello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error)
-	// Server Streaming: Rust sends continuous progress updates to Go.
-	StreamProgress(ctx context.Context, in *ProgressUpdate, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamResponse], error)
+	// Bidirectional Streaming: the worker streams progress of its jobs to Go, and Go answers
+	// every update, telling the worker when a job was cancelled. Updates renew the lease.
+	StreamProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProgressUpdate, ProgressAck], error)
+	// Server Streaming: a worker without access to the video file downloads it from Go.
+	DownloadVideo(ctx context.Context, in *VideoRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VideoChunk], error)
+	// Client Streaming: the worker uploads the output files of a job in chunks.
+	UploadArtifacts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArtifactChunk, UploadSummary], error)
+	// The job succeeded: Go installs the uploaded files and completes the task.
+	CompleteJob(ctx context.Context, in *CompleteJobRequest, opts ...grpc.CallOption) (*JobAck, error)
+	// The job failed: Go retries the task later or fails it.
+	FailJob(ctx context.Context, in *FailJobRequest, opts ...grpc.CallOption) (*JobAck, error)
 }
 
 type ovaServiceClient struct {
@@ -79,13 +94,26 @@ func (c *ovaServiceClient) SayHello(ctx context.Context, in *HelloRequest, opts
 	return out, nil
 }
 
-func (c *ovaServiceClient) StreamProgress(ctx context.Context, in *ProgressUpdate, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamResponse], error) {
+func (c *ovaServiceClient) StreamProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProgressUpdate, ProgressAck], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &OvaService_ServiceDesc.Streams[0], OvaService_StreamProgress_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[ProgressUpdate, StreamResponse]{ClientStream: stream}
+	x := &grpc.GenericClientStream[ProgressUpdate, ProgressAck]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OvaService_StreamProgressClient = grpc.BidiStreamingClient[ProgressUpdate, ProgressAck]
+
+func (c *ovaServiceClient) DownloadVideo(ctx context.Context, in *VideoRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VideoChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OvaService_ServiceDesc.Streams[1], OvaService_DownloadVideo_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[VideoRequest, VideoChunk]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -96,7 +124,40 @@ func (c *ovaServiceClient) StreamProgress(ctx context.Context, in *ProgressUpdat
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OvaService_StreamProgressClient = grpc.ServerStreamingClient[StreamResponse]
+type OvaService_DownloadVideoClient = grpc.ServerStreamingClient[VideoChunk]
+
+func (c *ovaServiceClient) UploadArtifacts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArtifactChunk, UploadSummary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OvaService_ServiceDesc.Streams[2], OvaService_UploadArtifacts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ArtifactChunk, UploadSummary]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OvaService_UploadArtifactsClient = grpc.ClientStreamingClient[ArtifactChunk, UploadSummary]
+
+func (c *ovaServiceClient) CompleteJob(ctx context.Context, in *CompleteJobRequest, opts ...grpc.CallOption) (*JobAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobAck)
+	err := c.cc.Invoke(ctx, OvaService_CompleteJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ovaServiceClient) FailJob(ctx context.Context, in *FailJobRequest, opts ...grpc.CallOption) (*JobAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobAck)
+	err := c.cc.Invoke(ctx, OvaService_FailJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
 // OvaServiceServer is the server API for OvaService service.
 // All implementations must embed UnimplementedOvaServiceServer
@@ -104,12 +165,23 @@ type OvaService_StreamProgressClient = grpc.ServerStreamingClient[StreamResponse
 //
 // The service defines the "actions" your Go server and Rust worker can perform.
 type OvaServiceServer interface {
-	// Unary RPC: Rust asks for a job, Go sends one back.
+	// Unary RPC: Rust asks for a job, Go leases one from the task queue.
+	// An empty job_id means there is no work for this worker right now.
 	GetNextJob(context.Context, *WorkerInfo) (*Job, error)
+	// Keeps the worker registered and renews the leases of the jobs it runs.
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	SayHello(context.Context, *HelloRequest) (*HelloResponse, error)
-	// Server Streaming: Rust sends continuous progress updates to Go.
-	StreamProgress(*ProgressUpdate, grpc.ServerStreamingServer[StreamResponse]) error
+	// Bidirectional Streaming: the worker streams progress of its jobs to Go, and Go answers
+	// every update, telling the worker when a job was cancelled. Updates renew the lease.
+	StreamProgress(grpc.BidiStreamingServer[ProgressUpdate, ProgressAck]) error
+	// Server Streaming: a worker without access to the video file downloads it from Go.
+	DownloadVideo(*VideoRequest, grpc.ServerStreamingServer[VideoChunk]) error
+	// Client Streaming: the worker uploads the output files of a job in chunks.
+	UploadArtifacts(grpc.ClientStreamingServer[ArtifactChunk, UploadSummary]) error
+	// The job succeeded: Go installs the uploaded files and completes the task.
+	CompleteJob(context.Context, *CompleteJobRequest) (*JobAck, error)
+	// The job failed: Go retries the task later or fails it.
+	FailJob(context.Context, *FailJobRequest) (*JobAck, error)
 	mustEmbedUnimplementedOvaServiceServer()
 }
 
@@ -129,9 +201,21 @@ func (UnimplementedOvaServiceServer) Heartbeat(context.Context, *HeartbeatReques
 func (UnimplementedOvaServiceServer) SayHello(context.Context, *HelloRequest) (*HelloResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SayHello not implemented")
 }
-func (UnimplementedOvaServiceServer) StreamProgress(*ProgressUpdate, grpc.ServerStreamingServer[StreamResponse]) error {
+func (UnimplementedOvaServiceServer) StreamProgress(grpc.BidiStreamingServer[ProgressUpdate, ProgressAck]) error {
 	return status.Error(codes.Unimplemented, "method StreamProgress not implemented")
 }
+func (UnimplementedOvaServiceServer) DownloadVideo(*VideoRequest, grpc.ServerStreamingServer[VideoChunk]) error {
+	return status.Error(codes.Unimplemented, "method DownloadVideo not implemented")
+}
+func (UnimplementedOvaServiceServer) UploadArtifacts(grpc.ClientStreamingServer[ArtifactChunk, UploadSummary]) error {
+	return status.Error(codes.Unimplemented, "method UploadArtifacts not implemented")
+}
+func (UnimplementedOvaServiceServer) CompleteJob(context.Context, *CompleteJobRequest) (*JobAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompleteJob not implemented")
+}
+func (UnimplementedOvaServiceServer) FailJob(context.Context, *FailJobRequest) (*JobAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method FailJob not implemented")
+}
 func (UnimplementedOvaServiceServer) mustEmbedUnimplementedOvaServiceServer() {}
 func (UnimplementedOvaServiceServer) testEmbeddedByValue()                    {}
 
@@ -208,15 +292,65 @@ func _OvaService_SayHello_Handler(srv interface{}, ctx context.Context, dec func
 }
 
 func _OvaService_StreamProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ProgressUpdate)
+	return srv.(OvaServiceServer).StreamProgress(&grpc.GenericServerStream[ProgressUpdate, ProgressAck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OvaService_StreamProgressServer = grpc.BidiStreamingServer[ProgressUpdate, ProgressAck]
+
+func _OvaService_DownloadVideo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(VideoRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(OvaServiceServer).StreamProgress(m, &grpc.GenericServerStream[ProgressUpdate, StreamResponse]{ServerStream: stream})
+	return srv.(OvaServiceServer).DownloadVideo(m, &grpc.GenericServerStream[VideoRequest, VideoChunk]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OvaService_StreamProgressServer = grpc.ServerStreamingServer[StreamResponse]
+type OvaService_DownloadVideoServer = grpc.ServerStreamingServer[VideoChunk]
+
+func _OvaService_UploadArtifacts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OvaServiceServer).UploadArtifacts(&grpc.GenericServerStream[ArtifactChunk, UploadSummary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OvaService_UploadArtifactsServer = grpc.ClientStreamingServer[ArtifactChunk, UploadSummary]
+
+func _OvaService_CompleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OvaServiceServer).CompleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OvaService_CompleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OvaServiceServer).CompleteJob(ctx, req.(*CompleteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OvaService_FailJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FailJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OvaServiceServer).FailJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OvaService_FailJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OvaServiceServer).FailJob(ctx, req.(*FailJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
 // OvaService_ServiceDesc is the grpc.ServiceDesc for OvaService service.
 // It's only intended for direct use with grpc.RegisterService,
@@ -237,12 +371,31 @@ var OvaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SayHello",
 			Handler:    _OvaService_SayHello_Handler,
 		},
+		{
+			MethodName: "CompleteJob",
+			Handler:    _OvaService_CompleteJob_Handler,
+		},
+		{
+			MethodName: "FailJob",
+			Handler:    _OvaService_FailJob_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "StreamProgress",
 			Handler:       _OvaService_StreamProgress_Handler,
 			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadVideo",
+			Handler:       _OvaService_DownloadVideo_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadArtifacts",
+			Handler:       _OvaService_UploadArtifacts_Handler,
+			ClientStreams: true,
 		},
 	},
 	Metadata: "ovaproto.proto",
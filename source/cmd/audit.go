@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"ova-cli/source/internal/datatypes"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of user and admin actions",
+	Run: func(cmd *cobra.Command, args []string) {
+		since, _ := cmd.Flags().GetString("since")
+		until, _ := cmd.Flags().GetString("until")
+		actor, _ := cmd.Flags().GetString("actor")
+		action, _ := cmd.Flags().GetString("action")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		filter := datatypes.AuditFilter{
+			Action: action,
+			Limit:  limit,
+		}
+
+		var err error
+		if filter.Since, err = datatypes.ParseAuditTime(since); err != nil {
+			pterm.Error.Printf("Invalid --since %q, use YYYY-MM-DD or RFC 3339\n", since)
+			os.Exit(1)
+		}
+		if filter.Until, err = datatypes.ParseAuditTime(until); err != nil {
+			pterm.Error.Printf("Invalid --until %q, use YYYY-MM-DD or RFC 3339\n", until)
+			os.Exit(1)
+		}
+
+		repository := openUserRepository(cmd)
+
+		// Accept a username as well as an account ID for the actor
+		if actor != "" {
+			filter.ActorID = actor
+			if user, err := repository.GetUserByUsername(actor); err == nil {
+				filter.ActorID = user.AccountID
+			}
+		}
+
+		events, err := repository.QueryAuditEvents(filter)
+		if err != nil {
+			pterm.Error.Printf("Error loading audit log: %v\n", err)
+			os.Exit(1)
+		}
+
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+		if jsonFlag {
+			jsonOutput, err := json.Marshal(events)
+			if err != nil {
+				pterm.Error.Printf("Failed to marshal audit events to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonOutput))
+			return
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No audit events found.")
+			return
+		}
+		fmt.Println("Time\tActor\tAction\tTargets\tIP")
+		for _, e := range events {
+			actorName := e.ActorName
+			if actorName == "" {
+				actorName = "-"
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n",
+				e.Time.Local().Format("2006-01-02 15:04:05"),
+				actorName,
+				e.Action,
+				strings.Join(e.TargetIDs, ","),
+				e.IP,
+			)
+		}
+	},
+}
+
+var auditPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove audit events older than the configured retention",
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+
+		days := repository.AuditRetentionDays()
+		if days == 0 {
+			pterm.Info.Println("Audit retention is off, events are kept forever")
+			return
+		}
+
+		removed, err := repository.PruneAuditEvents()
+		if err != nil {
+			pterm.Error.Printf("Failed to prune audit log: %v\n", err)
+			os.Exit(1)
+		}
+		pterm.Success.Printf("Removed %d audit events older than %d days\n", removed, days)
+	},
+}
+
+// InitCommandAudit adds the audit log commands to rootCmd
+func InitCommandAudit(rootCmd *cobra.Command) {
+	auditCmd.Flags().String("since", "", "Only show events at or after this time (YYYY-MM-DD or RFC 3339)")
+	auditCmd.Flags().String("until", "", "Only show events at or before this time (YYYY-MM-DD or RFC 3339)")
+	auditCmd.Flags().String("actor", "", "Only show events by this username or account ID")
+	auditCmd.Flags().String("action", "", "Only show this action, or a prefix such as 'video.'")
+	auditCmd.Flags().IntP("limit", "n", 100, "Maximum number of events to show (0 for all)")
+	auditCmd.Flags().BoolP("json", "j", false, "Output the data in JSON format")
+	auditCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+
+	auditPruneCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditPruneCmd)
+}
@@ -7,8 +7,10 @@ import (
 	"strconv"
 	"strings"
 
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 
+	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -74,7 +76,193 @@ var configServerCmd = &cobra.Command{
 	},
 }
 
+var configRequireTwoFactorCmd = &cobra.Command{
+	Use:   "require-2fa [roles...]",
+	Short: "Require two-factor authentication for the given roles (no roles clears the policy)",
+	Run: func(cmd *cobra.Command, args []string) {
+		roles := make([]datatypes.UserRole, 0, len(args))
+		for _, arg := range args {
+			role := datatypes.UserRole(arg)
+			if !datatypes.IsValidUserRole(role) {
+				pterm.Error.Printf("Unknown role: %s\n", arg)
+				os.Exit(1)
+			}
+			roles = append(roles, role)
+		}
+
+		// Create RepoManager instance
+		repoPath, err := filepath.Abs(".")
+		if err != nil {
+			pterm.Error.Println("Failed to resolve path:", err)
+			os.Exit(1)
+		}
+
+		repoManager, err := repo.NewRepoManager(repoPath)
+		if err != nil {
+			fmt.Println("Failed to initialize repository:", err)
+			return
+		}
+
+		cfg := repoManager.GetConfigs()
+		cfg.RequireTwoFactorRoles = roles
+
+		if err := repoManager.SaveRepoConfig(cfg); err != nil {
+			pterm.Error.Printf("Failed to save config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(roles) == 0 {
+			pterm.Success.Println("Two-factor authentication is no longer required for any role")
+		} else {
+			pterm.Success.Printf("Two-factor authentication required for roles: %v\n", roles)
+		}
+	},
+}
+
+var configAuditRetentionCmd = &cobra.Command{
+	Use:   "audit-retention [days]",
+	Short: "Set how many days audit events are kept (0 for the default of 365, -1 keeps them forever)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		days, err := strconv.Atoi(args[0])
+		if err != nil || days < -1 {
+			pterm.Error.Printf("Invalid number of days: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		// Create RepoManager instance
+		repoPath, err := filepath.Abs(".")
+		if err != nil {
+			pterm.Error.Println("Failed to resolve path:", err)
+			os.Exit(1)
+		}
+
+		repoManager, err := repo.NewRepoManager(repoPath)
+		if err != nil {
+			fmt.Println("Failed to initialize repository:", err)
+			return
+		}
+
+		cfg := repoManager.GetConfigs()
+		cfg.AuditRetentionDays = days
+
+		if err := repoManager.SaveRepoConfig(cfg); err != nil {
+			pterm.Error.Printf("Failed to save config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if kept := repoManager.AuditRetentionDays(); kept == 0 {
+			pterm.Success.Println("Audit events are kept forever")
+		} else {
+			pterm.Success.Printf("Audit events are kept for %d days\n", kept)
+		}
+	},
+}
+
+var configWorkerTokenCmd = &cobra.Command{
+	Use:   "worker-token [token]",
+	Short: "Set the token remote workers authenticate with (generated when omitted, \"off\" refuses workers)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		token := ""
+		if len(args) == 1 {
+			token = args[0]
+		} else {
+			generated, err := gonanoid.New(32)
+			if err != nil {
+				pterm.Error.Printf("Failed to generate token: %v\n", err)
+				os.Exit(1)
+			}
+			token = generated
+		}
+		if token == "off" {
+			token = ""
+		}
+
+		// Create RepoManager instance
+		repoPath, err := filepath.Abs(".")
+		if err != nil {
+			pterm.Error.Println("Failed to resolve path:", err)
+			os.Exit(1)
+		}
+
+		repoManager, err := repo.NewRepoManager(repoPath)
+		if err != nil {
+			fmt.Println("Failed to initialize repository:", err)
+			return
+		}
+
+		cfg := repoManager.GetConfigs()
+		cfg.WorkerToken = token
+
+		if err := repoManager.SaveRepoConfig(cfg); err != nil {
+			pterm.Error.Printf("Failed to save config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if token == "" {
+			pterm.Success.Println("Remote workers are refused")
+		} else {
+			pterm.Success.Printf("Remote workers authenticate with token: %s\n", token)
+		}
+	},
+}
+
+var configFeedCmd = &cobra.Command{
+	Use:       "feed <most-viewed|most-liked|trending|random> <on|off>",
+	Short:     "Switch a global video feed on or off and set its default window",
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: repo.FeedNames,
+	Run: func(cmd *cobra.Command, args []string) {
+		if args[1] != "on" && args[1] != "off" {
+			pterm.Error.Printf("Expected on or off, got: %s\n", args[1])
+			os.Exit(1)
+		}
+
+		// Create RepoManager instance
+		repoPath, err := filepath.Abs(".")
+		if err != nil {
+			pterm.Error.Println("Failed to resolve path:", err)
+			os.Exit(1)
+		}
+
+		repoManager, err := repo.NewRepoManager(repoPath)
+		if err != nil {
+			fmt.Println("Failed to initialize repository:", err)
+			return
+		}
+
+		feed, err := repoManager.FeedConfig(args[0])
+		if err != nil {
+			pterm.Error.Printf("Unknown feed: %s (expected one of %v)\n", args[0], repo.FeedNames)
+			os.Exit(1)
+		}
+		feed.Enabled = args[1] == "on"
+		if cmd.Flags().Changed("days") {
+			feed.WindowDays, _ = cmd.Flags().GetInt("days")
+		}
+		cfg := repoManager.GetConfigs()
+		if cmd.Flags().Changed("pool-minutes") {
+			cfg.Feeds.RandomPoolMinutes, _ = cmd.Flags().GetInt("pool-minutes")
+		}
+
+		if err := repoManager.SaveRepoConfig(cfg); err != nil {
+			pterm.Error.Printf("Failed to save config: %v\n", err)
+			os.Exit(1)
+		}
+
+		pterm.Success.Printf("Feed %s is %s\n", args[0], args[1])
+	},
+}
+
 func InitCommandConfig(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configServerCmd)
+	configCmd.AddCommand(configRequireTwoFactorCmd)
+	configCmd.AddCommand(configAuditRetentionCmd)
+	configCmd.AddCommand(configWorkerTokenCmd)
+
+	configFeedCmd.Flags().Int("days", 0, "Default window in days (0 for the feed's own default)")
+	configFeedCmd.Flags().Int("pool-minutes", 0, "How long a user's random order is kept (random feed only, 0 for 10)")
+	configCmd.AddCommand(configFeedCmd)
 }
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -12,6 +14,21 @@ import (
 var cookCmd = &cobra.Command{
 	Use:   "cook",
 	Short: "Cook Indexed Videos",
+	Long: `Cook indexed videos.
+
+Every video is queued as a cooking task. Without --no-wait the queue is run
+here until they are done; with it a running "ovacli serve" picks them up.
+
+--profile picks a cooking profile from the repository config; without it the
+config's default profile is used. Only what a video is missing or what the
+profile makes with other settings is cooked again.
+
+--only limits cooking to some artifacts (thumbnail, preview, storyboard),
+--force cooks them even when they are up to date, and --filter only cooks the
+indexed videos whose title contains the query. For example, to redo broken
+previews:
+
+  ovacli cook --force --only preview --filter holiday`,
 	Run: func(cmd *cobra.Command, args []string) {
 		repoRoot, err := os.Getwd()
 		if err != nil {
@@ -25,37 +42,76 @@ var cookCmd = &cobra.Command{
 			return
 		}
 
-		// Scan for all video paths
-		videoPaths, err := repoManager.ScanDiskForVideos()
-		if err != nil || len(videoPaths) == 0 {
-			fmt.Println("No videos found in the repository.")
+		var options repo.CookingOptions
+		options.Profile, _ = cmd.Flags().GetString("profile")
+		options.Only, _ = cmd.Flags().GetStringSlice("only")
+		options.Force, _ = cmd.Flags().GetBool("force")
+		profileName, err := repoManager.CheckCookingOptions(options)
+		if err != nil {
+			fmt.Println("Invalid cooking options:", err)
+			fmt.Println("Available profiles:", strings.Join(repoManager.GetCookingProfileNames(), ", "))
 			return
 		}
 
-		progressChan := make(chan int)
-		errorChan := make(chan error)
-
-		go func() {
-			for progress := range progressChan {
-				fmt.Printf("\rProgress: %d%%", progress) // Update progress in place
+		var tasks []*datatypes.TaskData
+		if filter, _ := cmd.Flags().GetString("filter"); filter != "" {
+			// Only indexed videos can match
+			videoIds, err := repoManager.SearchVideos(datatypes.VideoSearchCriteria{Query: filter})
+			if err != nil || len(videoIds) == 0 {
+				fmt.Printf("No indexed videos match %q.\n", filter)
+				return
 			}
-		}()
-
-		go func() {
-			for err := range errorChan {
-				fmt.Printf("\nCooking Error: %v\n", err)
+			for _, videoId := range videoIds {
+				task, err := repoManager.EnqueueVideoCooking(videoId, "", datatypes.TaskPriorityNormal, options)
+				if err != nil {
+					fmt.Printf("Failed to queue video %s: %v\n", videoId, err)
+					return
+				}
+				tasks = append(tasks, task)
+			}
+		} else {
+			// Scan for all video paths
+			videoPaths, err := repoManager.ScanDiskForVideos()
+			if err != nil || len(videoPaths) == 0 {
+				fmt.Println("No videos found in the repository.")
+				return
 			}
-		}()
+			for _, videoPath := range videoPaths {
+				task, err := repoManager.EnqueueCooking(videoPath, datatypes.TaskPriorityNormal, options)
+				if err != nil {
+					fmt.Printf("Failed to queue %s: %v\n", videoPath, err)
+					return
+				}
+				tasks = append(tasks, task)
+			}
+		}
+		fmt.Printf("Queued %d videos for cooking with profile %q.\n", len(tasks), profileName)
+		if noWait, _ := cmd.Flags().GetBool("no-wait"); noWait {
+			return
+		}
 
-		// Use the new CookMultiVideos method
-		_ = repoManager.CookMultiVideos(videoPaths, progressChan, errorChan)
+		finished, err := runQueuedTasks(repoManager, tasks, "Cooking", nil)
+		if err != nil {
+			fmt.Printf("Cooking stopped: %v\n", err)
+			return
+		}
+
+		for _, task := range finished {
+			if task.Status == datatypes.TaskFailed {
+				fmt.Printf("Cooking Error: %s: %s\n", task.VideoPath, task.Error)
+			}
+		}
 
 		// Print completion message
-		fmt.Println("\n✅ Sprite sheets and VTT generation complete.")
+		fmt.Println("\n✅ Cooking complete.")
 	},
 }
 
 func InitCommandCook(rootCmd *cobra.Command) {
-
+	cookCmd.Flags().String("profile", "", "Cooking profile from the repository config (default: the config's default profile)")
+	cookCmd.Flags().StringSlice("only", nil, "Only cook these artifacts: thumbnail, preview, storyboard")
+	cookCmd.Flags().Bool("force", false, "Cook even when the artifacts are up to date")
+	cookCmd.Flags().String("filter", "", "Only cook indexed videos whose title contains this text")
+	cookCmd.Flags().Bool("no-wait", false, "Only queue the tasks, for a running server to process")
 	rootCmd.AddCommand(cookCmd)
 }
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"ova-cli/source/internal/filehash"
@@ -31,8 +35,11 @@ var videoDetailsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		videoPath := args[0] // Get the path from the arguments
 
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		// Call GetVideoDetails to get the video details
-		videoDetails, err := thirdparty.GetVideoDetails(videoPath)
+		videoDetails, err := thirdparty.GetVideoDetails(ctx, videoPath)
 		if err != nil {
 			fmt.Printf("Error retrieving video details: %v\n", err)
 			return
@@ -58,8 +65,11 @@ var mp4infoCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		videoPath := args[0] // Get the path from the arguments
 
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		// Call GetMP4Info to get MP4 info
-		mp4Info, err := thirdparty.GetMP4Info(videoPath)
+		mp4Info, err := thirdparty.GetMP4Info(ctx, videoPath)
 		if err != nil {
 			fmt.Printf("Error retrieving MP4 info: %v\n", err)
 			return
@@ -3,10 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
-	"path/filepath"
-	"sync"
-	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +12,10 @@ import (
 var indexCmd = &cobra.Command{
 	Use:   "index",
 	Short: "Index all videos from disk",
+	Long: `Index all videos from disk.
+
+Every video is queued as an indexing task. Without --no-wait the queue is run
+here until they are done; with it a running "ovacli serve" picks them up.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		currentPath, err := os.Getwd()
 		if err != nil {
@@ -28,6 +30,8 @@ var indexCmd = &cobra.Command{
 		}
 
 		ownerID := repManager.GetRepoOwnerID()
+		cookAfter, _ := cmd.Flags().GetBool("cook")
+		noWait, _ := cmd.Flags().GetBool("no-wait")
 
 		// Step 1: Scan
 		fmt.Println("Scanning disk for videos...")
@@ -43,76 +47,52 @@ var indexCmd = &cobra.Command{
 			return
 		}
 
-		fmt.Printf("Found %d videos. Starting indexing...\n", totalVideos)
-
-		// Step 2: Prepare Logging
-		logDir := repManager.GetLogDir()
-		if err := os.MkdirAll(logDir, 0755); err != nil {
-			fmt.Printf("Failed to create log directory: %v\n", err)
+		// Step 2: Queue
+		tasks := make([]*datatypes.TaskData, 0, totalVideos)
+		for _, videoPath := range videoPaths {
+			task, err := repManager.EnqueueIndexing(videoPath, ownerID, cookAfter, datatypes.TaskPriorityNormal)
+			if err != nil {
+				fmt.Printf("Failed to queue %s: %v\n", videoPath, err)
+				return
+			}
+			tasks = append(tasks, task)
+		}
+		fmt.Printf("Found %d videos, queued for indexing.\n", totalVideos)
+		if noWait {
 			return
 		}
 
-		logFileName := fmt.Sprintf("indexing_%s.log", time.Now().Format("20060102_150405"))
-		logFilePath := filepath.Join(logDir, logFileName)
-		logFile, err := os.Create(logFilePath)
+		// Step 3: Prepare Logging
+		logFile, err := taskLogFile(repManager, "indexing")
 		if err != nil {
 			fmt.Printf("Failed to create log file: %v\n", err)
 			return
 		}
 		defer logFile.Close()
 
-		// Step 3: Setup Channels
-		indexingProgressChan := make(chan int)
-		indexingErrorChan := make(chan error)
-
-		var wg sync.WaitGroup
-		errorCount := 0
-
-		// Handle progress
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for progress := range indexingProgressChan {
-				fmt.Printf("\rIndexing Progress: %3d%%", progress)
-			}
-		}()
-
-		// Handle error logging to file
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for err := range indexingErrorChan {
-				errorCount++
-				// Write error to the file with a timestamp
-				timestamp := time.Now().Format("15:04:05")
-				fmt.Fprintf(logFile, "[%s] ERROR: %v\n", timestamp, err)
-			}
-		}()
-
 		// Step 4: Run Indexing
-		repManager.IndexMultiVideos(
-			videoPaths,
-			ownerID,
-			indexingProgressChan,
-			indexingErrorChan,
-		)
-
-		wg.Wait()
+		finished, err := runQueuedTasks(repManager, tasks, "Indexing", logFile)
+		if err != nil {
+			fmt.Printf("Indexing stopped: %v\n", err)
+			return
+		}
 
 		// Final output
-		fmt.Printf("\rIndexing Progress: 100%%\n\n")
-
+		errorCount := countTasks(finished, datatypes.TaskFailed)
+		fmt.Println()
 		fmt.Println("Indexing Summary")
 		fmt.Println("================")
 		fmt.Printf("Total videos: %d\n", totalVideos)
 		fmt.Printf("Errors encountered: %d\n", errorCount)
 
 		if errorCount > 0 {
-			fmt.Printf("Detailed logs saved to: %s\n", logFilePath)
+			fmt.Printf("Detailed logs saved to: %s\n", logFile.Name())
 		}
 	},
 }
 
 func InitCommandIndex(rootCmd *cobra.Command) {
+	indexCmd.Flags().Bool("cook", false, "Queue cooking of each video once it is indexed")
+	indexCmd.Flags().Bool("no-wait", false, "Only queue the tasks, for a running server to process")
 	rootCmd.AddCommand(indexCmd)
 }
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var recommendationsCmd = &cobra.Command{
+	Use:   "recommendations",
+	Short: "Manage the model behind personal recommendations",
+}
+
+var recommendationsRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Recompute recommendations from every user's history, saved videos and likes",
+	Long: `Recompute recommendations from every user's history, saved videos and likes.
+
+The model is built on first use and then updated as users watch, save and like
+videos, so a rebuild is only needed after removing history or restoring data.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+
+		model, err := repository.RebuildRecommendations()
+		if err != nil {
+			pterm.Error.Printf("Failed to rebuild recommendations: %v\n", err)
+			os.Exit(1)
+		}
+
+		pairs := 0
+		for _, others := range model.Pairs {
+			pairs += len(others)
+		}
+		pterm.Success.Printf("Recommendations rebuilt from %d users, %d videos and %d related pairs\n",
+			len(model.Interactions), len(model.Counts), pairs/2)
+	},
+}
+
+func InitCommandRecommendations(rootCmd *cobra.Command) {
+	recommendationsRebuildCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+	recommendationsCmd.AddCommand(recommendationsRebuildCmd)
+
+	rootCmd.AddCommand(recommendationsCmd)
+}
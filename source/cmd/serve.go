@@ -38,6 +38,11 @@ var serveCmd = &cobra.Command{
 		// Handle Graceful Shutdown
 		handleShutdown(repoManager)
 
+		// Run queued indexing and cooking in the background
+		if err := repoManager.StartTaskRunner(); err != nil {
+			serveLogger.Error("Failed to start task runner: %v", err)
+		}
+
 		// 1. Launch WebSocket Server in a Goroutine (Non-blocking)
 		wsPort := ":8081" // You can also move this to repoConfig
 		wsServer := server.NewWsServer(repoManager, wsPort)
@@ -49,6 +54,17 @@ var serveCmd = &cobra.Command{
 			}
 		}()
 
+		// Serve remote workers once they have a token to authenticate with
+		if repoConfig.WorkerToken != "" {
+			grpcServer := server.NewGrpcServer(repoManager, ":50051")
+			go func() {
+				serveLogger.Info("Launching gRPC server for remote workers on %s", grpcServer.Addr)
+				if err := grpcServer.Run(); err != nil {
+					serveLogger.Error("gRPC server error: %v", err)
+				}
+			}()
+		}
+
 		// 2. Log Info
 		if serveApiOnly {
 			serveLogger.Info("serving API at %s", serverAddr)
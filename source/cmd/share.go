@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"ova-cli/source/internal/repo"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Manage signed share links to videos",
+}
+
+var shareCreateCmd = &cobra.Command{
+	Use:   "create <video-id>",
+	Short: "Create an expiring link that lets anyone with it watch a video",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		accountId := shareAccountID(cmd, repository)
+
+		hours, _ := cmd.Flags().GetInt("expires")
+		start, _ := cmd.Flags().GetFloat64("start")
+		end, _ := cmd.Flags().GetFloat64("end")
+		download, _ := cmd.Flags().GetBool("download")
+		password, _ := cmd.Flags().GetString("password")
+		maxViews, _ := cmd.Flags().GetInt("max-views")
+
+		link, token, err := repository.CreateShareLink(accountId, args[0], repo.ShareLinkOptions{
+			TTL:           time.Duration(hours) * time.Hour,
+			StartSec:      start,
+			EndSec:        end,
+			AllowDownload: download,
+			Password:      password,
+			MaxViews:      maxViews,
+		})
+		if err != nil {
+			pterm.Error.Printf("Failed to create share link: %v\n", err)
+			os.Exit(1)
+		}
+
+		pterm.Success.Printf("Share link %s created, valid until %s\n", link.ID, link.ExpiresAt.Local().Format(time.RFC1123))
+		fmt.Printf("/share/%s\n", token)
+	},
+}
+
+var shareListCmd = &cobra.Command{
+	Use:   "list [video-id]",
+	Short: "List share links, optionally for one video",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		accountId := shareAccountID(cmd, repository)
+
+		videoId := ""
+		if len(args) == 1 {
+			videoId = args[0]
+		}
+		links, err := repository.GetShareLinks(accountId, videoId)
+		if err != nil {
+			pterm.Error.Printf("Error loading share links: %v\n", err)
+			os.Exit(1)
+		}
+
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+		if jsonFlag {
+			for i := range links {
+				links[i].PasswordHash = ""
+			}
+			jsonOutput, err := json.Marshal(links)
+			if err != nil {
+				pterm.Error.Printf("Failed to marshal share links to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonOutput))
+			return
+		}
+
+		if len(links) == 0 {
+			fmt.Println("No share links.")
+			return
+		}
+		fmt.Println("ID\tVideo\tExpires\tViews\tDownload\tPassword\tState")
+		for _, link := range links {
+			state := "active"
+			switch {
+			case link.Revoked:
+				state = "revoked"
+			case link.IsExpired():
+				state = "expired"
+			case link.ViewsExhausted():
+				state = "used up"
+			}
+			views := fmt.Sprintf("%d", link.Views)
+			if link.MaxViews > 0 {
+				views = fmt.Sprintf("%d/%d", link.Views, link.MaxViews)
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\t%t\t%t\t%s\n", link.ID, link.VideoID,
+				link.ExpiresAt.Local().Format("2006-01-02 15:04"), views, link.AllowDownload, link.HasPassword(), state)
+		}
+	},
+}
+
+var shareRevokeCmd = &cobra.Command{
+	Use:   "revoke <link-id>",
+	Short: "Disable a share link",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		accountId := shareAccountID(cmd, repository)
+
+		link, err := repository.RevokeShareLink(accountId, args[0])
+		if err != nil {
+			pterm.Error.Printf("Failed to revoke share link: %v\n", err)
+			os.Exit(1)
+		}
+		pterm.Success.Printf("Share link %s revoked\n", link.ID)
+	},
+}
+
+// shareAccountID returns the account the share commands act as: the --as user,
+// or the repository owner when none is given.
+func shareAccountID(cmd *cobra.Command, repository *repo.RepoManager) string {
+	username, _ := cmd.Flags().GetString("as")
+	if username == "" {
+		return repository.GetRepoOwnerID()
+	}
+	user, err := repository.GetUserByUsername(username)
+	if err != nil {
+		pterm.Error.Printf("User %s not found\n", username)
+		os.Exit(1)
+	}
+	return user.AccountID
+}
+
+// InitCommandShare adds the share commands to rootCmd
+func InitCommandShare(rootCmd *cobra.Command) {
+	shareCreateCmd.Flags().Int("expires", 0, "Hours until the link expires (default: 7 days)")
+	shareCreateCmd.Flags().Float64("start", 0, "Only share the video from this second on")
+	shareCreateCmd.Flags().Float64("end", 0, "Only share the video up to this second")
+	shareCreateCmd.Flags().Bool("download", false, "Allow downloading, not just streaming")
+	shareCreateCmd.Flags().String("password", "", "Ask for this password before playback")
+	shareCreateCmd.Flags().Int("max-views", 0, "Stop working after this many views (0 for unlimited)")
+	shareListCmd.Flags().BoolP("json", "j", false, "Output the data in JSON format")
+
+	for _, c := range []*cobra.Command{shareCreateCmd, shareListCmd, shareRevokeCmd} {
+		c.Flags().String("as", "", "Act as this user (default: the repository owner)")
+		c.Flags().StringP("repository", "r", "", "Specify the repository directory")
+		shareCmd.AddCommand(c)
+	}
+
+	rootCmd.AddCommand(shareCmd)
+}
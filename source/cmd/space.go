@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"ova-cli/source/internal/repo"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var spaceCmd = &cobra.Command{
+	Use:   "space",
+	Short: "Manage space privacy and membership",
+}
+
+var spaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List spaces with their privacy and members",
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+
+		spaces, err := repository.GetSpaces()
+		if err != nil {
+			pterm.Error.Printf("Error loading spaces: %v\n", err)
+			os.Exit(1)
+		}
+
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+		if jsonFlag {
+			jsonOutput, err := json.Marshal(spaces)
+			if err != nil {
+				pterm.Error.Printf("Failed to marshal spaces to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonOutput))
+			return
+		}
+
+		if len(spaces) == 0 {
+			fmt.Println("No space settings, all spaces are open.")
+			return
+		}
+		fmt.Println("Space\tPrivate\tMembers")
+		for _, space := range spaces {
+			members := make([]string, 0, len(space.MemberIds))
+			for _, accountId := range space.MemberIds {
+				if user, err := repository.GetUserByAccountID(accountId); err == nil {
+					members = append(members, user.Username)
+				} else {
+					members = append(members, accountId)
+				}
+			}
+			fmt.Printf("%s\t%t\t%s\n", space.SpaceName, space.SpaceSettings.IsPrivate, strings.Join(members, ","))
+		}
+	},
+}
+
+var spacePrivateCmd = &cobra.Command{
+	Use:   "private <space> <on|off>",
+	Short: "Restrict a space to its members, or open it again",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var private bool
+		switch args[1] {
+		case "on":
+			private = true
+		case "off":
+			private = false
+		default:
+			pterm.Error.Printf("Use on or off, not %q\n", args[1])
+			os.Exit(1)
+		}
+
+		repository := openUserRepository(cmd)
+		if _, err := repository.SetSpacePrivate(args[0], private); err != nil {
+			pterm.Error.Printf("Failed to update space: %v\n", err)
+			os.Exit(1)
+		}
+		if private {
+			pterm.Success.Printf("Space %s is now private to its members\n", args[0])
+		} else {
+			pterm.Success.Printf("Space %s is now open\n", args[0])
+		}
+	},
+}
+
+var spaceAddMemberCmd = &cobra.Command{
+	Use:   "add-member <space> <username>",
+	Short: "Give a user access to a space",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		accountId := spaceMemberAccountID(repository, args[1])
+
+		if _, err := repository.AddSpaceMember(args[0], accountId); err != nil {
+			pterm.Error.Printf("Failed to add member: %v\n", err)
+			os.Exit(1)
+		}
+		pterm.Success.Printf("User %s added to space %s\n", args[1], args[0])
+	},
+}
+
+var spaceRemoveMemberCmd = &cobra.Command{
+	Use:   "remove-member <space> <username>",
+	Short: "Remove a user's access to a space",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		accountId := spaceMemberAccountID(repository, args[1])
+
+		if _, err := repository.RemoveSpaceMember(args[0], accountId); err != nil {
+			pterm.Error.Printf("Failed to remove member: %v\n", err)
+			os.Exit(1)
+		}
+		pterm.Success.Printf("User %s removed from space %s\n", args[1], args[0])
+	},
+}
+
+// spaceMemberAccountID resolves a username to its account ID or exits.
+func spaceMemberAccountID(repository *repo.RepoManager, username string) string {
+	user, err := repository.GetUserByUsername(username)
+	if err != nil {
+		pterm.Error.Printf("User %s not found\n", username)
+		os.Exit(1)
+	}
+	return user.AccountID
+}
+
+// InitCommandSpace adds the space commands to rootCmd
+func InitCommandSpace(rootCmd *cobra.Command) {
+	spaceListCmd.Flags().BoolP("json", "j", false, "Output the data in JSON format")
+
+	for _, c := range []*cobra.Command{spaceListCmd, spacePrivateCmd, spaceAddMemberCmd, spaceRemoveMemberCmd} {
+		c.Flags().StringP("repository", "r", "", "Specify the repository directory")
+		spaceCmd.AddCommand(c)
+	}
+
+	rootCmd.AddCommand(spaceCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+)
+
+// runQueuedTasks runs the task queue in this process until the given tasks are finished,
+// printing their progress under label. Errors of failed tasks go to logFile when it is not
+// nil. It returns the finished tasks. On Ctrl+C the running tasks are queued again for the
+// next runner.
+func runQueuedTasks(repository *repo.RepoManager, tasks []*datatypes.TaskData, label string, logFile *os.File) ([]datatypes.TaskData, error) {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	if err := repository.StartTaskRunner(); err != nil {
+		return nil, err
+	}
+	defer repository.StopTaskRunner()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	finished, err := repository.WaitForTasks(ctx, ids, func(current []datatypes.TaskData) {
+		done, progress := 0, 0
+		speed := ""
+		for _, task := range current {
+			progress += task.Progress
+			if task.IsFinished() {
+				done++
+			}
+			if task.Media != nil && task.Media.Speed > 0 && speed == "" {
+				speed = fmt.Sprintf(", ffmpeg at %.1fx", task.Media.Speed)
+			}
+		}
+		// trailing spaces clear a longer previous line
+		fmt.Printf("\r%s Progress: %3d%% (%d/%d tasks%s)    ", label, progress/max(1, len(current)), done, len(current), speed)
+	})
+	fmt.Println()
+	if err != nil {
+		if ctx.Err() != nil {
+			return finished, fmt.Errorf("interrupted; unfinished tasks stay queued")
+		}
+		return finished, err
+	}
+
+	if logFile != nil {
+		for _, task := range finished {
+			if task.Status == datatypes.TaskFailed {
+				fmt.Fprintf(logFile, "[%s] ERROR: %s %s: %s\n", task.FinishedAt.Local().Format("15:04:05"), task.Type, task.VideoPath, task.Error)
+			}
+		}
+	}
+	return finished, nil
+}
+
+// countTasks returns how many of the tasks ended with the given status.
+func countTasks(tasks []datatypes.TaskData, status datatypes.TaskStatus) int {
+	count := 0
+	for _, task := range tasks {
+		if task.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// taskLogFile creates a timestamped log file for a batch of tasks in the repository log directory.
+func taskLogFile(repository *repo.RepoManager, prefix string) (*os.File, error) {
+	logDir := repository.GetLogDir()
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	name := fmt.Sprintf("%s_%s.log", prefix, time.Now().Format("20060102_150405"))
+	return os.Create(filepath.Join(logDir, name))
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"ova-cli/source/internal/logs"
 	"ova-cli/source/internal/thirdparty"
+	"syscall"
 
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
@@ -26,8 +31,13 @@ var toolsThumbnailCmd = &cobra.Command{
 
 		// Default time
 		timePos, _ := cmd.Flags().GetFloat64("time")
+		width, _ := cmd.Flags().GetInt("width")
+		quality, _ := cmd.Flags().GetInt("quality")
 
-		err := thirdparty.GenerateImageFromVideo(videoPath, thumbnailPath, timePos)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err := thirdparty.GenerateImageFromVideo(ctx, videoPath, thumbnailPath, timePos, width, quality)
 		if err != nil {
 			toolsLogger.Error("Failed to generate thumbnail: %v", err)
 			return
@@ -48,8 +58,16 @@ var toolsPreviewCmd = &cobra.Command{
 
 		startTime, _ := cmd.Flags().GetFloat64("start")
 		duration, _ := cmd.Flags().GetFloat64("duration")
+		width, _ := cmd.Flags().GetInt("width")
+		codec, _ := cmd.Flags().GetString("codec")
+		bitrate, _ := cmd.Flags().GetString("bitrate")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-		err := thirdparty.GenerateWebMFromVideo(videoPath, outputPath, startTime, duration)
+		progress, stopBar := ffmpegProgressBar("Generating preview")
+		err := thirdparty.GenerateWebMFromVideo(ctx, videoPath, outputPath, startTime, duration, width, codec, bitrate, progress)
+		stopBar()
 		if err != nil {
 			toolsLogger.Error("Failed to generate preview: %v", err)
 			return
@@ -60,6 +78,24 @@ var toolsPreviewCmd = &cobra.Command{
 	},
 }
 
+// ffmpegProgressBar shows the live progress of an ffmpeg run on stderr, so stdout stays
+// clean for scripting. Call the returned stop once ffmpeg returned.
+func ffmpegProgressBar(title string) (thirdparty.ProgressFunc, func()) {
+	bar, err := pterm.DefaultProgressbar.WithTotal(100).WithWriter(os.Stderr).WithRemoveWhenDone(true).Start(title)
+	if err != nil {
+		return nil, func() {}
+	}
+	progress := func(p thirdparty.FFmpegProgress) {
+		if step := int(p.Percent()) - bar.Current; step > 0 {
+			bar.Add(step)
+		}
+		if p.Speed > 0 {
+			bar.UpdateTitle(fmt.Sprintf("%s (%.1fx, %.0f fps)", title, p.Speed, p.FPS))
+		}
+	}
+	return progress, func() { _, _ = bar.Stop() }
+}
+
 // GetMP4Info runs mp4info on the provided video path and returns the output as string.
 var toolsInfoCmd = &cobra.Command{
 	Use:   "videoinfo <video-path>",
@@ -68,7 +104,10 @@ var toolsInfoCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		videoPath := args[0]
 
-		info, err := thirdparty.GetMP4Info(videoPath)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		info, err := thirdparty.GetMP4Info(ctx, videoPath)
 		if err != nil {
 			toolsLogger.Error("Failed to get MP4 info: %v", err)
 			return
@@ -86,7 +125,12 @@ func InitCommandTools(rootCmd *cobra.Command) {
 	toolsCmd.AddCommand(toolsInfoCmd)
 
 	toolsThumbnailCmd.Flags().Float64("time", 5.0, "Time position (in seconds) for thumbnail")
+	toolsThumbnailCmd.Flags().Int("width", 320, "Width of the thumbnail; the height keeps the aspect ratio")
+	toolsThumbnailCmd.Flags().Int("quality", 100, "JPEG quality, 1-100")
 
 	toolsPreviewCmd.Flags().Float64("start", 0.0, "Start time (in seconds) for preview")
 	toolsPreviewCmd.Flags().Float64("duration", 5.0, "Duration (in seconds) of preview clip")
+	toolsPreviewCmd.Flags().Int("width", 320, "Width of the clip; the height keeps the aspect ratio")
+	toolsPreviewCmd.Flags().String("codec", "vp8", "WebM video codec: vp8 or vp9")
+	toolsPreviewCmd.Flags().String("bitrate", "500K", "Video bitrate")
 }
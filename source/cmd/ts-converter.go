@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"ova-cli/source/internal/logs"
 	"ova-cli/source/internal/thirdparty"
@@ -23,14 +26,21 @@ var tsConvertCmd = &cobra.Command{
 	Short: "Convert .ts video(s) to MP4 format",
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		// Ctrl+C kills the running ffmpeg and stops the scan
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		if recursive {
 			// Scan current folder & subdirectories
 			err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
 				if err != nil {
 					return err
 				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
 				if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".ts") {
-					convertFile(path)
+					convertFile(ctx, path)
 				}
 				return nil
 			})
@@ -45,12 +55,12 @@ var tsConvertCmd = &cobra.Command{
 			return
 		}
 
-		convertFile(args[0])
+		convertFile(ctx, args[0])
 	},
 }
 
 // convertFile handles a single .ts → .mp4 conversion
-func convertFile(inputPath string) {
+func convertFile(ctx context.Context, inputPath string) {
 	ext := filepath.Ext(inputPath)
 	if strings.ToLower(ext) != ".ts" {
 		tsConvertLogger.Error("File is not a .ts: %s", inputPath)
@@ -59,7 +69,9 @@ func convertFile(inputPath string) {
 
 	outputPath := strings.TrimSuffix(inputPath, ext) + ".mp4"
 
-	err := thirdparty.ConvertToMP4(inputPath, outputPath)
+	progress, stopBar := ffmpegProgressBar("Converting " + filepath.Base(inputPath))
+	err := thirdparty.ConvertToMP4(ctx, inputPath, outputPath, progress)
+	stopBar()
 	if err != nil {
 		tsConvertLogger.Error("Failed to convert %s: %v", inputPath, err)
 		return
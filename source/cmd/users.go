@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/logs"
@@ -84,11 +85,20 @@ var userListCmd = &cobra.Command{
 				return
 			}
 
-			fmt.Println("Username\tRoles\tCreated At")
+			fmt.Println("Username\tRole\tCreated At\tLast Login At")
 			for _, user := range users {
-				fmt.Printf("%s\t%s\n",
+				lastLogin := "(never)"
+				if !user.LastLoginAt.IsZero() {
+					lastLogin = user.LastLoginAt.Format("2006-01-02 15:04:05")
+				}
+				if user.Disabled {
+					lastLogin += " (disabled)"
+				}
+				fmt.Printf("%s\t%s\t%s\t%s\n",
 					user.Username,
+					repository.GetUserRole(&user),
 					user.CreatedAt.Format("2006-01-02 15:04:05"), // Consistent time format
+					lastLogin,
 				)
 			}
 		}
@@ -125,6 +135,10 @@ var userAddCmd = &cobra.Command{
 		if role == "" {
 			role = "user"
 		}
+		if !datatypes.IsValidUserRole(datatypes.UserRole(role)) {
+			pterm.Error.Printf("Unknown role '%s', use 'admin' or 'user'\n", role)
+			os.Exit(1)
+		}
 
 		repository, err := repo.NewRepoManager(repoAddress)
 		if err != nil {
@@ -132,7 +146,12 @@ var userAddCmd = &cobra.Command{
 			return
 		}
 
-		userdata := datatypes.NewUserData(username, password)
+		userdata, err := datatypes.NewUserData(username, password)
+		if err != nil {
+			pterm.Error.Printf("Error adding user '%s': %v\n", username, err)
+			os.Exit(1)
+		}
+		userdata.Role = datatypes.UserRole(role)
 
 		// Create the user using the CreateUser method, which handles hashing and role assignment
 		err = repository.CreateUser(&userdata)
@@ -140,6 +159,7 @@ var userAddCmd = &cobra.Command{
 			pterm.Error.Printf("Error adding user '%s': %v\n", username, err)
 			os.Exit(1)
 		}
+		recordCLIAudit(repository, datatypes.AuditUserCreate, []string{userdata.AccountID}, nil, auditUserSnapshot(repository, &userdata))
 
 		// If --json flag is set, return user data as JSON
 		if jsonFlag {
@@ -183,6 +203,7 @@ var userRmCmd = &cobra.Command{
 			pterm.Error.Printf("Error removing user '%s': %v\n", username, err)
 			os.Exit(1)
 		}
+		recordCLIAudit(repository, datatypes.AuditUserDelete, []string{deletedUser.AccountID}, auditUserSnapshot(repository, deletedUser), nil)
 
 		// Check if --json or -j flag is set
 		jsonFlag, _ := cmd.Flags().GetBool("json")
@@ -239,7 +260,13 @@ var userInfoCmd = &cobra.Command{
 		pterm.Println(strings.Repeat("-", 30))
 
 		pterm.DefaultSection.Println("Username:", user.Username)
+		pterm.DefaultSection.Println("Role:", repository.GetUserRole(user))
 		pterm.DefaultSection.Println("Created At:", user.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+		if user.IsTwoFactorEnabled() {
+			pterm.DefaultSection.Println("Two-Factor: enabled,", len(user.TwoFactor.RecoveryCodeHashes), "recovery codes left")
+		} else {
+			pterm.DefaultSection.Println("Two-Factor: (disabled)")
+		}
 
 		if !user.LastLoginAt.IsZero() {
 			pterm.DefaultSection.Println("Last Login At:", user.LastLoginAt.Format("2006-01-02 15:04:05 MST"))
@@ -263,6 +290,295 @@ var userInfoCmd = &cobra.Command{
 	},
 }
 
+var userUnlockCmd = &cobra.Command{
+	Use:   "unlock [username]",
+	Short: "Lift a login lockout for a username or client IP",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ip, _ := cmd.Flags().GetString("ip")
+		if len(args) == 0 && ip == "" {
+			pterm.Error.Println("Provide a username, an --ip address, or both.")
+			os.Exit(1)
+		}
+
+		// Get the repository address from the --repository flag, or use the current working directory
+		repoAddress, _ := cmd.Flags().GetString("repository")
+		if repoAddress == "" {
+			repoAddress, _ = os.Getwd() // Default to current working directory if no flag is provided
+		}
+
+		repository, err := repo.NewRepoManager(repoAddress)
+		if err != nil {
+			fmt.Println("Failed to initialize repository:", err)
+			return
+		}
+
+		if len(args) == 1 {
+			if err := repository.UnlockLoginUser(args[0]); err != nil {
+				pterm.Warning.Printf("User '%s' is not locked: %v\n", args[0], err)
+			} else {
+				recordCLIAudit(repository, datatypes.AuditLoginUnlock, nil, nil, map[string]string{"username": args[0]})
+				pterm.Success.Printf("Login unlocked for user '%s'.\n", args[0])
+			}
+		}
+
+		if ip != "" {
+			if err := repository.UnlockLoginIP(ip); err != nil {
+				pterm.Warning.Printf("IP '%s' is not locked: %v\n", ip, err)
+			} else {
+				recordCLIAudit(repository, datatypes.AuditLoginUnlock, nil, nil, map[string]string{"ip": ip})
+				pterm.Success.Printf("Login unlocked for IP '%s'.\n", ip)
+			}
+		}
+	},
+}
+
+var userFailuresCmd = &cobra.Command{
+	Use:   "failures [username]",
+	Short: "Show failed login attempts and active lockouts",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		username := ""
+		if len(args) == 1 {
+			username = args[0]
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		// Get the repository address from the --repository flag, or use the current working directory
+		repoAddress, _ := cmd.Flags().GetString("repository")
+		if repoAddress == "" {
+			repoAddress, _ = os.Getwd() // Default to current working directory if no flag is provided
+		}
+
+		repository, err := repo.NewRepoManager(repoAddress)
+		if err != nil {
+			fmt.Println("Failed to initialize repository:", err)
+			return
+		}
+
+		failures, err := repository.GetLoginFailures(username)
+		if err != nil {
+			pterm.Error.Printf("Error loading login failures: %v\n", err)
+			os.Exit(1)
+		}
+		if limit > 0 && len(failures) > limit {
+			failures = failures[:limit]
+		}
+
+		attempts, err := repository.GetLoginAttempts()
+		if err != nil {
+			pterm.Error.Printf("Error loading login attempts: %v\n", err)
+			os.Exit(1)
+		}
+
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+		if jsonFlag {
+			jsonOutput, err := json.Marshal(map[string]interface{}{
+				"failures": failures,
+				"attempts": attempts,
+			})
+			if err != nil {
+				pterm.Error.Printf("Failed to marshal login failures to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonOutput))
+			return
+		}
+
+		pterm.DefaultSection.Println("Active lockouts")
+		locked := 0
+		for _, attempt := range attempts {
+			if time.Now().Before(attempt.BlockedUntil) {
+				fmt.Printf("%s\t%d failures\tblocked until %s\n",
+					attempt.Key,
+					attempt.Failures,
+					attempt.BlockedUntil.Local().Format("2006-01-02 15:04:05"),
+				)
+				locked++
+			}
+		}
+		if locked == 0 {
+			fmt.Println("No active lockouts.")
+		}
+
+		pterm.DefaultSection.Println("Recent failed logins")
+		if len(failures) == 0 {
+			fmt.Println("No failed logins recorded.")
+			return
+		}
+		fmt.Println("Time\tUsername\tIP\tReason")
+		for _, f := range failures {
+			fmt.Printf("%s\t%s\t%s\t%s\n",
+				f.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+				f.Username,
+				f.IP,
+				f.Reason,
+			)
+		}
+	},
+}
+
+var userTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage personal API tokens for a user",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var userTokenCreateCmd = &cobra.Command{
+	Use:   "create <username> <token-name>",
+	Short: "Create a personal API token (printed once)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		username, name := args[0], args[1]
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		days, _ := cmd.Flags().GetInt("days")
+
+		repository := openUserRepository(cmd)
+		user, err := repository.GetUserByUsername(username)
+		if err != nil {
+			pterm.Error.Printf("Error retrieving user '%s': %v\n", username, err)
+			os.Exit(1)
+		}
+
+		tokenScopes := make([]datatypes.TokenScope, 0, len(scopes))
+		for _, scope := range scopes {
+			tokenScopes = append(tokenScopes, datatypes.TokenScope(strings.TrimSpace(scope)))
+		}
+
+		token, plain, err := repository.CreateAPIToken(user.AccountID, name, tokenScopes, time.Duration(days)*24*time.Hour)
+		if err != nil {
+			pterm.Error.Printf("Error creating token: %v\n", err)
+			os.Exit(1)
+		}
+		recordCLIAudit(repository, datatypes.AuditTokenCreate, []string{token.ID}, nil, map[string]interface{}{
+			"accountId": user.AccountID,
+			"name":      token.Name,
+			"scopes":    token.Scopes,
+			"expiresAt": token.ExpiresAt,
+		})
+
+		pterm.Success.Printf("Created token '%s' (%s) for user '%s'\n", token.Name, token.ID, username)
+		fmt.Printf("Scopes: %v\nExpires At: %s\n", token.Scopes, token.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+		pterm.Warning.Println("Copy the token now, it will not be shown again:")
+		fmt.Println(plain)
+	},
+}
+
+var userTokenListCmd = &cobra.Command{
+	Use:   "list <username>",
+	Short: "List personal API tokens of a user",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		user, err := repository.GetUserByUsername(args[0])
+		if err != nil {
+			pterm.Error.Printf("Error retrieving user '%s': %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		tokens, err := repository.GetAPITokens(user.AccountID)
+		if err != nil {
+			pterm.Error.Printf("Error loading tokens: %v\n", err)
+			os.Exit(1)
+		}
+		if len(tokens) == 0 {
+			fmt.Println("No tokens found.")
+			return
+		}
+
+		fmt.Println("ID\tName\tScopes\tExpires At\tLast Used")
+		for _, t := range tokens {
+			lastUsed := "(never)"
+			if !t.LastUsedAt.IsZero() {
+				lastUsed = t.LastUsedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%s\t%s\t%v\t%s\t%s\n",
+				t.ID,
+				t.Name,
+				t.Scopes,
+				t.ExpiresAt.Local().Format("2006-01-02 15:04:05"),
+				lastUsed,
+			)
+		}
+	},
+}
+
+var userTokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <username> <token-id>",
+	Short: "Revoke a personal API token",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		user, err := repository.GetUserByUsername(args[0])
+		if err != nil {
+			pterm.Error.Printf("Error retrieving user '%s': %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		if err := repository.RevokeAPIToken(user.AccountID, args[1]); err != nil {
+			pterm.Error.Printf("Error revoking token: %v\n", err)
+			os.Exit(1)
+		}
+		recordCLIAudit(repository, datatypes.AuditTokenRevoke, []string{args[1]}, nil, nil)
+		pterm.Success.Printf("Token '%s' revoked.\n", args[1])
+	},
+}
+
+var userResetTwoFactorCmd = &cobra.Command{
+	Use:   "reset-2fa <username>",
+	Short: "Remove two-factor authentication from a user who lost their device",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repository := openUserRepository(cmd)
+		user, err := repository.GetUserByUsername(args[0])
+		if err != nil {
+			pterm.Error.Printf("Error retrieving user '%s': %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		if err := repository.DisableTwoFactor(user.AccountID); err != nil {
+			pterm.Error.Printf("Error resetting two-factor for '%s': %v\n", args[0], err)
+			os.Exit(1)
+		}
+		recordCLIAudit(repository, datatypes.AuditTwoFactorDisable, []string{user.AccountID}, nil, nil)
+		pterm.Success.Printf("Two-factor authentication removed for user '%s'.\n", args[0])
+	},
+}
+
+// recordCLIAudit logs an admin action taken from the command line. A failure is only
+// reported, the action itself already happened.
+func recordCLIAudit(repository *repo.RepoManager, action string, targetIds []string, before, after any) {
+	if err := repository.RecordCLIAudit(action, targetIds, before, after); err != nil {
+		pterm.Warning.Printf("Failed to write audit event: %v\n", err)
+	}
+}
+
+// auditUserSnapshot describes an account for the audit log without its secrets.
+func auditUserSnapshot(repository *repo.RepoManager, user *datatypes.UserData) map[string]interface{} {
+	return map[string]interface{}{
+		"accountId": user.AccountID,
+		"username":  user.Username,
+		"role":      repository.GetUserRole(user),
+	}
+}
+
+// openUserRepository opens the repository named by --repository, or the current directory.
+func openUserRepository(cmd *cobra.Command) *repo.RepoManager {
+	repoAddress, _ := cmd.Flags().GetString("repository")
+	if repoAddress == "" {
+		repoAddress, _ = os.Getwd() // Default to current working directory if no flag is provided
+	}
+
+	repository, err := repo.NewRepoManager(repoAddress)
+	if err != nil {
+		fmt.Println("Failed to initialize repository:", err)
+		os.Exit(1)
+	}
+	return repository
+}
+
 // InitCommandUsers adds user-related commands to rootCmd
 func InitCommandUsers(rootCmd *cobra.Command) {
 
@@ -280,10 +596,31 @@ func InitCommandUsers(rootCmd *cobra.Command) {
 	userRmCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
 	userRmCmd.Flags().BoolP("json", "j", false, "Return output in JSON format")
 
+	userUnlockCmd.Flags().String("ip", "", "Also unlock this client IP address")
+	userUnlockCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+
+	userFailuresCmd.Flags().Int("limit", 50, "Maximum number of failed logins to show (0 for all)")
+	userFailuresCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+	userFailuresCmd.Flags().BoolP("json", "j", false, "Output the data in JSON format")
+
+	userTokenCreateCmd.Flags().StringSlice("scope", []string{"read"}, "Token scopes: read, write, upload")
+	userTokenCreateCmd.Flags().Int("days", 90, "Days until the token expires")
+	userTokenCmd.PersistentFlags().StringP("repository", "r", "", "Specify the repository directory")
+	userTokenCmd.AddCommand(userTokenCreateCmd)
+	userTokenCmd.AddCommand(userTokenListCmd)
+	userTokenCmd.AddCommand(userTokenRevokeCmd)
+
+	userInfoCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+	userResetTwoFactorCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+
 	userCmd.AddCommand(userListCmd)
 	userCmd.AddCommand(userAddCmd)
 	userCmd.AddCommand(userRmCmd)
 	userCmd.AddCommand(userInfoCmd)
+	userCmd.AddCommand(userUnlockCmd)
+	userCmd.AddCommand(userFailuresCmd)
+	userCmd.AddCommand(userTokenCmd)
+	userCmd.AddCommand(userResetTwoFactorCmd)
 
 	rootCmd.AddCommand(userCmd)
 }
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"ova-cli/source/internal/datastorage/jsondb"
@@ -57,8 +60,12 @@ var videoAddOneCmd = &cobra.Command{
 		// Get the value of the --cook flag
 		cook, _ := cmd.Flags().GetBool("cook")
 
+		// Ctrl+C stops the running ffmpeg instead of leaving it behind
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		// Use AddOneVideo to add the single video
-		err = repository.AddOneVideo(absPath, repository.GetRepoOwnerID(), cook)
+		err = repository.AddOneVideo(ctx, absPath, repository.GetRepoOwnerID(), cook)
 		if err != nil {
 			fmt.Println("Failed to add video:", err)
 			return
@@ -186,6 +193,33 @@ var videoInfoCmd = &cobra.Command{
 	},
 }
 
+var videoVisibilityCmd = &cobra.Command{
+	Use:   "visibility <video-id> <public|private>",
+	Short: "Make a video public or private",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		videoID := args[0]
+
+		var isPublic bool
+		switch args[1] {
+		case "public":
+			isPublic = true
+		case "private":
+			isPublic = false
+		default:
+			pterm.Error.Printf("Unknown visibility %q, use public or private\n", args[1])
+			os.Exit(1)
+		}
+
+		repository := openUserRepository(cmd)
+		if _, err := repository.SetVideoVisibility(videoID, isPublic); err != nil {
+			pterm.Error.Printf("Failed to update video: %v\n", err)
+			os.Exit(1)
+		}
+		pterm.Success.Printf("Video %s is now %s\n", videoID, args[1])
+	},
+}
+
 func InitCommandVideo(rootCmd *cobra.Command) {
 
 	videoAddOneCmd.Flags().Bool("cook", false, "Cook video after adding (default: false)")
@@ -195,5 +229,8 @@ func InitCommandVideo(rootCmd *cobra.Command) {
 	videoCmd.AddCommand(videoInfoCmd)
 	videoCmd.AddCommand(videoRemoveCmd)
 
+	videoVisibilityCmd.Flags().StringP("repository", "r", "", "Specify the repository directory")
+	videoCmd.AddCommand(videoVisibilityCmd)
+
 	rootCmd.AddCommand(videoCmd)
 }
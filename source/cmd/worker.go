@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"ova-cli/source/internal/worker"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Run cooking jobs for a remote server over gRPC",
+	Long: `Run cooking jobs for a remote server over gRPC.
+
+The worker leases jobs from the server's task queue, cooks them here and
+uploads the results. It reports its CPU and RAM use, and the server stops
+giving it jobs while it is busy. A video is read at the path the server knows
+it by when the worker sees the same file there, and downloaded otherwise.
+The token is the one set on the server with "ovacli config worker-token".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		server, _ := cmd.Flags().GetString("server")
+		token, _ := cmd.Flags().GetString("token")
+		id, _ := cmd.Flags().GetString("id")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		transfer, _ := cmd.Flags().GetString("transfer")
+
+		w, err := worker.New(worker.Options{Server: server, Token: token, ID: id, Jobs: jobs, Transfer: transfer})
+		if err != nil {
+			pterm.Error.Println(err)
+			os.Exit(1)
+		}
+		defer w.Close()
+
+		// A running job is handed back to the server on interrupt
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		pterm.Info.Printf("Worker %s serving %s, press Ctrl+C to stop\n", w.ID(), server)
+		if err := w.Run(ctx); err != nil {
+			pterm.Error.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func InitCommandWorker(rootCmd *cobra.Command) {
+	workerCmd.Flags().String("server", "127.0.0.1:50051", "host:port of the server's gRPC service")
+	workerCmd.Flags().String("token", "", "Worker token of the repository")
+	workerCmd.Flags().String("id", "", "Name of this worker (default hostname-pid)")
+	workerCmd.Flags().Int("jobs", 1, "Jobs run at once")
+	workerCmd.Flags().String("transfer", worker.TransferAuto, "Download videos from the server: auto (when not found at the server's path), always or never")
+	rootCmd.AddCommand(workerCmd)
+}
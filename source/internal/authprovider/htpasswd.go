@@ -0,0 +1,195 @@
+package authprovider
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"ova-cli/source/internal/datatypes"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HtpasswdProvider checks passwords against an Apache htpasswd file.
+// Supported hashes are bcrypt (htpasswd -B), APR1 MD5 (the htpasswd default) and {SHA}.
+// The file is re-read whenever its modification time changes.
+type HtpasswdProvider struct {
+	cfg datatypes.AuthProviderConfig
+
+	mu      sync.Mutex
+	modTime time.Time
+	entries map[string]string // username -> hash
+}
+
+func NewHtpasswdProvider(cfg datatypes.AuthProviderConfig) (*HtpasswdProvider, error) {
+	if cfg.HtpasswdFile == "" {
+		return nil, fmt.Errorf("auth provider %q: htpasswdFile is required", cfg.Name)
+	}
+	p := &HtpasswdProvider{cfg: cfg}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *HtpasswdProvider) Name() string                    { return p.cfg.Name }
+func (p *HtpasswdProvider) Type() string                    { return datatypes.AuthProviderHtpasswd }
+func (p *HtpasswdProvider) DefaultRole() datatypes.UserRole { return defaultRole(p.cfg) }
+
+func (p *HtpasswdProvider) Authenticate(username, password string) (*Identity, error) {
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	hash, ok := p.entries[username]
+	p.mu.Unlock()
+
+	if !ok || !verifyHtpasswdHash(hash, password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Identity{
+		Provider:    p.cfg.Name,
+		Subject:     username,
+		Username:    username,
+		DisplayName: username,
+	}, nil
+}
+
+// reload parses the file again if it changed since the last read.
+func (p *HtpasswdProvider) reload() error {
+	info, err := os.Stat(p.cfg.HtpasswdFile)
+	if err != nil {
+		return fmt.Errorf("auth provider %q: %w", p.cfg.Name, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.entries != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	file, err := os.Open(p.cfg.HtpasswdFile)
+	if err != nil {
+		return fmt.Errorf("auth provider %q: %w", p.cfg.Name, err)
+	}
+	defer file.Close()
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		entries[user] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("auth provider %q: %w", p.cfg.Name, err)
+	}
+
+	p.entries = entries
+	p.modTime = info.ModTime()
+	return nil
+}
+
+func verifyHtpasswdHash(hash, password string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$2y$"), strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, "$apr1$"):
+		parts := strings.SplitN(hash, "$", 4)
+		if len(parts) != 4 {
+			return false
+		}
+		expected := apr1Crypt(password, parts[2])
+		return subtle.ConstantTimeCompare([]byte(expected), []byte(hash)) == 1
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		expected := "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+		return subtle.ConstantTimeCompare([]byte(expected), []byte(hash)) == 1
+	}
+	return false
+}
+
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apr1Crypt implements Apache's MD5-based "$apr1$" password hash.
+func apr1Crypt(password, salt string) string {
+	const magic = "$apr1$"
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+	pw := []byte(password)
+
+	alt := md5.New()
+	alt.Write(pw)
+	alt.Write([]byte(salt))
+	alt.Write(pw)
+	altSum := alt.Sum(nil)
+
+	ctx := md5.New()
+	ctx.Write(pw)
+	ctx.Write([]byte(magic))
+	ctx.Write([]byte(salt))
+	for i := len(pw); i > 0; i -= 16 {
+		ctx.Write(altSum[:min(i, 16)])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(pw[:1])
+		}
+	}
+	sum := ctx.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 == 1 {
+			round.Write(pw)
+		} else {
+			round.Write(sum)
+		}
+		if i%3 != 0 {
+			round.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			round.Write(pw)
+		}
+		if i&1 == 1 {
+			round.Write(sum)
+		} else {
+			round.Write(pw)
+		}
+		sum = round.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString(magic + salt + "$")
+	encode := func(a, b, c byte, n int) {
+		v := uint(a)<<16 | uint(b)<<8 | uint(c)
+		for ; n > 0; n-- {
+			out.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	encode(sum[0], sum[6], sum[12], 4)
+	encode(sum[1], sum[7], sum[13], 4)
+	encode(sum[2], sum[8], sum[14], 4)
+	encode(sum[3], sum[9], sum[15], 4)
+	encode(sum[4], sum[10], sum[5], 4)
+	encode(0, 0, sum[11], 2)
+	return out.String()
+}
@@ -0,0 +1,40 @@
+package authprovider
+
+import (
+	"ova-cli/source/internal/datatypes"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LocalProvider checks passwords against the bcrypt hashes in the repository's user store.
+type LocalProvider struct {
+	cfg   datatypes.AuthProviderConfig
+	users UserStore
+}
+
+func NewLocalProvider(cfg datatypes.AuthProviderConfig, users UserStore) *LocalProvider {
+	return &LocalProvider{cfg: cfg, users: users}
+}
+
+func (p *LocalProvider) Name() string                    { return p.cfg.Name }
+func (p *LocalProvider) Type() string                    { return datatypes.AuthProviderLocal }
+func (p *LocalProvider) DefaultRole() datatypes.UserRole { return defaultRole(p.cfg) }
+
+// Authenticate accepts only users that were created locally, not ones provisioned from other providers.
+func (p *LocalProvider) Authenticate(username, password string) (*Identity, error) {
+	user, err := p.users.GetUserByUsername(username)
+	if err != nil || user.AuthProvider != "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Identity{
+		Provider:    p.cfg.Name,
+		Subject:     user.AccountID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+	}, nil
+}
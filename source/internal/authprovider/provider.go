@@ -0,0 +1,74 @@
+// Package authprovider defines the identity sources a user can log in with:
+// the local user store, an Apache htpasswd file and OpenID Connect issuers.
+package authprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+)
+
+// ErrInvalidCredentials is returned when a provider does not accept a username and password.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// Identity is a user as reported by a provider.
+type Identity struct {
+	Provider    string // Name of the provider that authenticated the user
+	Subject     string // Stable user ID within the provider
+	Username    string
+	DisplayName string
+	Email       string
+}
+
+// Provider is an identity source configured for the repository.
+type Provider interface {
+	Name() string
+	Type() string
+	// DefaultRole is assigned to users this provider auto-provisions.
+	DefaultRole() datatypes.UserRole
+}
+
+// PasswordProvider checks a username and password directly.
+type PasswordProvider interface {
+	Provider
+	Authenticate(username, password string) (*Identity, error)
+}
+
+// RedirectProvider authenticates through a browser redirect (authorization code flow).
+type RedirectProvider interface {
+	Provider
+	AuthCodeURL(ctx context.Context, state, nonce string) (string, error)
+	Exchange(ctx context.Context, code, nonce string) (*Identity, error)
+}
+
+// UserStore is the subset of the repository the local provider needs.
+type UserStore interface {
+	GetUserByUsername(username string) (*datatypes.UserData, error)
+}
+
+// New builds a provider from its configuration.
+func New(cfg datatypes.AuthProviderConfig, users UserStore) (Provider, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("auth provider name is required")
+	}
+
+	switch cfg.Type {
+	case datatypes.AuthProviderLocal:
+		return NewLocalProvider(cfg, users), nil
+	case datatypes.AuthProviderHtpasswd:
+		return NewHtpasswdProvider(cfg)
+	case datatypes.AuthProviderOIDC:
+		return NewOIDCProvider(cfg)
+	default:
+		return nil, fmt.Errorf("unknown auth provider type: %s", cfg.Type)
+	}
+}
+
+// defaultRole falls back to RoleUser when the config leaves the role empty.
+func defaultRole(cfg datatypes.AuthProviderConfig) datatypes.UserRole {
+	if cfg.DefaultRole == "" {
+		return datatypes.RoleUser
+	}
+	return cfg.DefaultRole
+}
@@ -1,6 +1,9 @@
 package datastorage
 
-import "ova-cli/source/internal/datatypes"
+import (
+	"ova-cli/source/internal/datatypes"
+	"time"
+)
 
 // DiskDataStorage defines methods for user and video data operations without context.
 type DiskDataStorage interface {
@@ -12,6 +15,34 @@ type DiskDataStorage interface {
 	GetUserByUsername(username string) (*datatypes.UserData, error) // slower than by account Id
 	GetUserByAccountID(accountId string) (*datatypes.UserData, error)
 	GetAllUsers() ([]datatypes.UserData, error)
+	UpdateUser(userData datatypes.UserData) error
+
+	// Login brute-force tracking
+	GetLoginAttempt(key string) (*datatypes.LoginAttemptData, error)
+	SaveLoginAttempt(attempt datatypes.LoginAttemptData) error
+	DeleteLoginAttempt(key string) error
+	GetAllLoginAttempts() ([]datatypes.LoginAttemptData, error)
+	InsertLoginFailure(event datatypes.LoginFailureEvent) error
+	GetLoginFailures(username string) ([]datatypes.LoginFailureEvent, error)
+
+	// Personal API tokens
+	InsertAPIToken(token *datatypes.APITokenData) error
+	GetAPITokenByID(tokenId string) (*datatypes.APITokenData, error)
+	GetAPITokensByAccountID(accountId string) ([]datatypes.APITokenData, error)
+	DeleteAPIToken(accountId, tokenId string) error
+	UpdateAPITokenLastUsed(tokenId string, usedAt time.Time) error
+
+	// Share links
+	InsertShareLink(link *datatypes.ShareLinkData) error
+	GetShareLinkByID(linkId string) (*datatypes.ShareLinkData, error)
+	GetShareLinks(videoId, createdBy string) ([]datatypes.ShareLinkData, error)
+	RevokeShareLink(linkId string) error
+	AddShareLinkView(linkId string) (*datatypes.ShareLinkData, error)
+
+	// Audit log
+	AppendAuditEvent(event datatypes.AuditEvent) error
+	QueryAuditEvents(filter datatypes.AuditFilter) ([]datatypes.AuditEvent, error)
+	PruneAuditEvents(cutoff time.Time) (int, error)
 
 	// User favorites management
 	GetSavedVideosByAccountId(accountId string) ([]string, error)
@@ -27,6 +58,16 @@ type DiskDataStorage interface {
 	UpdateUserPassword(accountId, newHashedPassword string) error
 	GetPlaylistVideoIDsPaginated(accountId, playlistId string, page, limit int) ([]string, int, error)
 	GetPlaylistsByUser(accountId string) ([]datatypes.PlaylistData, error)
+	GetPlaylist(playlistId string) (*datatypes.PlaylistData, error)
+	UpdatePlaylistPrivacy(accountId, playlistId string, privacy datatypes.PrivacySetting) error
+	GetAllPlaylists() ([]datatypes.PlaylistData, error)
+	UpdatePlaylistShares(accountId, playlistId string, shares []datatypes.PlaylistShare) error
+	SavePlaylist(playlist datatypes.PlaylistData) error
+
+	// Space settings and membership
+	GetSpace(spaceName string) (*datatypes.SpaceData, error)
+	GetAllSpaces() ([]datatypes.SpaceData, error)
+	SaveSpace(space datatypes.SpaceData) error
 
 	GetTotalVideoCount() (int, error)
 
@@ -37,12 +78,46 @@ type DiskDataStorage interface {
 
 	InsertVideoLookup(videoId string, vidoePath string) error
 	GetVideoLookup(videoId string) (string, error)
+	GetAllVideoLookups() (map[string]string, error)
 
 	// New method to add video to user's watched list
 	GetUserWatchedVideos(accountId string) ([]string, error)
 	AddVideoToWatched(accountId, videoID string) error
+	RemoveVideosFromWatched(accountId string, videoIds []string) error
 	ClearUserWatchedHistory(accountId string) error
 
+	// View and download accounting
+	CountVideoView(videoId, viewerKey string, at time.Time, window time.Duration) (bool, error)
+	CountVideoDownload(videoId string, at time.Time) error
+	GetDailyVideoStats(since time.Time) (map[string]map[string]datatypes.VideoDailyStats, error)
+	SetVideoLike(videoId, accountId string, liked bool, at time.Time) (int, error)
+	GetAllVideoLikes() (map[string]map[string]time.Time, error)
+
+	// Background tasks
+	InsertTask(task datatypes.TaskData) error
+	UpdateTask(previous, task datatypes.TaskData) error // datatypes.ErrTaskChanged when the stored task is no longer previous
+	GetTaskByID(taskId string) (*datatypes.TaskData, error)
+	GetAllTasks() ([]datatypes.TaskData, error)
+	DeleteFinishedTasks(before time.Time) (int, error)
+
+	// Item-to-item recommendation model
+	GetRecommendationModel() (*datatypes.RecommendationModel, error)
+	SaveRecommendationModel(model *datatypes.RecommendationModel) error
+	AddRecommendationInteraction(accountId, videoId string) error
+
+	// Watch history, one entry per sitting
+	RecordWatchEvent(accountId, videoId string, at time.Time, watchedSec float64) (*datatypes.WatchEvent, error)
+	GetWatchEvents(accountId string, since, until time.Time) ([]datatypes.WatchEvent, error)
+	DeleteWatchEvent(accountId, eventId string) error
+	DeleteWatchEvents(accountId string, since, until time.Time) (int, error)
+
+	// Playback position per user and video
+	SavePlaybackState(accountId string, state datatypes.PlaybackState) error
+	GetPlaybackState(accountId, videoId string) (*datatypes.PlaybackState, error)
+	GetPlaybackStates(accountId string) ([]datatypes.PlaybackState, error)
+	DeletePlaybackState(accountId, videoId string) error
+	ClearPlaybackStates(accountId string) error
+
 	// Video tags management
 	AddTagToVideo(videoId, tag string) error
 	RemoveTagFromVideo(videoId, tag string) error
@@ -51,6 +126,7 @@ type DiskDataStorage interface {
 	InsertVideo(video datatypes.VideoData) error
 	GetAllVideos() ([]datatypes.VideoData, error)
 	GetVideoByID(videoId string) (*datatypes.VideoData, error)
+	UpdateVideo(videoData datatypes.VideoData) error
 	DeleteVideoByID(videoId string) error
 	DeleteAllVideos() error
 
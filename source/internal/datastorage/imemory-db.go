@@ -5,6 +5,7 @@ type SessionDataStorage interface {
 	AddSession(sessionID string, accountId string) error
 	GetSession(sessionID string) (string, error)
 	DeleteSession(sessionID string) error
+	DeleteSessionsByAccountID(accountId string) (int, error)
 	SaveOnDisk() error
 	LoadFromDisk() error
 	ClearAllSessions() error
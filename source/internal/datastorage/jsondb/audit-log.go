@@ -0,0 +1,133 @@
+package jsondb
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"ova-cli/source/internal/datatypes"
+	"path/filepath"
+	"time"
+)
+
+// The audit log is stored as JSON Lines so that new events are appended
+// without rewriting the file. Only retention pruning rewrites it.
+
+// AppendAuditEvent appends one event to the audit log.
+func (s *JsonDB) AppendAuditEvent(event datatypes.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.getAuditLogFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// QueryAuditEvents returns matching events, newest first.
+func (s *JsonDB) QueryAuditEvents(filter datatypes.AuditFilter) ([]datatypes.AuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := []datatypes.AuditEvent{}
+	err := s.scanAuditLog(func(event datatypes.AuditEvent) {
+		if filter.Matches(event) {
+			events = append(events, event)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The file is in chronological order, so reverse for newest first
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+	return events, nil
+}
+
+// PruneAuditEvents drops events older than cutoff and returns how many were removed.
+func (s *JsonDB) PruneAuditEvents(cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []datatypes.AuditEvent
+	removed := 0
+	err := s.scanAuditLog(func(event datatypes.AuditEvent) {
+		if event.Time.Before(cutoff) {
+			removed++
+			return
+		}
+		kept = append(kept, event)
+	})
+	if err != nil || removed == 0 {
+		return 0, err
+	}
+
+	// Write to a temp file first so a crash never leaves a half-written log
+	path := s.getAuditLogFilePath()
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, event := range kept {
+		if err := encoder.Encode(event); err != nil {
+			file.Close()
+			return 0, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// scanAuditLog calls fn for every event in file order. Unreadable lines are skipped.
+func (s *JsonDB) scanAuditLog(fn func(event datatypes.AuditEvent)) error {
+	file, err := os.Open(s.getAuditLogFilePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event datatypes.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		fn(event)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,35 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+func (s *JsonDB) loadAPITokens() (map[string]datatypes.APITokenData, error) {
+	path := s.getAPITokensFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens map[string]datatypes.APITokenData
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *JsonDB) saveAPITokens(tokens map[string]datatypes.APITokenData) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getAPITokensFilePath(), data, 0600)
+}
@@ -0,0 +1,64 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+func (s *JsonDB) loadLoginAttempts() (map[string]datatypes.LoginAttemptData, error) {
+	path := s.getLoginAttemptsFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts map[string]datatypes.LoginAttemptData
+	if err := json.Unmarshal(data, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (s *JsonDB) saveLoginAttempts(attempts map[string]datatypes.LoginAttemptData) error {
+	data, err := json.MarshalIndent(attempts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getLoginAttemptsFilePath(), data, 0644)
+}
+
+// loadLoginFailures loads failed-login events grouped by the attempted username.
+func (s *JsonDB) loadLoginFailures() (map[string][]datatypes.LoginFailureEvent, error) {
+	path := s.getLoginFailuresFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures map[string][]datatypes.LoginFailureEvent
+	if err := json.Unmarshal(data, &failures); err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
+
+func (s *JsonDB) saveLoginFailures(failures map[string][]datatypes.LoginFailureEvent) error {
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getLoginFailuresFilePath(), data, 0644)
+}
@@ -0,0 +1,36 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+// loadPlayback returns the playback states keyed by account ID, then video ID.
+func (s *JsonDB) loadPlayback() (map[string]map[string]datatypes.PlaybackState, error) {
+	path := s.getPlaybackFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var states map[string]map[string]datatypes.PlaybackState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (s *JsonDB) savePlayback(states map[string]map[string]datatypes.PlaybackState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getPlaybackFilePath(), data, 0644)
+}
@@ -0,0 +1,35 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+func (s *JsonDB) loadRecommendationModel() (*datatypes.RecommendationModel, error) {
+	path := s.getRecommendationsFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	model := datatypes.NewRecommendationModel()
+	if err := json.Unmarshal(data, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (s *JsonDB) saveRecommendationModel(model *datatypes.RecommendationModel) error {
+	data, err := json.Marshal(model) // Not indented, the pair counts get large
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getRecommendationsFilePath(), data, 0644)
+}
@@ -0,0 +1,35 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+func (s *JsonDB) loadShareLinks() (map[string]datatypes.ShareLinkData, error) {
+	path := s.getShareLinksFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var links map[string]datatypes.ShareLinkData
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (s *JsonDB) saveShareLinks(links map[string]datatypes.ShareLinkData) error {
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getShareLinksFilePath(), data, 0600)
+}
@@ -0,0 +1,63 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+// withTasksLock runs fn holding s.mu and a lock on tasks.lock. serve, `ovacli index` and
+// `ovacli cook` can run task runners on the same repository at once, so every access to
+// tasks.json is serialized across processes and not only within this one.
+func (s *JsonDB) withTasksLock(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.storageDir, 0755); err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(s.getTasksLockFilePath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open task lock: %w", err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock tasks: %w", err)
+	}
+	defer unlockFile(lock)
+
+	return fn()
+}
+
+// loadTasks returns all tasks keyed by task ID.
+func (s *JsonDB) loadTasks() (map[string]datatypes.TaskData, error) {
+	path := s.getTasksFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks map[string]datatypes.TaskData
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = map[string]datatypes.TaskData{}
+	}
+	return tasks, nil
+}
+
+func (s *JsonDB) saveTasks(tasks map[string]datatypes.TaskData) error {
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getTasksFilePath(), data, 0644)
+}
@@ -0,0 +1,36 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// loadVideoLikes returns when each account liked each video, keyed by video ID, then account ID.
+func (s *JsonDB) loadVideoLikes() (map[string]map[string]time.Time, error) {
+	path := s.getVideoLikesFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var likes map[string]map[string]time.Time
+	if err := json.Unmarshal(data, &likes); err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
+
+func (s *JsonDB) saveVideoLikes(likes map[string]map[string]time.Time) error {
+	data, err := json.MarshalIndent(likes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getVideoLikesFilePath(), data, 0644)
+}
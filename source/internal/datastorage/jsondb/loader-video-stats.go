@@ -0,0 +1,48 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+	"time"
+)
+
+// videoStatsFile is the layout of video-stats.json.
+type videoStatsFile struct {
+	Daily     map[string]map[string]datatypes.VideoDailyStats `json:"daily"`     // Day, then video ID
+	LastViews map[string]time.Time                            `json:"lastViews"` // Last counted view per viewer and video
+}
+
+func (s *JsonDB) loadVideoStats() (*videoStatsFile, error) {
+	path := s.getVideoStatsFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats videoStatsFile
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+	if stats.Daily == nil {
+		stats.Daily = map[string]map[string]datatypes.VideoDailyStats{}
+	}
+	if stats.LastViews == nil {
+		stats.LastViews = map[string]time.Time{}
+	}
+	return &stats, nil
+}
+
+func (s *JsonDB) saveVideoStats(stats *videoStatsFile) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getVideoStatsFilePath(), data, 0644)
+}
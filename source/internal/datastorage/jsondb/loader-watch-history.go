@@ -0,0 +1,36 @@
+package jsondb
+
+import (
+	"encoding/json"
+	"os"
+	"ova-cli/source/internal/datatypes"
+)
+
+// loadWatchHistory returns the watch history keyed by account ID, oldest entry first.
+func (s *JsonDB) loadWatchHistory() (map[string][]datatypes.WatchEvent, error) {
+	path := s.getWatchHistoryFilePath()
+
+	// Ensure file exists with "{}" if missing
+	if err := s.createEmptyJSONFileIfMissing(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var history map[string][]datatypes.WatchEvent
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *JsonDB) saveWatchHistory(history map[string][]datatypes.WatchEvent) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.getWatchHistoryFilePath(), data, 0644)
+}
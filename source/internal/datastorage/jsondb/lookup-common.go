@@ -56,3 +56,8 @@ func (jsdb *JsonDB) DeleteVideoLookup(videoId string) error {
 	// Save the updated map
 	return jsdb.SaveLookupCollection(allLookups)
 }
+
+// GetAllVideoLookups returns the full video ID to relative path table.
+func (jsdb *JsonDB) GetAllVideoLookups() (map[string]string, error) {
+	return jsdb.LoadLookupCollection()
+}
@@ -33,3 +33,55 @@ func (s *JsonDB) getSavedCollectionFilePath() string {
 func (s *JsonDB) getPlaylistCollectionFilePath() string {
 	return filepath.Join(s.storageDir, "playlists.json")
 }
+
+func (s *JsonDB) getLoginAttemptsFilePath() string {
+	return filepath.Join(s.storageDir, "login-attempts.json")
+}
+
+func (s *JsonDB) getLoginFailuresFilePath() string {
+	return filepath.Join(s.storageDir, "login-failures.json")
+}
+
+func (s *JsonDB) getAPITokensFilePath() string {
+	return filepath.Join(s.storageDir, "api-tokens.json")
+}
+
+func (s *JsonDB) getAuditLogFilePath() string {
+	return filepath.Join(s.storageDir, "audit.jsonl")
+}
+
+func (s *JsonDB) getSpacesFilePath() string {
+	return filepath.Join(s.storageDir, "spaces.json")
+}
+
+func (s *JsonDB) getShareLinksFilePath() string {
+	return filepath.Join(s.storageDir, "share-links.json")
+}
+
+func (s *JsonDB) getPlaybackFilePath() string {
+	return filepath.Join(s.storageDir, "playback.json")
+}
+
+func (s *JsonDB) getWatchHistoryFilePath() string {
+	return filepath.Join(s.storageDir, "watch-history.json")
+}
+
+func (s *JsonDB) getVideoStatsFilePath() string {
+	return filepath.Join(s.storageDir, "video-stats.json")
+}
+
+func (s *JsonDB) getVideoLikesFilePath() string {
+	return filepath.Join(s.storageDir, "video-likes.json")
+}
+
+func (s *JsonDB) getRecommendationsFilePath() string {
+	return filepath.Join(s.storageDir, "recommendations.json")
+}
+
+func (s *JsonDB) getTasksFilePath() string {
+	return filepath.Join(s.storageDir, "tasks.json")
+}
+
+func (s *JsonDB) getTasksLockFilePath() string {
+	return filepath.Join(s.storageDir, "tasks.lock")
+}
@@ -0,0 +1,51 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"time"
+)
+
+// GetRecommendationModel returns the stored recommendation model, empty when it was never built.
+func (s *JsonDB) GetRecommendationModel() (*datatypes.RecommendationModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	model, err := s.loadRecommendationModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recommendation model: %w", err)
+	}
+	return model, nil
+}
+
+// SaveRecommendationModel replaces the stored recommendation model.
+func (s *JsonDB) SaveRecommendationModel(model *datatypes.RecommendationModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.saveRecommendationModel(model); err != nil {
+		return fmt.Errorf("failed to save recommendation model: %w", err)
+	}
+	return nil
+}
+
+// AddRecommendationInteraction updates the stored model with one interaction. Models that
+// were never built are left alone, since the first rebuild includes the interaction anyway.
+func (s *JsonDB) AddRecommendationInteraction(accountId, videoId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	model, err := s.loadRecommendationModel()
+	if err != nil {
+		return fmt.Errorf("failed to load recommendation model: %w", err)
+	}
+	if model.BuiltAt.IsZero() || !model.AddInteraction(accountId, videoId) {
+		return nil
+	}
+	model.UpdatedAt = time.Now().UTC()
+
+	if err := s.saveRecommendationModel(model); err != nil {
+		return fmt.Errorf("failed to save recommendation model: %w", err)
+	}
+	return nil
+}
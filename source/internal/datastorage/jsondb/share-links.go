@@ -0,0 +1,116 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"sort"
+)
+
+// InsertShareLink stores a new share link.
+func (s *JsonDB) InsertShareLink(link *datatypes.ShareLinkData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.loadShareLinks()
+	if err != nil {
+		return fmt.Errorf("failed to load share links: %w", err)
+	}
+
+	if _, exists := links[link.ID]; exists {
+		return fmt.Errorf("share link with ID %q already exists", link.ID)
+	}
+
+	links[link.ID] = *link
+	return s.saveShareLinks(links)
+}
+
+// GetShareLinkByID returns a copy of the share link with the given ID.
+func (s *JsonDB) GetShareLinkByID(linkId string) (*datatypes.ShareLinkData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.loadShareLinks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share links: %w", err)
+	}
+
+	link, exists := links[linkId]
+	if !exists {
+		return nil, fmt.Errorf("share link %q not found", linkId)
+	}
+	return &link, nil
+}
+
+// GetShareLinks returns share links newest first. Empty arguments do not filter.
+func (s *JsonDB) GetShareLinks(videoId, createdBy string) ([]datatypes.ShareLinkData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.loadShareLinks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share links: %w", err)
+	}
+
+	result := []datatypes.ShareLinkData{}
+	for _, link := range links {
+		if videoId != "" && link.VideoID != videoId {
+			continue
+		}
+		if createdBy != "" && link.CreatedBy != createdBy {
+			continue
+		}
+		result = append(result, link)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// RevokeShareLink marks a share link as revoked. The record is kept for the audit trail.
+func (s *JsonDB) RevokeShareLink(linkId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.loadShareLinks()
+	if err != nil {
+		return fmt.Errorf("failed to load share links: %w", err)
+	}
+
+	link, exists := links[linkId]
+	if !exists {
+		return fmt.Errorf("share link %q not found", linkId)
+	}
+
+	link.Revoked = true
+	links[linkId] = link
+	return s.saveShareLinks(links)
+}
+
+// AddShareLinkView counts one view, refusing once the view limit is reached.
+// The check and the increment happen under one lock so concurrent opens cannot overshoot.
+func (s *JsonDB) AddShareLinkView(linkId string) (*datatypes.ShareLinkData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.loadShareLinks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share links: %w", err)
+	}
+
+	link, exists := links[linkId]
+	if !exists {
+		return nil, fmt.Errorf("share link %q not found", linkId)
+	}
+	if link.ViewsExhausted() {
+		return nil, fmt.Errorf("share link %q has reached its view limit", linkId)
+	}
+
+	link.Views++
+	links[linkId] = link
+	if err := s.saveShareLinks(links); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,58 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"sort"
+)
+
+// GetSpace returns the stored settings of a space by name.
+func (s *JsonDB) GetSpace(spaceName string) (*datatypes.SpaceData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spaces, err := s.loadSpaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spaces: %w", err)
+	}
+
+	space, exists := spaces[spaceName]
+	if !exists {
+		return nil, fmt.Errorf("space %q not found", spaceName)
+	}
+	return &space, nil
+}
+
+// GetAllSpaces returns every space that has stored settings, sorted by name.
+func (s *JsonDB) GetAllSpaces() ([]datatypes.SpaceData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spaces, err := s.loadSpaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spaces: %w", err)
+	}
+
+	result := make([]datatypes.SpaceData, 0, len(spaces))
+	for _, space := range spaces {
+		result = append(result, space)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SpaceName < result[j].SpaceName
+	})
+	return result, nil
+}
+
+// SaveSpace inserts or replaces the settings of a space.
+func (s *JsonDB) SaveSpace(space datatypes.SpaceData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spaces, err := s.loadSpaces()
+	if err != nil {
+		return fmt.Errorf("failed to load spaces: %w", err)
+	}
+
+	spaces[space.SpaceName] = space
+	return s.saveSpaces(spaces)
+}
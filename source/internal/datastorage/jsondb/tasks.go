@@ -0,0 +1,141 @@
+package jsondb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"sort"
+	"time"
+)
+
+// InsertTask stores a new task.
+func (s *JsonDB) InsertTask(task datatypes.TaskData) error {
+	return s.withTasksLock(func() error {
+		tasks, err := s.loadTasks()
+		if err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+
+		if _, exists := tasks[task.ID]; exists {
+			return fmt.Errorf("task with ID %q already exists", task.ID)
+		}
+
+		tasks[task.ID] = task
+		return s.saveTasks(tasks)
+	})
+}
+
+// UpdateTask replaces a stored task with task, but only while the stored copy still equals
+// previous. Otherwise another process changed it in the meantime and datatypes.ErrTaskChanged
+// is returned, so claiming a task or renewing its lease can never overwrite another worker's.
+func (s *JsonDB) UpdateTask(previous, task datatypes.TaskData) error {
+	return s.withTasksLock(func() error {
+		tasks, err := s.loadTasks()
+		if err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+
+		stored, exists := tasks[task.ID]
+		if !exists {
+			return fmt.Errorf("task %q not found", task.ID)
+		}
+		if same, err := sameTask(stored, previous); err != nil {
+			return err
+		} else if !same {
+			return fmt.Errorf("task %q: %w", task.ID, datatypes.ErrTaskChanged)
+		}
+
+		tasks[task.ID] = task
+		return s.saveTasks(tasks)
+	})
+}
+
+// sameTask compares two tasks as they are stored, so times read back from the file match.
+func sameTask(a, b datatypes.TaskData) (bool, error) {
+	aData, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aData, bData), nil
+}
+
+// GetTaskByID returns a copy of the task with the given ID.
+func (s *JsonDB) GetTaskByID(taskId string) (*datatypes.TaskData, error) {
+	var task datatypes.TaskData
+	err := s.withTasksLock(func() error {
+		tasks, err := s.loadTasks()
+		if err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+
+		stored, exists := tasks[taskId]
+		if !exists {
+			return fmt.Errorf("task %q not found", taskId)
+		}
+		task = stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetAllTasks returns all tasks, oldest first.
+func (s *JsonDB) GetAllTasks() ([]datatypes.TaskData, error) {
+	var tasks map[string]datatypes.TaskData
+	err := s.withTasksLock(func() error {
+		var err error
+		if tasks, err = s.loadTasks(); err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]datatypes.TaskData, 0, len(tasks))
+	for _, task := range tasks {
+		result = append(result, task)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// DeleteFinishedTasks removes completed, failed and cancelled tasks that finished before
+// the given time and returns how many were removed.
+func (s *JsonDB) DeleteFinishedTasks(before time.Time) (int, error) {
+	removed := 0
+	err := s.withTasksLock(func() error {
+		tasks, err := s.loadTasks()
+		if err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+
+		for id, task := range tasks {
+			if task.IsFinished() && task.FinishedAt.Before(before) {
+				delete(tasks, id)
+				removed++
+			}
+		}
+		if removed == 0 {
+			return nil
+		}
+		return s.saveTasks(tasks)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
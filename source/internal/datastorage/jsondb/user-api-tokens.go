@@ -0,0 +1,105 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"sort"
+	"time"
+)
+
+// InsertAPIToken stores a new personal API token.
+func (s *JsonDB) InsertAPIToken(token *datatypes.APITokenData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return fmt.Errorf("failed to load api tokens: %w", err)
+	}
+
+	if _, exists := tokens[token.ID]; exists {
+		return fmt.Errorf("api token with ID %q already exists", token.ID)
+	}
+
+	tokens[token.ID] = *token
+	return s.saveAPITokens(tokens)
+}
+
+// GetAPITokenByID returns a copy of the token with the given ID.
+func (s *JsonDB) GetAPITokenByID(tokenId string) (*datatypes.APITokenData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load api tokens: %w", err)
+	}
+
+	token, exists := tokens[tokenId]
+	if !exists {
+		return nil, fmt.Errorf("api token %q not found", tokenId)
+	}
+	return &token, nil
+}
+
+// GetAPITokensByAccountID returns all tokens owned by a user, newest first.
+func (s *JsonDB) GetAPITokensByAccountID(accountId string) ([]datatypes.APITokenData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load api tokens: %w", err)
+	}
+
+	result := []datatypes.APITokenData{}
+	for _, token := range tokens {
+		if token.AccountID == accountId {
+			result = append(result, token)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// DeleteAPIToken removes a token owned by the given user.
+func (s *JsonDB) DeleteAPIToken(accountId, tokenId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return fmt.Errorf("failed to load api tokens: %w", err)
+	}
+
+	token, exists := tokens[tokenId]
+	if !exists || token.AccountID != accountId {
+		return fmt.Errorf("api token %q not found", tokenId)
+	}
+
+	delete(tokens, tokenId)
+	return s.saveAPITokens(tokens)
+}
+
+// UpdateAPITokenLastUsed records when a token was last accepted.
+func (s *JsonDB) UpdateAPITokenLastUsed(tokenId string, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return fmt.Errorf("failed to load api tokens: %w", err)
+	}
+
+	token, exists := tokens[tokenId]
+	if !exists {
+		return fmt.Errorf("api token %q not found", tokenId)
+	}
+
+	token.LastUsedAt = usedAt
+	tokens[tokenId] = token
+	return s.saveAPITokens(tokens)
+}
@@ -54,6 +54,7 @@ func (s *JsonDB) DeleteUser(accountId string) (*datatypes.UserData, error) {
 	return &user, nil
 }
 
+// UpdateUser replaces the stored record of an existing user, matched by account ID.
 func (s *JsonDB) UpdateUser(userData datatypes.UserData) error { // Takes value, not pointer
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -63,11 +64,11 @@ func (s *JsonDB) UpdateUser(userData datatypes.UserData) error { // Takes value,
 		return fmt.Errorf("failed to load users: %w", err)
 	}
 
-	if _, exists := users[userData.Username]; !exists {
-		return fmt.Errorf("user %q not found for update", userData.Username)
+	if _, exists := users[userData.AccountID]; !exists {
+		return fmt.Errorf("user %q not found for update", userData.AccountID)
 	}
 
-	users[userData.Username] = userData // Store the updated value
+	users[userData.AccountID] = userData // Store the updated value
 	return s.saveUsers(users)
 }
 
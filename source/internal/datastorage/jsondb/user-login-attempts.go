@@ -0,0 +1,177 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"sort"
+	"time"
+)
+
+// maxLoginFailuresPerUser caps how many failure events are kept for each username.
+const maxLoginFailuresPerUser = 200
+
+// Every random username tried gets its own counter and failure list, so both files are
+// capped. Counters that are idle and no longer block anything are dropped first.
+const (
+	maxLoginAttemptKeys      = 5000
+	maxLoginFailureUsernames = 1000
+	loginAttemptIdleAfter    = 24 * time.Hour
+)
+
+// GetLoginAttempt returns the attempt counter for a key.
+// A fresh zero-valued counter is returned when the key has no recorded failures.
+func (s *JsonDB) GetLoginAttempt(key string) (*datatypes.LoginAttemptData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, err := s.loadLoginAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load login attempts: %w", err)
+	}
+
+	attempt, exists := attempts[key]
+	if !exists {
+		return &datatypes.LoginAttemptData{Key: key}, nil
+	}
+	return &attempt, nil
+}
+
+// SaveLoginAttempt inserts or replaces the attempt counter for attempt.Key.
+func (s *JsonDB) SaveLoginAttempt(attempt datatypes.LoginAttemptData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, err := s.loadLoginAttempts()
+	if err != nil {
+		return fmt.Errorf("failed to load login attempts: %w", err)
+	}
+
+	attempts[attempt.Key] = attempt
+	pruneLoginAttempts(attempts, attempt.Key, time.Now().UTC())
+	return s.saveLoginAttempts(attempts)
+}
+
+// DeleteLoginAttempt removes the attempt counter for a key.
+// Returns an error if the key has no recorded failures.
+func (s *JsonDB) DeleteLoginAttempt(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, err := s.loadLoginAttempts()
+	if err != nil {
+		return fmt.Errorf("failed to load login attempts: %w", err)
+	}
+
+	if _, exists := attempts[key]; !exists {
+		return fmt.Errorf("no login attempts recorded for %q", key)
+	}
+
+	delete(attempts, key)
+	return s.saveLoginAttempts(attempts)
+}
+
+// GetAllLoginAttempts returns every tracked attempt counter.
+func (s *JsonDB) GetAllLoginAttempts() ([]datatypes.LoginAttemptData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, err := s.loadLoginAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load login attempts: %w", err)
+	}
+
+	result := make([]datatypes.LoginAttemptData, 0, len(attempts))
+	for _, attempt := range attempts {
+		result = append(result, attempt)
+	}
+	return result, nil
+}
+
+// InsertLoginFailure appends a failure event under the attempted username,
+// dropping the oldest events once maxLoginFailuresPerUser is exceeded.
+func (s *JsonDB) InsertLoginFailure(event datatypes.LoginFailureEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failures, err := s.loadLoginFailures()
+	if err != nil {
+		return fmt.Errorf("failed to load login failures: %w", err)
+	}
+
+	events := append(failures[event.Username], event)
+	if len(events) > maxLoginFailuresPerUser {
+		events = events[len(events)-maxLoginFailuresPerUser:]
+	}
+	failures[event.Username] = events
+
+	// Forget the usernames whose last failure is oldest
+	for len(failures) > maxLoginFailureUsernames {
+		oldest, oldestAt := "", time.Time{}
+		for username, userEvents := range failures {
+			last := userEvents[len(userEvents)-1].CreatedAt
+			if username != event.Username && (oldest == "" || last.Before(oldestAt)) {
+				oldest, oldestAt = username, last
+			}
+		}
+		delete(failures, oldest)
+	}
+
+	return s.saveLoginFailures(failures)
+}
+
+// pruneLoginAttempts drops idle counters that block nothing, then the oldest others
+// until at most maxLoginAttemptKeys are left. keep is never dropped.
+func pruneLoginAttempts(attempts map[string]datatypes.LoginAttemptData, keep string, now time.Time) {
+	for key, attempt := range attempts {
+		if key != keep && now.Sub(attempt.LastFailure) > loginAttemptIdleAfter && !attempt.BlockedUntil.After(now) {
+			delete(attempts, key)
+		}
+	}
+	if len(attempts) <= maxLoginAttemptKeys {
+		return
+	}
+
+	keys := make([]string, 0, len(attempts))
+	for key := range attempts {
+		if key != keep {
+			keys = append(keys, key)
+		}
+	}
+	// Unblocked counters go before blocked ones, each oldest first
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := attempts[keys[i]], attempts[keys[j]]
+		if aBlocked, bBlocked := a.BlockedUntil.After(now), b.BlockedUntil.After(now); aBlocked != bBlocked {
+			return bBlocked
+		}
+		return a.LastFailure.Before(b.LastFailure)
+	})
+	for _, key := range keys[:len(attempts)-maxLoginAttemptKeys] {
+		delete(attempts, key)
+	}
+}
+
+// GetLoginFailures returns failure events newest first.
+// An empty username returns the events for every username.
+func (s *JsonDB) GetLoginFailures(username string) ([]datatypes.LoginFailureEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failures, err := s.loadLoginFailures()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load login failures: %w", err)
+	}
+
+	var events []datatypes.LoginFailureEvent
+	if username != "" {
+		events = append(events, failures[username]...)
+	} else {
+		for _, userEvents := range failures {
+			events = append(events, userEvents...)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.After(events[j].CreatedAt)
+	})
+	return events, nil
+}
@@ -0,0 +1,110 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"sort"
+)
+
+// SavePlaybackState stores the playback state of a user for one video, replacing the previous one.
+func (s *JsonDB) SavePlaybackState(accountId string, state datatypes.PlaybackState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.loadPlayback()
+	if err != nil {
+		return fmt.Errorf("failed to load playback states: %w", err)
+	}
+
+	if states[accountId] == nil {
+		states[accountId] = map[string]datatypes.PlaybackState{}
+	}
+	states[accountId][state.VideoID] = state
+
+	if err := s.savePlayback(states); err != nil {
+		return fmt.Errorf("failed to save playback states: %w", err)
+	}
+	return nil
+}
+
+// GetPlaybackState returns the playback state of a user for one video.
+func (s *JsonDB) GetPlaybackState(accountId, videoId string) (*datatypes.PlaybackState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.loadPlayback()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playback states: %w", err)
+	}
+
+	state, exists := states[accountId][videoId]
+	if !exists {
+		return nil, fmt.Errorf("no playback state for video %q", videoId)
+	}
+	return &state, nil
+}
+
+// GetPlaybackStates returns all playback states of a user, most recently updated first.
+func (s *JsonDB) GetPlaybackStates(accountId string) ([]datatypes.PlaybackState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.loadPlayback()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playback states: %w", err)
+	}
+
+	result := make([]datatypes.PlaybackState, 0, len(states[accountId]))
+	for _, state := range states[accountId] {
+		result = append(result, state)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	return result, nil
+}
+
+// DeletePlaybackState forgets where a user was in one video.
+func (s *JsonDB) DeletePlaybackState(accountId, videoId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.loadPlayback()
+	if err != nil {
+		return fmt.Errorf("failed to load playback states: %w", err)
+	}
+
+	if _, exists := states[accountId][videoId]; !exists {
+		return fmt.Errorf("no playback state for video %q", videoId)
+	}
+	delete(states[accountId], videoId)
+	if len(states[accountId]) == 0 {
+		delete(states, accountId)
+	}
+
+	if err := s.savePlayback(states); err != nil {
+		return fmt.Errorf("failed to save playback states: %w", err)
+	}
+	return nil
+}
+
+// ClearPlaybackStates forgets all playback states of a user.
+func (s *JsonDB) ClearPlaybackStates(accountId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.loadPlayback()
+	if err != nil {
+		return fmt.Errorf("failed to load playback states: %w", err)
+	}
+
+	if _, exists := states[accountId]; !exists {
+		return nil
+	}
+	delete(states, accountId)
+
+	if err := s.savePlayback(states); err != nil {
+		return fmt.Errorf("failed to save playback states: %w", err)
+	}
+	return nil
+}
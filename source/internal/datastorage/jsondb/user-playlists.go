@@ -85,6 +85,8 @@ func (s *JsonDB) DeletePlaylistByID(accountId, playlistId string) error {
 	return nil
 }
 
+// AddVideoToPlaylist appends a video to a playlist and records accountId as the one who added it.
+// Callers check that the account may edit the playlist, since collaborators can too.
 func (s *JsonDB) AddVideoToPlaylist(accountId, playlistId, videoId string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -100,10 +102,6 @@ func (s *JsonDB) AddVideoToPlaylist(accountId, playlistId, videoId string) error
 		return fmt.Errorf("playlist with id %q not found", playlistId)
 	}
 
-	if playlist.OwnerAccountId != accountId {
-		return fmt.Errorf("playlist with id %q does not belong to user %q", playlistId, accountId)
-	}
-
 	// Check if videoId already exists
 	for _, vid := range playlist.VideoIDs {
 		if vid == videoId {
@@ -113,6 +111,7 @@ func (s *JsonDB) AddVideoToPlaylist(accountId, playlistId, videoId string) error
 
 	// Add the video to the playlist
 	playlist.VideoIDs = append(playlist.VideoIDs, videoId)
+	playlist.RecordChange(accountId, "add", videoId)
 	playlists[playlistId] = playlist
 
 	if err := s.SavePlaylistCollection(playlists); err != nil {
@@ -122,8 +121,9 @@ func (s *JsonDB) AddVideoToPlaylist(accountId, playlistId, videoId string) error
 	return nil
 }
 
-// RemoveVideoFromPlaylist removes a video ID from a specific playlist of a user.
-// Returns an error if the user, playlist, or video (within the playlist) is not found.
+// RemoveVideoFromPlaylist removes a video ID from a playlist and records accountId as the one who removed it.
+// Returns an error if the playlist or video (within the playlist) is not found.
+// Callers check that the account may edit the playlist.
 func (s *JsonDB) RemoveVideoFromPlaylist(accountId, playlistId, videoId string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -139,10 +139,6 @@ func (s *JsonDB) RemoveVideoFromPlaylist(accountId, playlistId, videoId string)
 		return fmt.Errorf("playlist with id %q not found", playlistId)
 	}
 
-	if playlist.OwnerAccountId != accountId {
-		return fmt.Errorf("playlist with id %q does not belong to user %q", playlistId, accountId)
-	}
-
 	// Find the index of the video to remove
 	indexToRemove := -1
 	for i, vid := range playlist.VideoIDs {
@@ -157,6 +153,11 @@ func (s *JsonDB) RemoveVideoFromPlaylist(accountId, playlistId, videoId string)
 	}
 
 	playlist.VideoIDs = append(playlist.VideoIDs[:indexToRemove], playlist.VideoIDs[indexToRemove+1:]...)
+	playlist.RecordChange(accountId, "remove", videoId)
+	if playlist.CoverVideoID == videoId {
+		playlist.CoverVideoID = ""
+		playlist.CoverFrameSec = nil
+	}
 	playlists[playlistId] = playlist
 
 	if err := s.SavePlaylistCollection(playlists); err != nil {
@@ -263,3 +264,117 @@ func (s *JsonDB) GetPlaylistsByUser(accountId string) ([]datatypes.PlaylistData,
 
 	return userPlaylists, nil
 }
+
+// GetPlaylist returns a playlist by ID regardless of its owner.
+// Callers are responsible for checking whether the requester may see it.
+func (s *JsonDB) GetPlaylist(playlistId string) (*datatypes.PlaylistData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists, err := s.LoadPlaylistCollection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playlist collection: %w", err)
+	}
+
+	playlist, exists := playlists[playlistId]
+	if !exists {
+		return nil, fmt.Errorf("playlist with id %q not found", playlistId)
+	}
+	return &playlist, nil
+}
+
+// UpdatePlaylistPrivacy changes the privacy setting of a playlist owned by the user.
+func (s *JsonDB) UpdatePlaylistPrivacy(accountId, playlistId string, privacy datatypes.PrivacySetting) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists, err := s.LoadPlaylistCollection()
+	if err != nil {
+		return fmt.Errorf("failed to load playlist collection: %w", err)
+	}
+
+	playlist, exists := playlists[playlistId]
+	if !exists {
+		return fmt.Errorf("playlist with id %q not found", playlistId)
+	}
+	if playlist.OwnerAccountId != accountId {
+		return fmt.Errorf("playlist with id %q does not belong to user %q", playlistId, accountId)
+	}
+
+	playlist.Privacy = privacy
+	playlists[playlistId] = playlist
+
+	if err := s.SavePlaylistCollection(playlists); err != nil {
+		return fmt.Errorf("failed to save playlist updates: %w", err)
+	}
+	return nil
+}
+
+// GetAllPlaylists returns every playlist of every user.
+func (s *JsonDB) GetAllPlaylists() ([]datatypes.PlaylistData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists, err := s.LoadPlaylistCollection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playlist collection: %w", err)
+	}
+
+	result := make([]datatypes.PlaylistData, 0, len(playlists))
+	for _, p := range playlists {
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// UpdatePlaylistShares replaces the users and spaces a playlist owned by the user is shared with.
+func (s *JsonDB) UpdatePlaylistShares(accountId, playlistId string, shares []datatypes.PlaylistShare) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists, err := s.LoadPlaylistCollection()
+	if err != nil {
+		return fmt.Errorf("failed to load playlist collection: %w", err)
+	}
+
+	playlist, exists := playlists[playlistId]
+	if !exists {
+		return fmt.Errorf("playlist with id %q not found", playlistId)
+	}
+	if playlist.OwnerAccountId != accountId {
+		return fmt.Errorf("playlist with id %q does not belong to user %q", playlistId, accountId)
+	}
+
+	playlist.Shares = shares
+	playlists[playlistId] = playlist
+
+	if err := s.SavePlaylistCollection(playlists); err != nil {
+		return fmt.Errorf("failed to save playlist updates: %w", err)
+	}
+	return nil
+}
+
+// SavePlaylist replaces a stored playlist with the given one.
+// Callers check that the account may change it.
+func (s *JsonDB) SavePlaylist(playlist datatypes.PlaylistData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists, err := s.LoadPlaylistCollection()
+	if err != nil {
+		return fmt.Errorf("failed to load playlist collection: %w", err)
+	}
+
+	if _, exists := playlists[playlist.ID]; !exists {
+		return fmt.Errorf("playlist with id %q not found", playlist.ID)
+	}
+	if playlist.VideoIDs == nil {
+		playlist.VideoIDs = []string{}
+	}
+	playlists[playlist.ID] = playlist
+
+	if err := s.SavePlaylistCollection(playlists); err != nil {
+		return fmt.Errorf("failed to save playlist updates: %w", err)
+	}
+	return nil
+}
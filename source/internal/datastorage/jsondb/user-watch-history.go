@@ -0,0 +1,124 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"time"
+)
+
+// RecordWatchEvent adds watchedSec to the user's latest history entry when it is for the same
+// video and was seen within datatypes.WatchSessionGap, and starts a new entry otherwise.
+func (s *JsonDB) RecordWatchEvent(accountId, videoId string, at time.Time, watchedSec float64) (*datatypes.WatchEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.loadWatchHistory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watch history: %w", err)
+	}
+
+	events := history[accountId]
+	if n := len(events); n > 0 {
+		last := &events[n-1]
+		if last.VideoID == videoId && at.Sub(last.LastSeenAt) <= datatypes.WatchSessionGap {
+			last.LastSeenAt = at
+			last.WatchedSec += watchedSec
+			if err := s.saveWatchHistory(history); err != nil {
+				return nil, fmt.Errorf("failed to save watch history: %w", err)
+			}
+			event := *last
+			return &event, nil
+		}
+	}
+
+	event, err := datatypes.NewWatchEvent(videoId, at)
+	if err != nil {
+		return nil, err
+	}
+	event.WatchedSec = watchedSec
+	history[accountId] = append(events, *event)
+
+	if err := s.saveWatchHistory(history); err != nil {
+		return nil, fmt.Errorf("failed to save watch history: %w", err)
+	}
+	return event, nil
+}
+
+// GetWatchEvents returns the user's history entries that started within [since, until),
+// newest first. Zero bounds are open.
+func (s *JsonDB) GetWatchEvents(accountId string, since, until time.Time) ([]datatypes.WatchEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.loadWatchHistory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watch history: %w", err)
+	}
+
+	events := history[accountId]
+	result := make([]datatypes.WatchEvent, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].InRange(since, until) {
+			result = append(result, events[i])
+		}
+	}
+	return result, nil
+}
+
+// DeleteWatchEvent removes one entry from the user's history.
+func (s *JsonDB) DeleteWatchEvent(accountId, eventId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.loadWatchHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load watch history: %w", err)
+	}
+
+	events := history[accountId]
+	for i := range events {
+		if events[i].ID == eventId {
+			history[accountId] = append(events[:i], events[i+1:]...)
+			if err := s.saveWatchHistory(history); err != nil {
+				return fmt.Errorf("failed to save watch history: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("history entry %q not found", eventId)
+}
+
+// DeleteWatchEvents removes the user's history entries that started within [since, until)
+// and returns how many were removed. Zero bounds are open, so two zero times clear everything.
+func (s *JsonDB) DeleteWatchEvents(accountId string, since, until time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.loadWatchHistory()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load watch history: %w", err)
+	}
+
+	events := history[accountId]
+	kept := make([]datatypes.WatchEvent, 0, len(events))
+	for _, event := range events {
+		if !event.InRange(since, until) {
+			kept = append(kept, event)
+		}
+	}
+
+	removed := len(events) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	if len(kept) == 0 {
+		delete(history, accountId)
+	} else {
+		history[accountId] = kept
+	}
+
+	if err := s.saveWatchHistory(history); err != nil {
+		return 0, fmt.Errorf("failed to save watch history: %w", err)
+	}
+	return removed, nil
+}
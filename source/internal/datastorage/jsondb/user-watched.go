@@ -71,6 +71,43 @@ func (s *JsonDB) GetUserWatchedVideos(accountId string) ([]string, error) {
 	return watchedVideos, nil
 }
 
+// RemoveVideosFromWatched takes videos off the watched list of a given user.
+// Videos that are not on the list are ignored.
+func (s *JsonDB) RemoveVideosFromWatched(accountId string, videoIds []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	videos, err := s.loadWatched()
+	if err != nil {
+		return fmt.Errorf("failed to load watched videos: %w", err)
+	}
+
+	userVideos, exists := videos[accountId]
+	if !exists {
+		return nil
+	}
+
+	removed := make(map[string]bool, len(videoIds))
+	for _, videoId := range videoIds {
+		removed[videoId] = true
+	}
+	kept := make([]string, 0, len(userVideos))
+	for _, videoId := range userVideos {
+		if !removed[videoId] {
+			kept = append(kept, videoId)
+		}
+	}
+	if len(kept) == len(userVideos) {
+		return nil
+	}
+	videos[accountId] = kept
+
+	if err := s.saveWatched(videos); err != nil {
+		return fmt.Errorf("failed to save updated watched videos: %w", err)
+	}
+	return nil
+}
+
 // ClearUserWatchedHistory clears all watched videos for a given user.
 func (s *JsonDB) ClearUserWatchedHistory(accountId string) error {
 	s.mu.Lock()
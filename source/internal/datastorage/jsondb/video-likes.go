@@ -0,0 +1,51 @@
+package jsondb
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetVideoLike likes or unlikes a video for an account and returns the video's number of likes.
+// Liking a video again keeps the time of the first like.
+func (s *JsonDB) SetVideoLike(videoId, accountId string, liked bool, at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	likes, err := s.loadVideoLikes()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load video likes: %w", err)
+	}
+
+	_, exists := likes[videoId][accountId]
+	switch {
+	case liked && !exists:
+		if likes[videoId] == nil {
+			likes[videoId] = map[string]time.Time{}
+		}
+		likes[videoId][accountId] = at
+	case !liked && exists:
+		delete(likes[videoId], accountId)
+		if len(likes[videoId]) == 0 {
+			delete(likes, videoId)
+		}
+	default:
+		return len(likes[videoId]), nil
+	}
+
+	if err := s.saveVideoLikes(likes); err != nil {
+		return 0, fmt.Errorf("failed to save video likes: %w", err)
+	}
+	return len(likes[videoId]), nil
+}
+
+// GetAllVideoLikes returns when each account liked each video, keyed by video ID, then account ID.
+func (s *JsonDB) GetAllVideoLikes() (map[string]map[string]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	likes, err := s.loadVideoLikes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load video likes: %w", err)
+	}
+	return likes, nil
+}
@@ -0,0 +1,125 @@
+package jsondb
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"time"
+)
+
+// CountVideoView adds a view to the video and to its daily statistics, unless the same viewer
+// already added one within window. It reports whether the view was counted.
+func (s *JsonDB) CountVideoView(videoId, viewerKey string, at time.Time, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.loadVideoStats()
+	if err != nil {
+		return false, fmt.Errorf("failed to load video stats: %w", err)
+	}
+
+	key := viewerKey + "/" + videoId
+	if last, exists := stats.LastViews[key]; exists && at.Sub(last) < window {
+		return false, nil
+	}
+
+	videos, err := s.loadVideos()
+	if err != nil {
+		return false, fmt.Errorf("failed to load videos: %w", err)
+	}
+	video, exists := videos[videoId]
+	if !exists {
+		return false, fmt.Errorf("video %q not found", videoId)
+	}
+	video.TotalViews++
+	videos[videoId] = video
+
+	// Drop debounce entries that can no longer block a view
+	for k, last := range stats.LastViews {
+		if at.Sub(last) >= window {
+			delete(stats.LastViews, k)
+		}
+	}
+	stats.LastViews[key] = at
+	addDailyStats(stats, videoId, at, 1, 0)
+
+	if err := s.saveVideos(videos); err != nil {
+		return false, fmt.Errorf("failed to save videos: %w", err)
+	}
+	if err := s.saveVideoStats(stats); err != nil {
+		return false, fmt.Errorf("failed to save video stats: %w", err)
+	}
+	return true, nil
+}
+
+// CountVideoDownload adds a download to the video and to its daily statistics.
+func (s *JsonDB) CountVideoDownload(videoId string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	videos, err := s.loadVideos()
+	if err != nil {
+		return fmt.Errorf("failed to load videos: %w", err)
+	}
+	video, exists := videos[videoId]
+	if !exists {
+		return fmt.Errorf("video %q not found", videoId)
+	}
+	video.TotalDownloads++
+	videos[videoId] = video
+
+	stats, err := s.loadVideoStats()
+	if err != nil {
+		return fmt.Errorf("failed to load video stats: %w", err)
+	}
+	addDailyStats(stats, videoId, at, 0, 1)
+
+	if err := s.saveVideos(videos); err != nil {
+		return fmt.Errorf("failed to save videos: %w", err)
+	}
+	if err := s.saveVideoStats(stats); err != nil {
+		return fmt.Errorf("failed to save video stats: %w", err)
+	}
+	return nil
+}
+
+// GetDailyVideoStats returns the statistics of every video keyed by UTC day, then video ID,
+// for the days from since on. A zero since returns all days.
+func (s *JsonDB) GetDailyVideoStats(since time.Time) (map[string]map[string]datatypes.VideoDailyStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.loadVideoStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load video stats: %w", err)
+	}
+
+	first := ""
+	if !since.IsZero() {
+		first = since.UTC().Format(datatypes.StatsDayLayout)
+	}
+
+	result := make(map[string]map[string]datatypes.VideoDailyStats)
+	for day, videos := range stats.Daily {
+		if day < first {
+			continue
+		}
+		copied := make(map[string]datatypes.VideoDailyStats, len(videos))
+		for videoId, counts := range videos {
+			copied[videoId] = counts
+		}
+		result[day] = copied
+	}
+	return result, nil
+}
+
+// addDailyStats adds views and downloads to the video's counts for the UTC day of at.
+func addDailyStats(stats *videoStatsFile, videoId string, at time.Time, views, downloads int) {
+	day := at.UTC().Format(datatypes.StatsDayLayout)
+	if stats.Daily[day] == nil {
+		stats.Daily[day] = map[string]datatypes.VideoDailyStats{}
+	}
+	counts := stats.Daily[day][videoId]
+	counts.Views += views
+	counts.Downloads += downloads
+	stats.Daily[day][videoId] = counts
+}
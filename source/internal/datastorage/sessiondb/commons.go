@@ -41,6 +41,20 @@ func (db *SessionDB) DeleteSession(sessionID string) error {
 	return nil
 }
 
+// DeleteSessionsByAccountID removes every session of an account and returns how many were removed.
+func (db *SessionDB) DeleteSessionsByAccountID(accountId string) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	removed := 0
+	for sessionID, owner := range db.SessionIDs {
+		if owner == accountId {
+			delete(db.SessionIDs, sessionID)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
 // ClearAllSessions removes all sessions from the database.
 func (db *SessionDB) ClearAllSessions() error {
 	db.mu.Lock()
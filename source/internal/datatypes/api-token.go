@@ -0,0 +1,92 @@
+package datatypes
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type TokenScope string
+
+const (
+	TokenScopeRead   TokenScope = "read"   // GET access to videos, search and the caller's own lists
+	TokenScopeWrite  TokenScope = "write"  // Modify playlists, saved videos, tags, markers and history
+	TokenScopeUpload TokenScope = "upload" // Upload new videos
+)
+
+// APITokenPrefix marks a bearer token as an OVA personal API token.
+const APITokenPrefix = "ova_"
+
+// APITokenData represents a personal API token. Only the SHA-256 of the
+// secret part is stored; the plain token is shown once at creation.
+type APITokenData struct {
+	ID         string       `json:"id"`
+	AccountID  string       `json:"accountId"`
+	Name       string       `json:"name"`
+	SecretHash string       `json:"secretHash"`
+	Scopes     []TokenScope `json:"scopes"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	ExpiresAt  time.Time    `json:"expiresAt"`
+	LastUsedAt time.Time    `json:"lastUsedAt,omitzero"`
+}
+
+// NewAPITokenData creates a token record and returns it together with the plain
+// token string in the form "ova_<id>.<secret>".
+func NewAPITokenData(accountId string, name string, scopes []TokenScope, ttl time.Duration) (*APITokenData, string, error) {
+	id, err := gonanoid.New(11)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not generate id: %w", err)
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, "", fmt.Errorf("could not generate secret: %w", err)
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	now := time.Now().UTC()
+	token := &APITokenData{
+		ID:         id,
+		AccountID:  accountId,
+		Name:       name,
+		SecretHash: HashAPITokenSecret(secret),
+		Scopes:     scopes,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	return token, APITokenPrefix + id + "." + secret, nil
+}
+
+// HashAPITokenSecret returns the hex SHA-256 digest stored for a token secret.
+func HashAPITokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// HasScope reports whether the token grants the given scope.
+func (t *APITokenData) HasScope(scope TokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired reports whether the token can no longer be used.
+func (t *APITokenData) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsValidTokenScope reports whether scope is one of the known token scopes.
+func IsValidTokenScope(scope TokenScope) bool {
+	switch scope {
+	case TokenScopeRead, TokenScopeWrite, TokenScopeUpload:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package datatypes
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Audit actions recorded by the server and CLI.
+const (
+	AuditLogin            = "auth.login"
+	AuditLoginFailed      = "auth.login_failed"
+	AuditLogout           = "auth.logout"
+	AuditPasswordChange   = "auth.password_change"
+	AuditTwoFactorEnable  = "auth.2fa_enable"
+	AuditTwoFactorDisable = "auth.2fa_disable"
+	AuditTokenCreate      = "token.create"
+	AuditTokenRevoke      = "token.revoke"
+	AuditUserCreate       = "user.create"
+	AuditUserUpdate       = "user.update"
+	AuditUserPassword     = "user.password_reset"
+	AuditUserDelete       = "user.delete"
+	AuditLoginUnlock      = "auth.unlock"
+	AuditVideoUpload      = "video.upload"
+	AuditVideoDelete      = "video.delete"
+	AuditVideoVisibility  = "video.visibility"
+	AuditVideoCook        = "video.cook"
+	AuditTagAdd           = "video.tag_add"
+	AuditTagRemove        = "video.tag_remove"
+	AuditMarkerAdd        = "marker.add"
+	AuditMarkerRemove     = "marker.remove"
+	AuditPlaylistPrivacy  = "playlist.privacy"
+	AuditPlaylistShare    = "playlist.share"
+	AuditPlaylistUnshare  = "playlist.unshare"
+	AuditSpaceUpdate      = "space.update"
+	AuditShareCreate      = "share.create"
+	AuditShareRevoke      = "share.revoke"
+	AuditTaskCancel       = "task.cancel"
+	AuditTaskRetry        = "task.retry"
+	AuditTaskPriority     = "task.priority"
+)
+
+// AuditEvent is one entry of the append-only audit log.
+type AuditEvent struct {
+	ID        string          `json:"id"`
+	Time      time.Time       `json:"time"`
+	ActorID   string          `json:"actorId"`   // Account that performed the action, empty for anonymous
+	ActorName string          `json:"actorName"` // Username at the time of the action
+	Action    string          `json:"action"`
+	TargetIDs []string        `json:"targetIds,omitempty"` // Videos, users, tokens... the action touched
+	Before    json.RawMessage `json:"before,omitempty"`    // Snapshot before the change
+	After     json.RawMessage `json:"after,omitempty"`     // Snapshot after the change
+	IP        string          `json:"ip,omitempty"`
+}
+
+// AuditFilter selects audit events. Zero fields do not filter.
+type AuditFilter struct {
+	Since   time.Time
+	Until   time.Time
+	ActorID string
+	Action  string // Exact action, or a prefix ending in "." such as "video."
+	Limit   int    // Newest events first; 0 returns everything
+}
+
+// Matches reports whether the event passes the filter, ignoring Limit.
+func (f AuditFilter) Matches(event AuditEvent) bool {
+	if !f.Since.IsZero() && event.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Time.After(f.Until) {
+		return false
+	}
+	if f.ActorID != "" && event.ActorID != f.ActorID {
+		return false
+	}
+	if f.Action != "" {
+		if strings.HasSuffix(f.Action, ".") {
+			if !strings.HasPrefix(event.Action, f.Action) {
+				return false
+			}
+		} else if event.Action != f.Action {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseAuditTime accepts RFC 3339 timestamps or plain dates; empty means no bound.
+func ParseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
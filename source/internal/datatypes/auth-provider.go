@@ -0,0 +1,26 @@
+package datatypes
+
+// Supported authentication provider types.
+const (
+	AuthProviderLocal    = "local"
+	AuthProviderHtpasswd = "htpasswd"
+	AuthProviderOIDC     = "oidc"
+)
+
+// AuthProviderConfig describes one identity source in configs.json.
+// Only the fields for the provider's Type are used.
+type AuthProviderConfig struct {
+	Name        string   `json:"name"` // Unique, used in URLs and stored on provisioned users
+	Type        string   `json:"type"` // local, htpasswd or oidc
+	DefaultRole UserRole `json:"defaultRole,omitempty"`
+
+	// htpasswd
+	HtpasswdFile string `json:"htpasswdFile,omitempty"`
+
+	// oidc
+	IssuerURL    string   `json:"issuerUrl,omitempty"`
+	ClientID     string   `json:"clientId,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+	RedirectURL  string   `json:"redirectUrl,omitempty"` // Must point at /api/v1/auth/oidc/<name>/callback
+	Scopes       []string `json:"scopes,omitempty"`      // Defaults to openid, profile, email
+}
@@ -0,0 +1,150 @@
+package datatypes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Artifacts cooking makes for a video.
+const (
+	ArtifactThumbnail  = "thumbnail"  // JPEG poster frame
+	ArtifactPreview    = "preview"    // Short WebM clip played on hover
+	ArtifactStoryboard = "storyboard" // Sprite sheets and the VTT of the seek bar
+)
+
+// AllArtifacts lists the artifacts in the order they are made.
+var AllArtifacts = []string{ArtifactThumbnail, ArtifactPreview, ArtifactStoryboard}
+
+// IsValidArtifact reports whether name is a known artifact.
+func IsValidArtifact(name string) bool {
+	return slices.Contains(AllArtifacts, name)
+}
+
+// ThumbnailSettings controls the poster frame of a video.
+type ThumbnailSettings struct {
+	Width   int     `json:"width"`           // Height follows the aspect ratio
+	AtSec   float64 `json:"atSec,omitempty"` // Frame time, 0 for the middle of the video
+	Quality int     `json:"quality"`         // JPEG quality, 1-100
+}
+
+// PreviewSettings controls the hover clip of a video.
+type PreviewSettings struct {
+	Width       int     `json:"width"`              // Height follows the aspect ratio
+	StartSec    float64 `json:"startSec,omitempty"` // Clip start, 0 for the middle of the video
+	DurationSec float64 `json:"durationSec"`
+	Codec       string  `json:"codec"`   // vp8 or vp9; the file is WebM either way
+	Bitrate     string  `json:"bitrate"` // ffmpeg bitrate, e.g. 500K
+}
+
+// StoryboardSettings controls the seek bar thumbnails of a video.
+type StoryboardSettings struct {
+	TileWidth   int     `json:"tileWidth"`
+	TileHeight  int     `json:"tileHeight"`
+	Columns     int     `json:"columns"` // Tiles per sprite sheet row
+	Rows        int     `json:"rows"`
+	IntervalSec float64 `json:"intervalSec,omitempty"` // One tile every this many seconds, 0 for one per keyframe
+	Quality     int     `json:"quality"`               // JPEG quality of the sprite sheets, 1-100
+}
+
+// CookingProfile is a named set of cooking settings in the repository config.
+type CookingProfile struct {
+	Artifacts  []string           `json:"artifacts"` // What the profile makes; missing artifacts are left alone
+	Thumbnail  ThumbnailSettings  `json:"thumbnail"`
+	Preview    PreviewSettings    `json:"preview"`
+	Storyboard StoryboardSettings `json:"storyboard"`
+}
+
+// DefaultCookingProfile returns the settings ovacli has always cooked with.
+func DefaultCookingProfile() CookingProfile {
+	return CookingProfile{
+		Artifacts:  slices.Clone(AllArtifacts),
+		Thumbnail:  ThumbnailSettings{Width: 320, Quality: 100},
+		Preview:    PreviewSettings{Width: 320, DurationSec: 4, Codec: "vp8", Bitrate: "500K"},
+		Storyboard: StoryboardSettings{TileWidth: 160, TileHeight: 90, Columns: 5, Rows: 5, Quality: 75},
+	}
+}
+
+// WithDefaults fills the settings a profile leaves out from DefaultCookingProfile, so a
+// profile only needs to name what it changes.
+func (p CookingProfile) WithDefaults() CookingProfile {
+	d := DefaultCookingProfile()
+	if p.Artifacts == nil {
+		p.Artifacts = d.Artifacts
+	}
+	p.Thumbnail.Width = orDefault(p.Thumbnail.Width, d.Thumbnail.Width)
+	p.Thumbnail.Quality = orDefault(p.Thumbnail.Quality, d.Thumbnail.Quality)
+	p.Preview.Width = orDefault(p.Preview.Width, d.Preview.Width)
+	p.Preview.DurationSec = orDefault(p.Preview.DurationSec, d.Preview.DurationSec)
+	p.Preview.Codec = orDefault(p.Preview.Codec, d.Preview.Codec)
+	p.Preview.Bitrate = orDefault(p.Preview.Bitrate, d.Preview.Bitrate)
+	p.Storyboard.TileWidth = orDefault(p.Storyboard.TileWidth, d.Storyboard.TileWidth)
+	p.Storyboard.TileHeight = orDefault(p.Storyboard.TileHeight, d.Storyboard.TileHeight)
+	p.Storyboard.Columns = orDefault(p.Storyboard.Columns, d.Storyboard.Columns)
+	p.Storyboard.Rows = orDefault(p.Storyboard.Rows, d.Storyboard.Rows)
+	p.Storyboard.Quality = orDefault(p.Storyboard.Quality, d.Storyboard.Quality)
+	return p
+}
+
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
+
+// Produces reports whether the profile makes the artifact.
+func (p CookingProfile) Produces(artifact string) bool {
+	return slices.Contains(p.Artifacts, artifact)
+}
+
+// Fingerprint identifies the settings an artifact is made with. Two profiles with the same
+// fingerprint for an artifact make the same file, so switching between them redoes nothing.
+func (p CookingProfile) Fingerprint(artifact string) string {
+	var settings interface{}
+	switch artifact {
+	case ArtifactThumbnail:
+		settings = p.Thumbnail
+	case ArtifactPreview:
+		settings = p.Preview
+	case ArtifactStoryboard:
+		settings = p.Storyboard
+	default:
+		return ""
+	}
+	data, _ := json.Marshal(settings)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// ArtifactStatus is the cook status of one artifact of a video.
+type ArtifactStatus struct {
+	Artifact    string    `json:"artifact"`
+	Present     bool      `json:"present"`               // The file is on disk
+	Profile     string    `json:"profile,omitempty"`     // Cooking profile it was made with, empty when not recorded
+	Fingerprint string    `json:"fingerprint,omitempty"` // Settings it was made with
+	CookedAt    time.Time `json:"cookedAt,omitzero"`
+	UpToDate    bool      `json:"upToDate"` // Present and made with the settings of the default profile
+}
+
+// CookedArtifact records how an artifact of a video was made.
+type CookedArtifact struct {
+	Profile     string    `json:"profile"`     // Cooking profile used
+	Fingerprint string    `json:"fingerprint"` // CookingProfile.Fingerprint of the settings used
+	CookedAt    time.Time `json:"cookedAt"`
+}
+
+// RecordArtifact stores that an artifact of the video was made with a cooking profile.
+func (v *VideoData) RecordArtifact(artifact, profileName string, profile CookingProfile) {
+	if v.Artifacts == nil {
+		v.Artifacts = make(map[string]CookedArtifact)
+	}
+	v.Artifacts[artifact] = CookedArtifact{
+		Profile:     profileName,
+		Fingerprint: profile.Fingerprint(artifact),
+		CookedAt:    time.Now().UTC(),
+	}
+}
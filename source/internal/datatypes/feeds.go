@@ -0,0 +1,26 @@
+package datatypes
+
+// FeedConfig switches one global feed on and sets its default window.
+type FeedConfig struct {
+	Enabled    bool `json:"enabled"`
+	WindowDays int  `json:"windowDays,omitempty"` // Default for ?days=, 0 for the feed's own default
+}
+
+// FeedsConfig holds the switches of the global video feeds.
+type FeedsConfig struct {
+	MostViewed        FeedConfig `json:"mostViewed"`
+	MostLiked         FeedConfig `json:"mostLiked"`
+	Trending          FeedConfig `json:"trending"`
+	Random            FeedConfig `json:"random"`
+	RandomPoolMinutes int        `json:"randomPoolMinutes,omitempty"` // How long a user's random order is kept, 0 for 10
+}
+
+// DefaultFeedsConfig is the feed setup of new repositories: every feed on with its default window.
+func DefaultFeedsConfig() FeedsConfig {
+	return FeedsConfig{
+		MostViewed: FeedConfig{Enabled: true},
+		MostLiked:  FeedConfig{Enabled: true},
+		Trending:   FeedConfig{Enabled: true},
+		Random:     FeedConfig{Enabled: true},
+	}
+}
@@ -0,0 +1,19 @@
+package datatypes
+
+import "time"
+
+// LoginAttemptData tracks consecutive failed logins for a single username or client IP.
+type LoginAttemptData struct {
+	Key          string    `json:"key"`      // "user:<username>" or "ip:<address>"
+	Failures     int       `json:"failures"` // Consecutive failures since the last success or unlock
+	LastFailure  time.Time `json:"lastFailure"`
+	BlockedUntil time.Time `json:"blockedUntil,omitzero"` // Zero when no backoff is active
+}
+
+// LoginFailureEvent records a single rejected login so admins can see who is being targeted.
+type LoginFailureEvent struct {
+	Username  string    `json:"username"`
+	IP        string    `json:"ip"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
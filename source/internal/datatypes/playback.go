@@ -0,0 +1,30 @@
+package datatypes
+
+import "time"
+
+// PlaybackCompletePercent is how far into a video a user has to get for it to count as watched.
+const PlaybackCompletePercent = 90.0
+
+// PlaybackState is where a user last was in a video, as reported by the player.
+type PlaybackState struct {
+	VideoID     string    `json:"videoId"`
+	PositionSec float64   `json:"positionSec"` // Last reported position
+	DurationSec float64   `json:"durationSec"` // Length of the video when the position was reported
+	Percent     float64   `json:"percent"`     // PositionSec as a percentage of DurationSec
+	Completed   bool      `json:"completed"`   // Set once the user got past PlaybackCompletePercent
+	WatchedSec  float64   `json:"watchedSec"`  // Time spent watching in the current sitting
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// InProgress reports whether the user started the video and stopped before the end.
+func (p *PlaybackState) InProgress() bool {
+	return p.PositionSec > 0 && p.Percent < PlaybackCompletePercent
+}
+
+// ResumePositionSec is where playback should pick up, 0 when the video was played to the end.
+func (p *PlaybackState) ResumePositionSec() float64 {
+	if !p.InProgress() {
+		return 0
+	}
+	return p.PositionSec
+}
@@ -2,6 +2,7 @@ package datatypes
 
 import (
 	"fmt"
+	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
 )
@@ -14,14 +15,90 @@ const (
 	Unlisted PrivacySetting = "unlisted"
 )
 
+// IsValidPrivacySetting reports whether p is one of the known privacy settings.
+func IsValidPrivacySetting(p PrivacySetting) bool {
+	switch p {
+	case Public, Private, Unlisted:
+		return true
+	}
+	return false
+}
+
+// PlaylistPermission is what a collaborator may do with a shared playlist.
+type PlaylistPermission string
+
+const (
+	PlaylistViewer PlaylistPermission = "viewer" // May open the playlist even when it is private
+	PlaylistEditor PlaylistPermission = "editor" // May also add and remove videos
+)
+
+// IsValidPlaylistPermission reports whether p is one of the known permissions.
+func IsValidPlaylistPermission(p PlaylistPermission) bool {
+	return p == PlaylistViewer || p == PlaylistEditor
+}
+
+// PlaylistShareKind tells whether a playlist is shared with a user or with every member of a space.
+type PlaylistShareKind string
+
+const (
+	ShareWithUser  PlaylistShareKind = "user"
+	ShareWithSpace PlaylistShareKind = "space"
+)
+
+// PlaylistShare grants a user or a space access to a playlist.
+type PlaylistShare struct {
+	Kind       PlaylistShareKind  `json:"kind"`
+	Target     string             `json:"target"` // Account ID for users, space name for spaces
+	Permission PlaylistPermission `json:"permission"`
+}
+
+// MaxPlaylistHistory is how many changes are kept per playlist; older ones are dropped.
+const MaxPlaylistHistory = 200
+
+// PlaylistChange records who added or removed a video.
+type PlaylistChange struct {
+	Time      time.Time `json:"time"`
+	AccountID string    `json:"accountId"`
+	Action    string    `json:"action"` // "add", "remove" or "move"
+	VideoID   string    `json:"videoId"`
+}
+
 // PlaylistData represents a single playlist.
 type PlaylistData struct {
-	ID             string         `json:"id"`
-	Title          string         `json:"title"`
-	Description    string         `json:"description"`
-	Privacy        PrivacySetting `json:"privacy"`
-	VideoIDs       []string       `json:"videoIds"`       // Additional: necessary for backend
-	OwnerAccountId string         `json:"ownerAccountId"` // The owner/user who created this playlist
+	ID             string           `json:"id"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Privacy        PrivacySetting   `json:"privacy"`
+	VideoIDs       []string         `json:"videoIds"`                // Additional: necessary for backend
+	OwnerAccountId string           `json:"ownerAccountId"`          // The owner/user who created this playlist
+	Shares         []PlaylistShare  `json:"shares,omitempty"`        // Users and spaces the owner shared the playlist with
+	History        []PlaylistChange `json:"history,omitempty"`       // Latest video changes, oldest first
+	CoverVideoID   string           `json:"coverVideoId,omitempty"`  // Video shown as cover, the first video when empty
+	CoverFrameSec  *float64         `json:"coverFrameSec,omitempty"` // Frame of the cover video used as image, its thumbnail when nil
+}
+
+// CoverVideo returns the video whose image represents the playlist, or "" for an empty playlist.
+func (p *PlaylistData) CoverVideo() string {
+	if p.CoverVideoID != "" {
+		return p.CoverVideoID
+	}
+	if len(p.VideoIDs) > 0 {
+		return p.VideoIDs[0]
+	}
+	return ""
+}
+
+// RecordChange appends a change to the playlist history, dropping the oldest beyond MaxPlaylistHistory.
+func (p *PlaylistData) RecordChange(accountId, action, videoId string) {
+	p.History = append(p.History, PlaylistChange{
+		Time:      time.Now().UTC(),
+		AccountID: accountId,
+		Action:    action,
+		VideoID:   videoId,
+	})
+	if len(p.History) > MaxPlaylistHistory {
+		p.History = p.History[len(p.History)-MaxPlaylistHistory:]
+	}
 }
 
 // NewPlaylistData returns an example playlist map.
@@ -0,0 +1,84 @@
+package datatypes
+
+import (
+	"math"
+	"time"
+)
+
+// MaxInteractionsPerUser caps how many of a user's most recent videos feed the
+// recommendation model, which keeps the pair counts from growing quadratically.
+const MaxInteractionsPerUser = 200
+
+// RecommendationModel counts how often two videos are watched, saved or liked by the same
+// users. It is built from scratch by a rebuild and then kept current one interaction at a time.
+type RecommendationModel struct {
+	Interactions map[string][]string       `json:"interactions"` // Account ID to the videos they interacted with, oldest first
+	Counts       map[string]int            `json:"counts"`       // Number of users per video
+	Pairs        map[string]map[string]int `json:"pairs"`        // Number of users per pair of videos, stored both ways
+	BuiltAt      time.Time                 `json:"builtAt"`      // Last full rebuild, zero when never built
+	UpdatedAt    time.Time                 `json:"updatedAt"`
+}
+
+// NewRecommendationModel returns an empty model.
+func NewRecommendationModel() *RecommendationModel {
+	return &RecommendationModel{
+		Interactions: map[string][]string{},
+		Counts:       map[string]int{},
+		Pairs:        map[string]map[string]int{},
+	}
+}
+
+// AddInteraction records that the user watched, saved or liked a video. It reports false
+// when the video was already among the user's interactions. Past MaxInteractionsPerUser the
+// oldest interaction is dropped.
+func (m *RecommendationModel) AddInteraction(accountId, videoId string) bool {
+	videos := m.Interactions[accountId]
+	for _, existing := range videos {
+		if existing == videoId {
+			return false
+		}
+	}
+
+	if len(videos) >= MaxInteractionsPerUser {
+		oldest := videos[0]
+		videos = videos[1:]
+		m.Counts[oldest]--
+		for _, other := range videos {
+			m.addPair(oldest, other, -1)
+		}
+	}
+
+	m.Counts[videoId]++
+	for _, other := range videos {
+		m.addPair(videoId, other, 1)
+	}
+	m.Interactions[accountId] = append(videos, videoId)
+	return true
+}
+
+// Neighbors returns the videos that share users with videoId and their cosine similarity to it.
+func (m *RecommendationModel) Neighbors(videoId string) map[string]float64 {
+	neighbors := make(map[string]float64, len(m.Pairs[videoId]))
+	for other, together := range m.Pairs[videoId] {
+		if together <= 0 || m.Counts[videoId] <= 0 || m.Counts[other] <= 0 {
+			continue
+		}
+		neighbors[other] = float64(together) / math.Sqrt(float64(m.Counts[videoId]*m.Counts[other]))
+	}
+	return neighbors
+}
+
+func (m *RecommendationModel) addPair(a, b string, delta int) {
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		if m.Pairs[pair[0]] == nil {
+			m.Pairs[pair[0]] = map[string]int{}
+		}
+		m.Pairs[pair[0]][pair[1]] += delta
+		if m.Pairs[pair[0]][pair[1]] <= 0 {
+			delete(m.Pairs[pair[0]], pair[1])
+			if len(m.Pairs[pair[0]]) == 0 {
+				delete(m.Pairs, pair[0])
+			}
+		}
+	}
+}
@@ -5,14 +5,25 @@ import (
 )
 
 type ConfigData struct {
-	RepositoryName       string    `json:"repositoryName"`
-	Version              string    `json:"version"`
-	ServerHost           string    `json:"serverHost"`
-	ServerPort           int       `json:"serverPort"`
-	RootUser             string    `json:"rootUser"`
-	EnableAuthentication bool      `json:"enableAuthentication"`
-	MaxBucketSize        int       `json:"maxBucketSize"`
-	EnableDocs           bool      `json:"enableDocs"`
-	DataStorageType      string    `json:"dataStorageType"`
-	CreatedAt            time.Time `json:"createdAt"`
+	RepositoryName        string                    `json:"repositoryName"`
+	Version               string                    `json:"version"`
+	ServerHost            string                    `json:"serverHost"`
+	ServerPort            int                       `json:"serverPort"`
+	RootUser              string                    `json:"rootUser"`
+	EnableAuthentication  bool                      `json:"enableAuthentication"`
+	MaxBucketSize         int                       `json:"maxBucketSize"`
+	EnableDocs            bool                      `json:"enableDocs"`
+	DataStorageType       string                    `json:"dataStorageType"`
+	RequireTwoFactorRoles []UserRole                `json:"requireTwoFactorRoles,omitempty"` // Roles that must enroll in TOTP
+	AuditRetentionDays    int                       `json:"auditRetentionDays,omitempty"`    // Days audit events are kept, 0 for 365, -1 forever
+	AuthProviders         []AuthProviderConfig      `json:"authProviders,omitempty"`         // Tried in order; empty means local users only
+	ViewMinWatchSec       int                       `json:"viewMinWatchSec,omitempty"`       // Seconds of playback before a view counts, 0 for 30
+	ViewMinPercent        int                       `json:"viewMinPercent,omitempty"`        // Or this percentage of the video, 0 for 50
+	ViewWindowHours       int                       `json:"viewWindowHours,omitempty"`       // One view per user and video in this many hours, 0 for 6
+	TaskWorkers           int                       `json:"taskWorkers,omitempty"`           // Background tasks run at once, 0 for half the CPUs
+	WorkerToken           string                    `json:"workerToken,omitempty"`           // Shared secret of remote workers; empty refuses them
+	CookingProfiles       map[string]CookingProfile `json:"cookingProfiles,omitempty"`       // Named cooking settings; "default" overrides the built-in one
+	DefaultCookingProfile string                    `json:"defaultCookingProfile,omitempty"` // Profile used when none is named, empty for "default"
+	Feeds                 FeedsConfig               `json:"feeds"`                           // Global feeds; missing feeds are off
+	CreatedAt             time.Time                 `json:"createdAt"`
 }
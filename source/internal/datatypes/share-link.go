@@ -0,0 +1,62 @@
+package datatypes
+
+import (
+	"fmt"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// ShareLinkData is an expiring link that lets someone without an account watch one video.
+// The link itself is HMAC-signed; this record holds its limits and view count.
+type ShareLinkData struct {
+	ID            string    `json:"id"`
+	VideoID       string    `json:"videoId"`
+	CreatedBy     string    `json:"createdBy"` // Account that created the link
+	CreatedAt     time.Time `json:"createdAt"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	StartSec      float64   `json:"startSec,omitempty"` // Optional clip start, 0 for the beginning
+	EndSec        float64   `json:"endSec,omitempty"`   // Optional clip end, 0 for the whole video
+	AllowDownload bool      `json:"allowDownload"`      // Stream-only when false
+	PasswordHash  string    `json:"passwordHash,omitempty"`
+	MaxViews      int       `json:"maxViews,omitempty"` // 0 means unlimited
+	Views         int       `json:"views"`
+	Revoked       bool      `json:"revoked"`
+}
+
+// NewShareLinkData creates a share link record with a fresh ID.
+func NewShareLinkData(videoId, createdBy string, ttl time.Duration) (*ShareLinkData, error) {
+	id, err := gonanoid.New(11)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate id: %w", err)
+	}
+
+	now := time.Now().UTC()
+	return &ShareLinkData{
+		ID:        id,
+		VideoID:   videoId,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// IsExpired reports whether the link can no longer be used.
+func (l *ShareLinkData) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// IsClip reports whether the link is limited to a time range of the video.
+func (l *ShareLinkData) IsClip() bool {
+	return l.StartSec > 0 || l.EndSec > 0
+}
+
+// HasPassword reports whether the link asks for a password before playback.
+func (l *ShareLinkData) HasPassword() bool {
+	return l.PasswordHash != ""
+}
+
+// ViewsExhausted reports whether the view limit has been reached.
+func (l *ShareLinkData) ViewsExhausted() bool {
+	return l.MaxViews > 0 && l.Views >= l.MaxViews
+}
@@ -0,0 +1,165 @@
+package datatypes
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// TaskType is the kind of work a task does.
+type TaskType string
+
+const (
+	TaskIndexing        TaskType = "INDEXING"        // Hash a video file, read its codecs and make its thumbnail and preview
+	TaskCooking         TaskType = "COOKING"         // Make the thumbnail, preview and storyboard of an indexed video
+	TaskRecommendations TaskType = "RECOMMENDATIONS" // Rebuild the recommendation model
+)
+
+// TaskStatus is where a task is in its life.
+type TaskStatus string
+
+const (
+	TaskPending    TaskStatus = "PENDING"    // Waiting for a worker, or for its next attempt
+	TaskProcessing TaskStatus = "PROCESSING" // Running
+	TaskCompleted  TaskStatus = "COMPLETED"
+	TaskFailed     TaskStatus = "FAILED" // Out of attempts, or failed in a way retrying cannot fix
+	TaskCancelled  TaskStatus = "CANCELLED"
+)
+
+// Task priorities. Higher runs first; tasks of the same priority run oldest first.
+const (
+	TaskPriorityLow    = -10
+	TaskPriorityNormal = 0
+	TaskPriorityHigh   = 10
+)
+
+// ErrTaskChanged is returned when a task is stored over a version another process changed
+// since it was read.
+var ErrTaskChanged = errors.New("task was changed by another process")
+
+// DefaultTaskMaxAttempts is how often a task is tried before it fails.
+const DefaultTaskMaxAttempts = 3
+
+// MaxTaskLogEntries caps the log kept per task; older lines are dropped.
+const MaxTaskLogEntries = 100
+
+// TaskLogEntry is one line of a task's log.
+type TaskLogEntry struct {
+	At      time.Time `json:"at"`
+	Message string    `json:"message"`
+}
+
+// MediaProgress is where a running ffmpeg is in the video it processes.
+type MediaProgress struct {
+	OutTimeSec  float64 `json:"outTimeSec"`  // Seconds of the video processed
+	DurationSec float64 `json:"durationSec"` // 0 when unknown
+	Speed       float64 `json:"speed"`       // Times realtime
+	FPS         float64 `json:"fps"`
+}
+
+// TaskData is one unit of background work.
+type TaskData struct {
+	ID              string         `json:"id"`
+	Type            TaskType       `json:"type"`
+	Status          TaskStatus     `json:"status"`
+	Priority        int            `json:"priority"`
+	VideoIDs        []string       `json:"videoIds"`            // Videos the task worked on, once known
+	VideoPath       string         `json:"videoPath,omitempty"` // Absolute path of the video file
+	AccountID       string         `json:"accountId,omitempty"` // Who asked for the task
+	CookAfter       bool           `json:"cookAfter,omitempty"` // Indexing queues cooking when done
+	Profile         string         `json:"profile,omitempty"`   // Cooking profile, empty for the default
+	Only            []string       `json:"only,omitempty"`      // Artifacts to cook, empty for all the profile makes
+	Force           bool           `json:"force,omitempty"`     // Cook the artifacts even when they are up to date
+	ParentID        string         `json:"parentId,omitempty"`  // Task that queued this one
+	Progress        int            `json:"progress"`            // 0-100
+	Media           *MediaProgress `json:"media,omitempty"`     // Live ffmpeg progress, only while processing
+	Attempts        int            `json:"attempts"`
+	MaxAttempts     int            `json:"maxAttempts"`
+	Error           string         `json:"error,omitempty"` // Error of the last attempt
+	CancelRequested bool           `json:"cancelRequested,omitempty"`
+	NotBefore       time.Time      `json:"notBefore"`        // Waits until then before the next attempt
+	Worker          string         `json:"worker,omitempty"` // Process running the task
+	LeaseUntil      time.Time      `json:"leaseUntil"`       // The worker renews this while running; past it the task is given up
+	Logs            []TaskLogEntry `json:"logs"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	StartedAt       time.Time      `json:"startedAt"`  // Start of the last attempt
+	FinishedAt      time.Time      `json:"finishedAt"` // Zero until completed, failed or cancelled
+}
+
+// NewTaskData creates a pending task of the given type with a fresh ID.
+func NewTaskData(taskType TaskType, priority int) (*TaskData, error) {
+	id, err := gonanoid.New(11)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate id: %w", err)
+	}
+
+	now := time.Now().UTC()
+	return &TaskData{
+		ID:          id,
+		Type:        taskType,
+		Status:      TaskPending,
+		Priority:    priority,
+		VideoIDs:    []string{},
+		MaxAttempts: DefaultTaskMaxAttempts,
+		Logs:        []TaskLogEntry{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// IsFinished reports whether the task completed, failed or was cancelled.
+func (t *TaskData) IsFinished() bool {
+	return t.Status == TaskCompleted || t.Status == TaskFailed || t.Status == TaskCancelled
+}
+
+// ForOwner returns the task as the account that asked for it sees it: without the path of
+// the file on the server, the process running it and its log, which only admins see.
+func (t TaskData) ForOwner() TaskData {
+	t.VideoPath = ""
+	t.Worker = ""
+	t.Logs = nil
+	return t
+}
+
+// AddLog appends a line to the task's log.
+func (t *TaskData) AddLog(format string, args ...interface{}) {
+	now := time.Now().UTC()
+	t.Logs = append(t.Logs, TaskLogEntry{At: now, Message: fmt.Sprintf(format, args...)})
+	if len(t.Logs) > MaxTaskLogEntries {
+		t.Logs = t.Logs[len(t.Logs)-MaxTaskLogEntries:]
+	}
+	t.UpdatedAt = now
+}
+
+// TaskFilter selects tasks. Empty fields match every task.
+type TaskFilter struct {
+	Status    TaskStatus
+	Type      TaskType
+	VideoID   string
+	AccountID string
+}
+
+// Matches reports whether the task passes the filter.
+func (f TaskFilter) Matches(task *TaskData) bool {
+	if f.Status != "" && task.Status != f.Status {
+		return false
+	}
+	if f.Type != "" && task.Type != f.Type {
+		return false
+	}
+	if f.AccountID != "" && task.AccountID != f.AccountID {
+		return false
+	}
+	if f.VideoID != "" {
+		for _, videoId := range task.VideoIDs {
+			if videoId == f.VideoID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
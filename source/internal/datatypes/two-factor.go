@@ -0,0 +1,13 @@
+package datatypes
+
+import "time"
+
+// TwoFactorData holds a user's TOTP enrollment state.
+type TwoFactorData struct {
+	Enabled            bool      `json:"enabled"`
+	Secret             string    `json:"secret,omitempty"`             // Active base32 TOTP secret
+	PendingSecret      string    `json:"pendingSecret,omitempty"`      // Secret awaiting its first valid code
+	RecoveryCodeHashes []string  `json:"recoveryCodeHashes,omitempty"` // SHA-256 of unused recovery codes
+	LastUsedStep       int64     `json:"lastUsedStep,omitempty"`       // Last accepted time step, blocks code replay
+	EnabledAt          time.Time `json:"enabledAt,omitzero"`
+}
@@ -1,29 +1,62 @@
 package datatypes
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+type UserRole string
+
+const (
+	RoleAdmin UserRole = "admin"
+	RoleUser  UserRole = "user"
+)
+
 // UserData represents a user's profile and associated data.
 type UserData struct {
-	DisplayName  string    `json:"displayName"`
-	Username     string    `json:"username"`
-	AccountID    string    `json:"accountId"`
-	PasswordHash string    `json:"passwordHash"`
-	Favorites    []string  `json:"favorites"` // Stores VideoIDs
-	CreatedAt    time.Time `json:"createdAt"`
-	LastLoginAt  time.Time `json:"lastLoginAt,omitempty"` // omitempty for zero-valued time
+	DisplayName   string         `json:"displayName"`
+	Username      string         `json:"username"`
+	AccountID     string         `json:"accountId"`
+	PasswordHash  string         `json:"passwordHash"`
+	Role          UserRole       `json:"role,omitempty"` // Empty is treated as RoleUser
+	Favorites     []string       `json:"favorites"`      // Stores VideoIDs
+	CreatedAt     time.Time      `json:"createdAt"`
+	LastLoginAt   time.Time      `json:"lastLoginAt,omitempty"`   // omitempty for zero-valued time
+	TwoFactor     *TwoFactorData `json:"twoFactor,omitempty"`     // nil until the user starts TOTP enrollment
+	AuthProvider  string         `json:"authProvider,omitempty"`  // Provider that provisioned the user, empty for local users
+	ExternalID    string         `json:"externalId,omitempty"`    // Subject of the user within AuthProvider
+	Disabled      bool           `json:"disabled,omitempty"`      // Disabled users cannot log in or use tokens
+	HistoryPaused bool           `json:"historyPaused,omitempty"` // Watching is not recorded in the history while set
+}
+
+// GetRole returns the user's role, defaulting to RoleUser for older records.
+func (u *UserData) GetRole() UserRole {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
 }
 
-// NewUserData returns an initialized UserData struct for a new user.
-func NewUserData(username string, password string) UserData {
+// IsValidUserRole reports whether role is one of the known user roles.
+func IsValidUserRole(role UserRole) bool {
+	return role == RoleAdmin || role == RoleUser
+}
+
+// IsTwoFactorEnabled reports whether the user has completed TOTP enrollment.
+func (u *UserData) IsTwoFactorEnabled() bool {
+	return u.TwoFactor != nil && u.TwoFactor.Enabled
+}
+
+// NewUserData returns an initialized UserData struct for a new user. It fails when the
+// password cannot be hashed, e.g. bcrypt.ErrPasswordTooLong for more than 72 bytes.
+func NewUserData(username string, password string) (UserData, error) {
 	// Hash password
 	hashedPass, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return UserData{}
+		return UserData{}, fmt.Errorf("cannot create hash from password: %w", err)
 	}
 
 	// Generate a new account ID
@@ -33,8 +66,9 @@ func NewUserData(username string, password string) UserData {
 		Username:     username,
 		AccountID:    accountId,
 		PasswordHash: string(hashedPass),
+		Role:         RoleUser,
 		CreatedAt:    time.Now().UTC(),
 		LastLoginAt:  time.Time{}, // Zero value for LastLoginAt
 		Favorites:    []string{},  // Initialize with empty slice
-	}
+	}, nil
 }
@@ -0,0 +1,10 @@
+package datatypes
+
+// StatsDayLayout is the format of the UTC day keys of the daily video statistics.
+const StatsDayLayout = "2006-01-02"
+
+// VideoDailyStats counts the views and downloads of one video on one UTC day.
+type VideoDailyStats struct {
+	Views     int `json:"views"`
+	Downloads int `json:"downloads"`
+}
@@ -27,12 +27,14 @@ type VideoData struct {
 	VideoID        string      `json:"videoId"`        // Unique identifier for the
 	Tags           []string    `json:"tags"`           // Tags for categorization and search
 	Codecs         VideoCodecs `json:"codecs"`         // Codec information
-	IsCooked       bool        `json:"isCooked"`       // Indicates if the video is processed (cooked)
+	IsCooked       bool        `json:"isCooked"`       // The storyboard is in place, so players can show seek bar thumbnails
 	UploaderID     string      `json:"uploaderId"`     // ID of the owner account
 	TotalViews     int         `json:"totalViews"`     // Total number of views
 	TotalDownloads int         `json:"totalDownloads"` // Total number of downloads
 	IsPublic       bool        `json:"isPublic"`       // Indicates if the video is public
 	UploadedAt     time.Time   `json:"uploadedAt"`     // Timestamp of upload
+
+	Artifacts map[string]CookedArtifact `json:"artifacts,omitempty"` // How each cooked artifact was made, by artifact name
 }
 
 // NewVideoData returns an initialized VideoData struct.
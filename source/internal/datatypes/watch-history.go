@@ -0,0 +1,40 @@
+package datatypes
+
+import (
+	"fmt"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// WatchSessionGap is how long a video can go unreported before watching it again starts a new history entry.
+const WatchSessionGap = 30 * time.Minute
+
+// WatchEvent is one entry of a user's watch history: a video watched in one sitting.
+type WatchEvent struct {
+	ID         string    `json:"id"`
+	VideoID    string    `json:"videoId"`
+	StartedAt  time.Time `json:"startedAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"` // Last time the player reported progress
+	WatchedSec float64   `json:"watchedSec"` // Time spent watching, not the position reached
+}
+
+// NewWatchEvent creates a history entry with a fresh ID.
+func NewWatchEvent(videoId string, at time.Time) (*WatchEvent, error) {
+	id, err := gonanoid.New(11)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate id: %w", err)
+	}
+	return &WatchEvent{ID: id, VideoID: videoId, StartedAt: at, LastSeenAt: at}, nil
+}
+
+// InRange reports whether the entry started within [since, until). Zero bounds are open.
+func (e *WatchEvent) InRange(since, until time.Time) bool {
+	if !since.IsZero() && e.StartedAt.Before(since) {
+		return false
+	}
+	if !until.IsZero() && !e.StartedAt.Before(until) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+// Package playlistfile reads and writes playlists in the M3U/M3U8 and XSPF formats
+// understood by external players.
+package playlistfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format is a playlist file format.
+type Format string
+
+const (
+	FormatM3U8 Format = "m3u8"
+	FormatXSPF Format = "xspf"
+)
+
+// ParseFormat accepts "m3u", "m3u8" and "xspf", case-insensitively.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "m3u", "m3u8":
+		return FormatM3U8, nil
+	case "xspf":
+		return FormatXSPF, nil
+	}
+	return "", fmt.Errorf("unknown playlist format %q, use m3u8 or xspf", s)
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == FormatXSPF {
+		return "application/xspf+xml"
+	}
+	return "application/vnd.apple.mpegurl"
+}
+
+// Entry is one item of a playlist file.
+type Entry struct {
+	Location    string // URL or file path
+	Title       string
+	DurationSec int    // 0 when unknown
+	Hash        string // SHA-256 of the file content, which is also the video ID; empty when unknown
+}
+
+// Playlist is the content of a playlist file.
+type Playlist struct {
+	Title   string
+	Entries []Entry
+}
+
+// hashTag carries the content hash of the next entry in M3U files. Players ignore unknown tags.
+const hashTag = "#EXT-X-OVA-SHA256:"
+
+// Write encodes the playlist in the given format.
+func Write(w io.Writer, format Format, p Playlist) error {
+	if format == FormatXSPF {
+		return writeXSPF(w, p)
+	}
+	return writeM3U8(w, p)
+}
+
+func writeM3U8(w io.Writer, p Playlist) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	if p.Title != "" {
+		fmt.Fprintf(&b, "#PLAYLIST:%s\n", oneLine(p.Title))
+	}
+	for _, e := range p.Entries {
+		duration := e.DurationSec
+		if duration <= 0 {
+			duration = -1
+		}
+		fmt.Fprintf(&b, "#EXTINF:%d,%s\n", duration, oneLine(e.Title))
+		if e.Hash != "" {
+			fmt.Fprintf(&b, "%s%s\n", hashTag, e.Hash)
+		}
+		fmt.Fprintf(&b, "%s\n", oneLine(e.Location))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type xspfPlaylist struct {
+	XMLName   xml.Name    `xml:"playlist"`
+	Version   string      `xml:"version,attr"`
+	Namespace string      `xml:"xmlns,attr"`
+	Title     string      `xml:"title,omitempty"`
+	Tracks    []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   []string `xml:"location"`
+	Identifier []string `xml:"identifier,omitempty"`
+	Title      string   `xml:"title,omitempty"`
+	Duration   int      `xml:"duration,omitempty"` // milliseconds
+}
+
+const xspfHashPrefix = "urn:sha256:"
+
+func writeXSPF(w io.Writer, p Playlist) error {
+	doc := xspfPlaylist{
+		Version:   "1",
+		Namespace: "http://xspf.org/ns/0/",
+		Title:     p.Title,
+	}
+	for _, e := range p.Entries {
+		track := xspfTrack{
+			Location: []string{e.Location},
+			Title:    e.Title,
+			Duration: e.DurationSec * 1000,
+		}
+		if e.Hash != "" {
+			track.Identifier = []string{xspfHashPrefix + e.Hash}
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Parse decodes an M3U, M3U8 or XSPF playlist, telling them apart by content.
+func Parse(data []byte) (Playlist, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return parseXSPF(trimmed)
+	}
+	return parseM3U(trimmed)
+}
+
+func parseM3U(data []byte) (Playlist, error) {
+	var p Playlist
+	var pending Entry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line == "#EXTM3U":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			p.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			duration, title, _ := strings.Cut(info, ",")
+			// Attributes such as tvg-name="..." may follow the duration
+			duration, _, _ = strings.Cut(duration, " ")
+			if seconds, err := strconv.Atoi(duration); err == nil && seconds > 0 {
+				pending.DurationSec = seconds
+			}
+			pending.Title = strings.TrimSpace(title)
+		case strings.HasPrefix(line, hashTag):
+			pending.Hash = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, hashTag)))
+		case strings.HasPrefix(line, "#"):
+		default:
+			pending.Location = line
+			p.Entries = append(p.Entries, pending)
+			pending = Entry{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Playlist{}, fmt.Errorf("failed to read m3u playlist: %w", err)
+	}
+	return p, nil
+}
+
+func parseXSPF(data []byte) (Playlist, error) {
+	var doc xspfPlaylist
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return Playlist{}, fmt.Errorf("failed to read xspf playlist: %w", err)
+	}
+
+	p := Playlist{Title: strings.TrimSpace(doc.Title)}
+	for _, track := range doc.Tracks {
+		entry := Entry{
+			Title:       strings.TrimSpace(track.Title),
+			DurationSec: track.Duration / 1000,
+		}
+		if len(track.Location) > 0 {
+			entry.Location = strings.TrimSpace(track.Location[0])
+		}
+		for _, id := range track.Identifier {
+			id = strings.TrimSpace(id)
+			if strings.HasPrefix(strings.ToLower(id), xspfHashPrefix) {
+				entry.Hash = strings.ToLower(id[len(xspfHashPrefix):])
+				break
+			}
+		}
+		p.Entries = append(p.Entries, entry)
+	}
+	return p, nil
+}
+
+// oneLine keeps values from breaking the line-based M3U format.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,124 @@
+package playlistfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteParseRoundTrip(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	playlist := Playlist{
+		Title: "Road trip",
+		Entries: []Entry{
+			{Location: "videos/day one.mp4", Title: "Day one", DurationSec: 95, Hash: hash},
+			{Location: "file:///srv/media/day%20two.mp4", Title: "Day two"},
+			{Location: "https://ova.example/api/v1/stream/" + hash + "?grant=a.b&x=<1>", Title: "Streamed & shared", DurationSec: 3},
+		},
+	}
+
+	for _, format := range []Format{FormatM3U8, FormatXSPF} {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, playlist); err != nil {
+			t.Fatalf("%s: write: %v", format, err)
+		}
+		got, err := Parse(buf.Bytes())
+		if err != nil {
+			t.Fatalf("%s: parse: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, playlist) {
+			t.Errorf("%s: got %+v, want %+v\n%s", format, got, playlist, buf.String())
+		}
+	}
+}
+
+func TestWriteKeepsM3ULinesWhole(t *testing.T) {
+	playlist := Playlist{
+		Title:   "two\nlines",
+		Entries: []Entry{{Location: "a.mp4\n#EXTINF:1,injected", Title: "line\r\nbreak"}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatM3U8, playlist); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := Playlist{
+		Title:   "two lines",
+		Entries: []Entry{{Location: "a.mp4 #EXTINF:1,injected", Title: "line  break"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Playlist
+	}{
+		{
+			name: "xspf without a title",
+			data: "\xef\xbb\xbf<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				`<playlist xmlns="http://xspf.org/ns/0/" xmlns:vlc="http://www.videolan.org/vlc/playlist/ns/0/" version="1">
+  <trackList>
+    <track>
+      <location>file:///home/me/Videos/clip.mp4</location>
+      <identifier>URN:SHA256:ABCD</identifier>
+      <duration>61500</duration>
+    </track>
+    <track>
+      <location> ../other/clip.webm </location>
+      <title> Other </title>
+    </track>
+  </trackList>
+</playlist>`,
+			want: Playlist{Entries: []Entry{
+				{Location: "file:///home/me/Videos/clip.mp4", DurationSec: 61, Hash: "abcd"},
+				{Location: "../other/clip.webm", Title: "Other"},
+			}},
+		},
+		{
+			name: "m3u from other players",
+			data: "#EXTM3U\r\n#EXTINF:-1 tvg-name=\"x\",Live\r\nhttp://example.com/live\r\n# a comment\r\nplain/relative.mp4\r\n",
+			want: Playlist{Entries: []Entry{
+				{Location: "http://example.com/live", Title: "Live"},
+				{Location: "plain/relative.mp4"},
+			}},
+		},
+		{
+			name: "empty",
+			data: "",
+			want: Playlist{},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse([]byte(tt.data))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := Parse([]byte("<playlist><trackList>")); err == nil {
+		t.Errorf("broken xspf parsed without an error")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"m3u": FormatM3U8, " M3U8 ": FormatM3U8, "XSPF": FormatXSPF} {
+		if got, err := ParseFormat(input); err != nil || got != want {
+			t.Errorf("ParseFormat(%q): got %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("pls"); err == nil {
+		t.Errorf("ParseFormat(\"pls\") did not fail")
+	}
+}
@@ -0,0 +1,201 @@
+package repo
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+)
+
+// A video is visible to an account when:
+//   - auth is disabled, or the account is an admin or the video's uploader, and otherwise
+//   - its space is open, or the account is a member of the private space, and
+//   - the video is public, or the account is a member of its space.
+//
+// Anonymous requests (empty account ID) only see public videos in open spaces.
+
+// videoAccess caches what is needed to answer visibility checks for one account,
+// so listings do not reload users, spaces and lookups for every video.
+type videoAccess struct {
+	accountId    string
+	unrestricted bool
+	lookups      map[string]string
+	spaces       map[string]datatypes.SpaceData
+}
+
+// newVideoAccess prepares visibility checks for an account.
+func (r *RepoManager) newVideoAccess(accountId string) (*videoAccess, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	access := &videoAccess{
+		accountId:    accountId,
+		unrestricted: !r.AuthEnabled || (accountId != "" && r.IsAdmin(accountId)),
+	}
+	if access.unrestricted {
+		return access, nil
+	}
+
+	lookups, err := r.diskDataStorage.GetAllVideoLookups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load video lookups: %w", err)
+	}
+	spaces, err := r.diskDataStorage.GetAllSpaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spaces: %w", err)
+	}
+
+	access.lookups = lookups
+	access.spaces = make(map[string]datatypes.SpaceData, len(spaces))
+	for _, space := range spaces {
+		access.spaces[space.SpaceName] = space
+	}
+	return access, nil
+}
+
+// canView reports whether the account may see the video.
+func (a *videoAccess) canView(video *datatypes.VideoData) bool {
+	if a.unrestricted {
+		return true
+	}
+	if a.accountId != "" && video.UploaderID == a.accountId {
+		return true
+	}
+
+	space, hasSettings := a.spaces[SpaceNameFromPath(a.lookups[video.VideoID])]
+	member := hasSettings && a.isMember(space)
+	if hasSettings && space.SpaceSettings.IsPrivate && !member {
+		return false
+	}
+	return video.IsPublic || member
+}
+
+func (a *videoAccess) isMember(space datatypes.SpaceData) bool {
+	if a.accountId == "" {
+		return false
+	}
+	if space.SpaceOwner == a.accountId {
+		return true
+	}
+	for _, member := range space.MemberIds {
+		if member == a.accountId {
+			return true
+		}
+	}
+	return false
+}
+
+// CanViewVideo reports whether an account may see, stream and download a video.
+func (r *RepoManager) CanViewVideo(accountId string, video *datatypes.VideoData) bool {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return false
+	}
+	return access.canView(video)
+}
+
+// CanManageVideo reports whether an account may change a video's settings:
+// only its uploader and admins can.
+func (r *RepoManager) CanManageVideo(accountId string, video *datatypes.VideoData) bool {
+	if !r.AuthEnabled {
+		return true
+	}
+	if accountId == "" {
+		return false
+	}
+	return video.UploaderID == accountId || r.IsAdmin(accountId)
+}
+
+// GetVisibleVideoByID returns a video only if the account may see it.
+// Hidden videos are reported as not found so their IDs are not confirmed.
+func (r *RepoManager) GetVisibleVideoByID(accountId, videoId string) (*datatypes.VideoData, error) {
+	video, err := r.GetVideoByID(videoId)
+	if err != nil {
+		return nil, err
+	}
+	if !r.CanViewVideo(accountId, video) {
+		return nil, fmt.Errorf("video %q not found", videoId)
+	}
+	return video, nil
+}
+
+// FilterVisibleVideos keeps the videos the account may see, preserving order.
+func (r *RepoManager) FilterVisibleVideos(accountId string, videos []datatypes.VideoData) []datatypes.VideoData {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return []datatypes.VideoData{}
+	}
+
+	visible := make([]datatypes.VideoData, 0, len(videos))
+	for i := range videos {
+		if access.canView(&videos[i]) {
+			visible = append(visible, videos[i])
+		}
+	}
+	return visible
+}
+
+// FilterVisibleVideoRefs is FilterVisibleVideos for slices of pointers.
+func (r *RepoManager) FilterVisibleVideoRefs(accountId string, videos []*datatypes.VideoData) []*datatypes.VideoData {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return []*datatypes.VideoData{}
+	}
+
+	visible := make([]*datatypes.VideoData, 0, len(videos))
+	for _, video := range videos {
+		if video != nil && access.canView(video) {
+			visible = append(visible, video)
+		}
+	}
+	return visible
+}
+
+// VisibleVideoIDChecker returns a check of whether the account may see a video by ID, with
+// access loaded once for all the checks. Unknown videos are not visible. It is meant for one
+// request and is not safe for concurrent use.
+func (r *RepoManager) VisibleVideoIDChecker(accountId string) func(videoId string) bool {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return func(string) bool { return false }
+	}
+
+	checked := map[string]bool{}
+	return func(videoId string) bool {
+		visible, ok := checked[videoId]
+		if !ok {
+			video, err := r.GetVideoByID(videoId)
+			visible = err == nil && video != nil && access.canView(video)
+			checked[videoId] = visible
+		}
+		return visible
+	}
+}
+
+// SetVideoVisibility makes a video public or private.
+func (r *RepoManager) SetVideoVisibility(videoId string, isPublic bool) (*datatypes.VideoData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	video, err := r.diskDataStorage.GetVideoByID(videoId)
+	if err != nil {
+		return nil, err
+	}
+	video.IsPublic = isPublic
+	if err := r.diskDataStorage.UpdateVideo(*video); err != nil {
+		return nil, fmt.Errorf("failed to update video: %w", err)
+	}
+	return video, nil
+}
+
+// CanViewPlaylist reports whether an account may open a playlist by its ID.
+// Public and unlisted playlists can be opened by anyone; private ones only by
+// their owner, admins and the users and spaces it is shared with.
+// Videos inside are still filtered by CanViewVideo.
+func (r *RepoManager) CanViewPlaylist(accountId string, playlist *datatypes.PlaylistData) bool {
+	switch playlist.Privacy {
+	case datatypes.Public, datatypes.Unlisted:
+		return true
+	}
+	return r.playlistPermission(accountId, playlist) != ""
+}
@@ -0,0 +1,201 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/user"
+	"ova-cli/source/internal/datatypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// auditPruneInterval is how often RecordAudit applies the retention policy.
+const auditPruneInterval = 24 * time.Hour
+
+// DefaultAuditRetentionDays applies when auditRetentionDays is not set.
+const DefaultAuditRetentionDays = 365
+
+const (
+	// failedLoginAuditWindow is the shortest gap between two failed-login events from one IP.
+	// Failures in between are counted into the next event instead of getting their own.
+	failedLoginAuditWindow = 10 * time.Minute
+	// maxFailedLoginAuditIPs caps the IPs tracked; beyond it all other IPs share one window.
+	maxFailedLoginAuditIPs = 10000
+)
+
+// failedLoginAudit tracks the failed logins of one IP since its last audit event.
+type failedLoginAudit struct {
+	RecordedAt time.Time
+	Folded     int // Failures not written to the log since RecordedAt
+}
+
+// RecordAudit appends an event to the audit log. before and after are optional
+// snapshots of the changed object and are stored as JSON.
+func (r *RepoManager) RecordAudit(actorId, action, ip string, targetIds []string, before, after any) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	event := datatypes.AuditEvent{
+		ActorID:   actorId,
+		Action:    action,
+		TargetIDs: targetIds,
+		IP:        ip,
+	}
+	if actorId != "" {
+		if user, err := r.diskDataStorage.GetUserByAccountID(actorId); err == nil {
+			event.ActorName = user.Username
+		}
+	}
+	return r.appendAudit(event, before, after)
+}
+
+// RecordCLIAudit appends an event for an admin action run from the command line. No account
+// is behind it, so the actor name is "cli:" followed by the operating system user.
+func (r *RepoManager) RecordCLIAudit(action string, targetIds []string, before, after any) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	event := datatypes.AuditEvent{
+		ActorName: "cli",
+		Action:    action,
+		TargetIDs: targetIds,
+	}
+	if current, err := user.Current(); err == nil {
+		event.ActorName += ":" + current.Username
+	}
+	return r.appendAudit(event, before, after)
+}
+
+// RecordFailedLoginAudit logs an anonymous failed login. An IP gets at most one event per
+// failedLoginAuditWindow, so guessing cannot flood the log; the event's "failures" counts
+// this attempt and the ones folded since the IP's previous event.
+func (r *RepoManager) RecordFailedLoginAudit(username, reason, ip string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	now := time.Now()
+	r.auditMu.Lock()
+	if r.failedLoginAudits == nil {
+		r.failedLoginAudits = make(map[string]*failedLoginAudit)
+	}
+	key := ip
+	if _, tracked := r.failedLoginAudits[key]; !tracked && len(r.failedLoginAudits) >= maxFailedLoginAuditIPs {
+		for id, entry := range r.failedLoginAudits {
+			if id != "" && entry.Folded == 0 && now.Sub(entry.RecordedAt) >= failedLoginAuditWindow {
+				delete(r.failedLoginAudits, id)
+			}
+		}
+		if len(r.failedLoginAudits) >= maxFailedLoginAuditIPs {
+			key = "" // too many IPs at once: they share one window
+		}
+	}
+
+	entry := r.failedLoginAudits[key]
+	if entry != nil && now.Sub(entry.RecordedAt) < failedLoginAuditWindow {
+		entry.Folded++
+		r.auditMu.Unlock()
+		return nil
+	}
+	failures := 1
+	if entry != nil {
+		failures += entry.Folded
+	}
+	r.failedLoginAudits[key] = &failedLoginAudit{RecordedAt: now}
+	r.auditMu.Unlock()
+
+	return r.RecordAudit("", datatypes.AuditLoginFailed, ip, nil, nil, map[string]interface{}{
+		"username": username,
+		"reason":   reason,
+		"failures": failures,
+	})
+}
+
+// appendAudit stamps the event, stores the snapshots and writes it to the log.
+func (r *RepoManager) appendAudit(event datatypes.AuditEvent, before, after any) error {
+	event.ID = uuid.NewString()
+	event.Time = time.Now().UTC()
+
+	var err error
+	if event.Before, err = auditSnapshot(before); err != nil {
+		return err
+	}
+	if event.After, err = auditSnapshot(after); err != nil {
+		return err
+	}
+
+	if err := r.diskDataStorage.AppendAuditEvent(event); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+
+	r.pruneAuditIfDue()
+	return nil
+}
+
+// QueryAuditEvents returns audit events matching the filter, newest first.
+func (r *RepoManager) QueryAuditEvents(filter datatypes.AuditFilter) ([]datatypes.AuditEvent, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.QueryAuditEvents(filter)
+}
+
+// AuditRetentionDays returns how many days audit events are kept, with the default
+// applied. It returns 0 when the config keeps them forever.
+func (r *RepoManager) AuditRetentionDays() int {
+	switch days := r.configs.AuditRetentionDays; {
+	case days < 0:
+		return 0
+	case days == 0:
+		return DefaultAuditRetentionDays
+	default:
+		return days
+	}
+}
+
+// PruneAuditEvents drops events older than the retention.
+// It does nothing when the config keeps events forever.
+func (r *RepoManager) PruneAuditEvents() (int, error) {
+	if !r.IsDataStorageInitialized() {
+		return 0, fmt.Errorf("data storage is not initialized")
+	}
+	days := r.AuditRetentionDays()
+	if days == 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -days)
+	return r.diskDataStorage.PruneAuditEvents(cutoff)
+}
+
+// pruneAuditIfDue runs PruneAuditEvents at most once per auditPruneInterval.
+func (r *RepoManager) pruneAuditIfDue() {
+	r.auditMu.Lock()
+	due := time.Since(r.lastAuditPrune) > auditPruneInterval
+	if due {
+		r.lastAuditPrune = time.Now()
+	}
+	r.auditMu.Unlock()
+
+	if due {
+		_, _ = r.PruneAuditEvents()
+	}
+}
+
+// auditSnapshot encodes a snapshot, leaving nil values empty.
+func auditSnapshot(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		return raw, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	return data, nil
+}
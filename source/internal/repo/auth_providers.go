@@ -0,0 +1,251 @@
+package repo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"ova-cli/source/internal/authprovider"
+	"ova-cli/source/internal/datatypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCStateTTL is how long a user may take at the identity provider before the login is dropped.
+const OIDCStateTTL = 10 * time.Minute
+
+// oidcState ties an OIDC callback back to the login that started it.
+type oidcState struct {
+	Provider  string
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// AuthProviderInfo is the public description of a configured provider.
+type AuthProviderInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Redirect bool   `json:"redirect"` // true when login goes through a browser redirect
+}
+
+// GetAuthProviders returns the configured providers, building them on first use.
+// Without configuration only the local user store is used.
+func (r *RepoManager) GetAuthProviders() ([]authprovider.Provider, error) {
+	r.providersMu.Lock()
+	defer r.providersMu.Unlock()
+
+	if r.authProviders != nil {
+		return r.authProviders, nil
+	}
+
+	configs := r.configs.AuthProviders
+	if len(configs) == 0 {
+		configs = []datatypes.AuthProviderConfig{{Name: datatypes.AuthProviderLocal, Type: datatypes.AuthProviderLocal}}
+	}
+
+	providers := make([]authprovider.Provider, 0, len(configs))
+	seen := make(map[string]bool)
+	for _, cfg := range configs {
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("duplicate auth provider name %q", cfg.Name)
+		}
+		seen[cfg.Name] = true
+
+		provider, err := authprovider.New(cfg, r)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	r.authProviders = providers
+	return providers, nil
+}
+
+// GetAuthProvider returns a configured provider by name.
+func (r *RepoManager) GetAuthProvider(name string) (authprovider.Provider, error) {
+	providers, err := r.GetAuthProviders()
+	if err != nil {
+		return nil, err
+	}
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("auth provider %q not found", name)
+}
+
+// ListAuthProviders describes the configured providers for login screens.
+func (r *RepoManager) ListAuthProviders() ([]AuthProviderInfo, error) {
+	providers, err := r.GetAuthProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]AuthProviderInfo, 0, len(providers))
+	for _, provider := range providers {
+		_, redirect := provider.(authprovider.RedirectProvider)
+		infos = append(infos, AuthProviderInfo{
+			Name:     provider.Name(),
+			Type:     provider.Type(),
+			Redirect: redirect,
+		})
+	}
+	return infos, nil
+}
+
+// AuthenticatePassword checks a username and password against the password providers in
+// configured order, or only against providerName when it is set, and returns the matching user.
+func (r *RepoManager) AuthenticatePassword(username, password, providerName string) (*datatypes.UserData, error) {
+	providers, err := r.GetAuthProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, provider := range providers {
+		if providerName != "" && provider.Name() != providerName {
+			continue
+		}
+		passwordProvider, ok := provider.(authprovider.PasswordProvider)
+		if !ok {
+			continue
+		}
+
+		identity, err := passwordProvider.Authenticate(username, password)
+		if errors.Is(err, authprovider.ErrInvalidCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return r.ProvisionIdentity(identity, provider)
+	}
+	return nil, authprovider.ErrInvalidCredentials
+}
+
+// ProvisionIdentity maps a provider identity to a stored user. Users from external
+// providers are created on first login with the provider's default role.
+func (r *RepoManager) ProvisionIdentity(identity *authprovider.Identity, provider authprovider.Provider) (*datatypes.UserData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	if provider.Type() == datatypes.AuthProviderLocal {
+		user, err := r.diskDataStorage.GetUserByAccountID(identity.Subject)
+		if err != nil {
+			return nil, err
+		}
+		return checkUserEnabled(user)
+	}
+
+	// Two first logins of the same identity at once must not both create a user
+	r.usersMu.Lock()
+	defer r.usersMu.Unlock()
+
+	users, err := r.diskDataStorage.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].AuthProvider == identity.Provider && users[i].ExternalID == identity.Subject {
+			return checkUserEnabled(&users[i])
+		}
+	}
+	for _, user := range users {
+		if user.Username == identity.Username {
+			return nil, fmt.Errorf("username %q is already taken by another account", identity.Username)
+		}
+	}
+
+	// External users never log in with a local password, so they get a random unusable one.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("could not generate password: %w", err)
+	}
+	userdata, err := datatypes.NewUserData(identity.Username, hex.EncodeToString(secret))
+	if err != nil {
+		return nil, err
+	}
+	userdata.DisplayName = identity.DisplayName
+	userdata.Role = provider.DefaultRole()
+	userdata.AuthProvider = identity.Provider
+	userdata.ExternalID = identity.Subject
+
+	if err := r.diskDataStorage.InsertUser(&userdata); err != nil {
+		return nil, fmt.Errorf("failed to provision user: %w", err)
+	}
+	return &userdata, nil
+}
+
+// BeginRedirectLogin returns the URL that starts a browser login at a redirect provider,
+// and the state the callback must bring back. The caller binds the state to the browser.
+func (r *RepoManager) BeginRedirectLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, err := r.GetAuthProvider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+	redirectProvider, ok := provider.(authprovider.RedirectProvider)
+	if !ok {
+		return "", "", fmt.Errorf("auth provider %q does not support redirect login", providerName)
+	}
+
+	state := uuid.NewString()
+	nonce := uuid.NewString()
+
+	r.challengesMu.Lock()
+	if r.oidcStates == nil {
+		r.oidcStates = make(map[string]*oidcState)
+	}
+	now := time.Now()
+	for id, pending := range r.oidcStates {
+		if now.After(pending.ExpiresAt) {
+			delete(r.oidcStates, id)
+		}
+	}
+	r.oidcStates[state] = &oidcState{Provider: providerName, Nonce: nonce, ExpiresAt: now.Add(OIDCStateTTL)}
+	r.challengesMu.Unlock()
+
+	authURL, err := redirectProvider.AuthCodeURL(ctx, state, nonce)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+// CompleteRedirectLogin finishes a browser login from the provider callback and returns the user.
+func (r *RepoManager) CompleteRedirectLogin(ctx context.Context, providerName, state, code string) (*datatypes.UserData, error) {
+	r.challengesMu.Lock()
+	pending, ok := r.oidcStates[state]
+	delete(r.oidcStates, state)
+	r.challengesMu.Unlock()
+
+	if !ok || time.Now().After(pending.ExpiresAt) || pending.Provider != providerName {
+		return nil, fmt.Errorf("login state not found or expired")
+	}
+
+	provider, err := r.GetAuthProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	redirectProvider, ok := provider.(authprovider.RedirectProvider)
+	if !ok {
+		return nil, fmt.Errorf("auth provider %q does not support redirect login", providerName)
+	}
+
+	identity, err := redirectProvider.Exchange(ctx, code, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	return r.ProvisionIdentity(identity, provider)
+}
+
+// checkUserEnabled refuses accounts an admin has disabled.
+func checkUserEnabled(user *datatypes.UserData) (*datatypes.UserData, error) {
+	if user.Disabled {
+		return nil, fmt.Errorf("account %q is disabled", user.Username)
+	}
+	return user, nil
+}
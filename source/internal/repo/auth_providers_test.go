@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"sync"
+	"testing"
+
+	"ova-cli/source/internal/authprovider"
+	"ova-cli/source/internal/datatypes"
+)
+
+type stubProvider struct{}
+
+func (stubProvider) Name() string                    { return "corp" }
+func (stubProvider) Type() string                    { return datatypes.AuthProviderOIDC }
+func (stubProvider) DefaultRole() datatypes.UserRole { return datatypes.RoleUser }
+
+// TestProvisionIdentityOnce logs the same new identity in many times at once, as a user
+// double-clicking through a slow login would. Only one account may be created.
+func TestProvisionIdentityOnce(t *testing.T) {
+	r, err := NewRepoManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("open repository: %v", err)
+	}
+	identity := &authprovider.Identity{Provider: "corp", Subject: "sub-1", Username: "dana"}
+
+	const logins = 8
+	accounts := make([]string, logins)
+	var wg sync.WaitGroup
+	for i := range logins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := r.ProvisionIdentity(identity, stubProvider{})
+			if err != nil {
+				t.Errorf("login %d: %v", i, err)
+				return
+			}
+			accounts[i] = user.AccountID
+		}()
+	}
+	wg.Wait()
+
+	for i := range accounts {
+		if accounts[i] != accounts[0] {
+			t.Fatalf("logins got accounts %v, want the same one", accounts)
+		}
+	}
+	users, err := r.GetAllUsers()
+	if err != nil {
+		t.Fatalf("get users: %v", err)
+	}
+	created := 0
+	for _, user := range users {
+		if user.ExternalID == identity.Subject {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Errorf("created %d users for the identity, want 1", created)
+	}
+}
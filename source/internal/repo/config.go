@@ -23,6 +23,7 @@ func (r *RepoManager) GetDefaultConfig() *datatypes.ConfigData {
 			EnableAuthentication: true,
 			MaxBucketSize:        30,
 			DataStorageType:      "jsondb",
+			Feeds:                datatypes.DefaultFeedsConfig(),
 			CreatedAt:            time.Now(),
 		}
 	}
@@ -43,6 +44,7 @@ func (r *RepoManager) GetDefaultConfigTemplate() (*datatypes.ConfigData, error)
 			EnableAuthentication: true,
 			DataStorageType:      "jsondb",
 			MaxBucketSize:        30,
+			Feeds:                datatypes.DefaultFeedsConfig(),
 			CreatedAt:            time.Now(),
 		}
 
@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// DefaultCookingProfileName names the built-in profile, which the config may override.
+const DefaultCookingProfileName = "default"
+
+// ErrUnknownCookingProfile is returned for a profile that is neither in the config nor built in.
+var ErrUnknownCookingProfile = errors.New("unknown cooking profile")
+
+// GetCookingProfile returns a cooking profile by name, with the settings it leaves out filled
+// from the defaults. An empty name takes the config's defaultCookingProfile. It also returns
+// the resolved name, which is what the video's artifact records keep.
+func (r *RepoManager) GetCookingProfile(name string) (string, datatypes.CookingProfile, error) {
+	if name == "" {
+		name = r.configs.DefaultCookingProfile
+	}
+	if name == "" {
+		name = DefaultCookingProfileName
+	}
+
+	if profile, ok := r.configs.CookingProfiles[name]; ok {
+		for _, artifact := range profile.Artifacts {
+			if !datatypes.IsValidArtifact(artifact) {
+				return "", datatypes.CookingProfile{}, fmt.Errorf("cooking profile %q has unknown artifact %q", name, artifact)
+			}
+		}
+		return name, profile.WithDefaults(), nil
+	}
+	if name == DefaultCookingProfileName {
+		return name, datatypes.DefaultCookingProfile(), nil
+	}
+	return "", datatypes.CookingProfile{}, fmt.Errorf("%w %q", ErrUnknownCookingProfile, name)
+}
+
+// CookingOptions says how to cook a video.
+type CookingOptions struct {
+	Profile string   // Cooking profile, empty for the default
+	Only    []string // Artifacts to cook, empty for all the profile makes
+	Force   bool     // Cook the artifacts even when they are up to date
+}
+
+// cookingOptionsOf reads the cooking options of a task.
+func cookingOptionsOf(task *datatypes.TaskData) CookingOptions {
+	return CookingOptions{Profile: task.Profile, Only: task.Only, Force: task.Force}
+}
+
+// CheckCookingOptions checks that the profile and artifacts of the options exist and
+// returns the name of the profile they resolve to.
+func (r *RepoManager) CheckCookingOptions(options CookingOptions) (string, error) {
+	for _, artifact := range options.Only {
+		if !datatypes.IsValidArtifact(artifact) {
+			return "", fmt.Errorf("unknown artifact %q, expected one of %s", artifact, strings.Join(datatypes.AllArtifacts, ", "))
+		}
+	}
+	name, _, err := r.GetCookingProfile(options.Profile)
+	return name, err
+}
+
+// GetCookingProfileNames lists the profiles of the config and the built-in one, sorted.
+func (r *RepoManager) GetCookingProfileNames() []string {
+	names := []string{DefaultCookingProfileName}
+	for name := range r.configs.CookingProfiles {
+		if name != DefaultCookingProfileName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// cookingArtifacts returns the artifacts the options ask for, in the order they are made:
+// those named in Only, even when the profile does not make them, or else all of the
+// profile's. Unless forced, artifacts that are up to date are left out.
+func (r *RepoManager) cookingArtifacts(video *datatypes.VideoData, profile datatypes.CookingProfile, options CookingOptions) []string {
+	var artifacts []string
+	for _, artifact := range datatypes.AllArtifacts {
+		if len(options.Only) > 0 && !slices.Contains(options.Only, artifact) {
+			continue
+		}
+		if len(options.Only) == 0 && !profile.Produces(artifact) {
+			continue
+		}
+		if options.Force || !r.isArtifactUpToDate(video, artifact, profile) {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts
+}
+
+// isArtifactUpToDate reports whether an artifact of the video is on disk and was made with
+// the settings of the profile. Files made before artifacts were recorded count as made with
+// the built-in defaults.
+func (r *RepoManager) isArtifactUpToDate(video *datatypes.VideoData, artifact string, profile datatypes.CookingProfile) bool {
+	if !r.artifactExists(video.VideoID, artifact) {
+		return false
+	}
+	made, ok := video.Artifacts[artifact]
+	fingerprint := made.Fingerprint
+	if !ok {
+		fingerprint = datatypes.DefaultCookingProfile().Fingerprint(artifact)
+	}
+	return fingerprint == profile.Fingerprint(artifact)
+}
+
+// GetArtifactStatus returns the cook status of each artifact of a video, compared against
+// the default cooking profile.
+func (r *RepoManager) GetArtifactStatus(videoId string) ([]datatypes.ArtifactStatus, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	video, err := r.diskDataStorage.GetVideoByID(videoId)
+	if err != nil {
+		return nil, err
+	}
+	_, profile, err := r.GetCookingProfile("")
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]datatypes.ArtifactStatus, 0, len(datatypes.AllArtifacts))
+	for _, artifact := range datatypes.AllArtifacts {
+		made := video.Artifacts[artifact]
+		statuses = append(statuses, datatypes.ArtifactStatus{
+			Artifact:    artifact,
+			Present:     r.artifactExists(videoId, artifact),
+			Profile:     made.Profile,
+			Fingerprint: made.Fingerprint,
+			CookedAt:    made.CookedAt,
+			UpToDate:    r.isArtifactUpToDate(video, artifact, profile),
+		})
+	}
+	return statuses, nil
+}
+
+// artifactExists reports whether the file that shows an artifact of the video is on disk.
+func (r *RepoManager) artifactExists(videoId, artifact string) bool {
+	var path string
+	switch artifact {
+	case datatypes.ArtifactThumbnail:
+		path = r.GetThumbnailFilePathByVideoID(videoId)
+	case datatypes.ArtifactPreview:
+		path = r.GetPreviewFilePathByVideoID(videoId)
+	case datatypes.ArtifactStoryboard:
+		path = filepath.Join(r.GetPreviewThumbnailsFolderPathByVideoID(videoId), "thumbnails.vtt")
+	default:
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// recordArtifact stores that an artifact of the video was made with the profile.
+func (r *RepoManager) recordArtifact(videoId, artifact, profileName string, profile datatypes.CookingProfile) error {
+	r.artifactsMu.Lock()
+	defer r.artifactsMu.Unlock()
+
+	video, err := r.diskDataStorage.GetVideoByID(videoId)
+	if err != nil {
+		return err
+	}
+	video.RecordArtifact(artifact, profileName, profile)
+	if artifact == datatypes.ArtifactStoryboard {
+		video.IsCooked = true
+	}
+	if err := r.diskDataStorage.UpdateVideo(*video); err != nil {
+		return fmt.Errorf("failed to record %s of video %s: %w", artifact, videoId, err)
+	}
+	return nil
+}
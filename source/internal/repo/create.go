@@ -40,7 +40,11 @@ func (r *RepoManager) CreateRepoWithUser(username, password string, useBoltDB bo
 		storageType = "boltdb"
 	}
 
-	userdata := datatypes.NewUserData(username, password)
+	userdata, err := datatypes.NewUserData(username, password)
+	if err != nil {
+		return err
+	}
+	userdata.Role = datatypes.RoleAdmin
 
 	// Create default config with desired storage type
 	if err := r.CreateDefaultConfigFileWithStorageType(userdata.AccountID, storageType); err != nil {
@@ -15,12 +15,18 @@ func (r *RepoManager) OnInit() error {
 		return fmt.Errorf("failed to get total video count: %w", err)
 	}
 
+	// Apply the audit log retention policy
+	r.pruneAuditIfDue()
+
 	return nil
 }
 
 // OnShutdown gracefully shuts down the repository, ensuring all data is persisted and resources are released.
 func (r *RepoManager) OnShutdown() error {
 
+	// Queue running tasks again so the next start resumes them
+	r.StopTaskRunner()
+
 	// Attempt to save all user session data to disk
 	if err := r.SaveUserSessionOnDisk(); err != nil {
 		return fmt.Errorf("failed to save session data: %w", err)
@@ -1,13 +1,14 @@
 package indexer
 
 import (
+	"context"
 	"fmt"
 	"ova-cli/source/internal/thirdparty"
 )
 
-func (s *Indexer) generatePreview(videoPath string) error {
+func (s *Indexer) generatePreview(ctx context.Context, videoPath string) error {
 
-	if err := thirdparty.GenerateImageFromVideo(videoPath, "output.webm", 50); err != nil {
+	if err := thirdparty.GenerateImageFromVideo(ctx, videoPath, "output.webm", 50, 320, 100); err != nil {
 		return fmt.Errorf("failed to generate thumbnail for %s: %w", videoPath, err)
 	}
 
@@ -102,3 +102,14 @@ func (r *RepoManager) GetVideoMarkerFilePathByVideoID(videoID string) string {
 	// Return the video marker path directly without checking if the file exists
 	return videoMarkerPath
 }
+
+// GetPlaylistCoverFilePath returns where the cover frame chosen for a playlist is stored.
+func (r *RepoManager) GetPlaylistCoverFilePath(playlistID string) string {
+	return filepath.Join(r.rootDir, ".ova-repo", "storage", "playlist_covers", playlistID+".jpg")
+}
+
+// getTaskArtifactsDir returns where files uploaded by a remote worker for a task are kept
+// until the task completes.
+func (r *RepoManager) getTaskArtifactsDir(taskID string) string {
+	return filepath.Join(r.rootDir, ".ova-repo", "tmp", "task_artifacts", taskID)
+}
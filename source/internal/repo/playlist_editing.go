@@ -0,0 +1,328 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/thirdparty"
+	"path/filepath"
+	"strings"
+)
+
+// PlaylistDetails holds the fields of a playlist its owner may change. Nil fields are left as they are.
+type PlaylistDetails struct {
+	Title       *string
+	Description *string
+	Privacy     *datatypes.PrivacySetting
+}
+
+// updatePlaylist loads a playlist, applies fn and saves it, holding playlistMu so concurrent
+// edits by collaborators are not lost. ownerOnly restricts the change to the owner and admins,
+// otherwise editors may make it too.
+func (r *RepoManager) updatePlaylist(accountId, playlistId string, ownerOnly bool, fn func(playlist *datatypes.PlaylistData) error) (*datatypes.PlaylistData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.playlistMu.Lock()
+	defer r.playlistMu.Unlock()
+
+	playlist, err := r.diskDataStorage.GetPlaylist(playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := r.CanEditPlaylist(accountId, playlist)
+	if ownerOnly {
+		allowed = !r.AuthEnabled || playlist.OwnerAccountId == accountId || r.IsAdmin(accountId)
+	}
+	if !allowed {
+		return nil, fmt.Errorf("playlist with id %q cannot be changed by user %q", playlistId, accountId)
+	}
+
+	if err := fn(playlist); err != nil {
+		return nil, err
+	}
+	if err := r.diskDataStorage.SavePlaylist(*playlist); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// MovePlaylistVideo moves a video to a zero-based position. Positions past the end move it last.
+func (r *RepoManager) MovePlaylistVideo(accountId, playlistId, videoId string, position int) (*datatypes.PlaylistData, error) {
+	return r.updatePlaylist(accountId, playlistId, false, func(playlist *datatypes.PlaylistData) error {
+		from := indexOf(playlist.VideoIDs, videoId)
+		if from < 0 {
+			return fmt.Errorf("video %q not found in playlist %q", videoId, playlistId)
+		}
+
+		rest := append(playlist.VideoIDs[:from:from], playlist.VideoIDs[from+1:]...)
+		playlist.VideoIDs = insertAt(rest, []string{videoId}, position)
+		playlist.RecordChange(accountId, "move", videoId)
+		return nil
+	})
+}
+
+// SwapPlaylistVideos exchanges the positions of two videos.
+func (r *RepoManager) SwapPlaylistVideos(accountId, playlistId, firstId, secondId string) (*datatypes.PlaylistData, error) {
+	return r.updatePlaylist(accountId, playlistId, false, func(playlist *datatypes.PlaylistData) error {
+		i, j := indexOf(playlist.VideoIDs, firstId), indexOf(playlist.VideoIDs, secondId)
+		if i < 0 || j < 0 {
+			return fmt.Errorf("both videos must be in playlist %q", playlistId)
+		}
+
+		playlist.VideoIDs[i], playlist.VideoIDs[j] = playlist.VideoIDs[j], playlist.VideoIDs[i]
+		playlist.RecordChange(accountId, "move", firstId)
+		playlist.RecordChange(accountId, "move", secondId)
+		return nil
+	})
+}
+
+// InsertPlaylistVideos adds videos at a zero-based position, keeping their order.
+// A negative position or one past the end appends them. Videos already in the
+// playlist and duplicates in videoIds are skipped; the added IDs are returned.
+func (r *RepoManager) InsertPlaylistVideos(accountId, playlistId string, videoIds []string, position int) (*datatypes.PlaylistData, []string, error) {
+	if len(videoIds) == 0 {
+		return nil, nil, fmt.Errorf("no videos to add")
+	}
+
+	added := []string{}
+	playlist, err := r.updatePlaylist(accountId, playlistId, false, func(playlist *datatypes.PlaylistData) error {
+		seen := make(map[string]bool, len(playlist.VideoIDs)+len(videoIds))
+		for _, id := range playlist.VideoIDs {
+			seen[id] = true
+		}
+		for _, id := range videoIds {
+			if !seen[id] {
+				seen[id] = true
+				added = append(added, id)
+			}
+		}
+
+		if position < 0 {
+			position = len(playlist.VideoIDs)
+		}
+		playlist.VideoIDs = insertAt(playlist.VideoIDs, added, position)
+		for _, id := range added {
+			playlist.RecordChange(accountId, "add", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return playlist, added, nil
+}
+
+// UpdatePlaylistDetails changes the title, description or privacy of a playlist. Owner only.
+func (r *RepoManager) UpdatePlaylistDetails(accountId, playlistId string, details PlaylistDetails) (*datatypes.PlaylistData, error) {
+	return r.updatePlaylist(accountId, playlistId, true, func(playlist *datatypes.PlaylistData) error {
+		if details.Title != nil {
+			title := strings.TrimSpace(*details.Title)
+			if title == "" {
+				return fmt.Errorf("title cannot be empty")
+			}
+			playlist.Title = title
+		}
+		if details.Description != nil {
+			playlist.Description = *details.Description
+		}
+		if details.Privacy != nil {
+			if !datatypes.IsValidPrivacySetting(*details.Privacy) {
+				return fmt.Errorf("unknown privacy setting %q", *details.Privacy)
+			}
+			playlist.Privacy = *details.Privacy
+		}
+		return nil
+	})
+}
+
+// SetPlaylistCover makes a video of the playlist its cover. With a frame time, that frame
+// is extracted and used as the cover image, otherwise the video's thumbnail is. Cancelling
+// ctx stops the frame extraction.
+func (r *RepoManager) SetPlaylistCover(ctx context.Context, accountId, playlistId, videoId string, frameSec *float64) (*datatypes.PlaylistData, error) {
+	checkCover := func(playlist *datatypes.PlaylistData) error {
+		if indexOf(playlist.VideoIDs, videoId) < 0 {
+			return fmt.Errorf("video %q not found in playlist %q", videoId, playlistId)
+		}
+		return nil
+	}
+
+	// The frame is extracted before playlistMu is taken, since ffmpeg can run for seconds and
+	// the lock is shared by every playlist. Only the finished file is swapped in under it.
+	coverPath := r.GetPlaylistCoverFilePath(playlistId)
+	var framePath string
+	if frameSec != nil {
+		if *frameSec < 0 {
+			return nil, fmt.Errorf("frame time cannot be negative")
+		}
+		if _, err := r.readPlaylistForEdit(accountId, playlistId, checkCover); err != nil {
+			return nil, err
+		}
+
+		var err error
+		framePath, err = r.extractPlaylistCoverFrame(ctx, playlistId, videoId, *frameSec)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(framePath) // a no-op once it has been renamed to the cover
+	}
+
+	return r.updatePlaylist(accountId, playlistId, false, func(playlist *datatypes.PlaylistData) error {
+		if err := checkCover(playlist); err != nil {
+			return err
+		}
+
+		if framePath != "" {
+			if err := os.Rename(framePath, coverPath); err != nil {
+				return fmt.Errorf("failed to save cover frame: %w", err)
+			}
+		} else {
+			_ = os.Remove(coverPath)
+		}
+
+		playlist.CoverVideoID = videoId
+		playlist.CoverFrameSec = frameSec
+		return nil
+	})
+}
+
+// readPlaylistForEdit runs the checks of updatePlaylist without taking playlistMu, so a
+// slow preparation step can fail early. updatePlaylist must still repeat them.
+func (r *RepoManager) readPlaylistForEdit(accountId, playlistId string, check func(playlist *datatypes.PlaylistData) error) (*datatypes.PlaylistData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	playlist, err := r.diskDataStorage.GetPlaylist(playlistId)
+	if err != nil {
+		return nil, err
+	}
+	if !r.CanEditPlaylist(accountId, playlist) {
+		return nil, fmt.Errorf("playlist with id %q cannot be changed by user %q", playlistId, accountId)
+	}
+	return playlist, check(playlist)
+}
+
+// extractPlaylistCoverFrame writes one frame of the video to a temporary file next to the
+// playlist's cover and returns its path.
+func (r *RepoManager) extractPlaylistCoverFrame(ctx context.Context, playlistId, videoId string, frameSec float64) (string, error) {
+	videoPath, err := r.GetVideoPathByID(videoId)
+	if err != nil {
+		return "", err
+	}
+
+	coverPath := r.GetPlaylistCoverFilePath(playlistId)
+	if err := os.MkdirAll(filepath.Dir(coverPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create cover directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(coverPath), playlistId+"-*.jpg")
+	if err != nil {
+		return "", fmt.Errorf("failed to create cover file: %w", err)
+	}
+	tmp.Close()
+
+	// Covers look like thumbnails, so they follow the default cooking profile
+	settings := datatypes.DefaultCookingProfile().Thumbnail
+	if _, profile, err := r.GetCookingProfile(""); err == nil {
+		settings = profile.Thumbnail
+	}
+	if err := thirdparty.GenerateImageFromVideo(ctx, filepath.Join(r.rootDir, videoPath), tmp.Name(), frameSec, settings.Width, settings.Quality); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to extract cover frame: %w", err)
+	}
+	return tmp.Name(), nil
+}
+
+// ClearPlaylistCover goes back to using the first video as cover.
+func (r *RepoManager) ClearPlaylistCover(accountId, playlistId string) (*datatypes.PlaylistData, error) {
+	return r.updatePlaylist(accountId, playlistId, false, func(playlist *datatypes.PlaylistData) error {
+		_ = os.Remove(r.GetPlaylistCoverFilePath(playlistId))
+		playlist.CoverVideoID = ""
+		playlist.CoverFrameSec = nil
+		return nil
+	})
+}
+
+// GetPlaylistCoverPath returns the image file to show for a playlist the viewer may open:
+// the chosen frame, or the thumbnail of the cover video.
+func (r *RepoManager) GetPlaylistCoverPath(viewerId, playlistId string) (string, error) {
+	playlist, err := r.GetPlaylist(viewerId, playlistId)
+	if err != nil {
+		return "", err
+	}
+
+	videoId := playlist.CoverVideo()
+	if videoId == "" {
+		return "", fmt.Errorf("playlist %q has no cover", playlistId)
+	}
+	if _, err := r.GetVisibleVideoByID(viewerId, videoId); err != nil {
+		return "", fmt.Errorf("playlist %q has no cover", playlistId)
+	}
+
+	if playlist.CoverFrameSec != nil && playlist.CoverVideoID != "" {
+		coverPath := r.GetPlaylistCoverFilePath(playlistId)
+		if _, err := os.Stat(coverPath); err == nil {
+			return coverPath, nil
+		}
+	}
+	return r.GetThumbnailFilePathByVideoID(videoId), nil
+}
+
+// DuplicatePlaylist copies a playlist the account may open into a new private playlist it owns.
+// Videos the account may not see are left out; shares and history are not copied.
+func (r *RepoManager) DuplicatePlaylist(accountId, playlistId, title string) (*datatypes.PlaylistData, error) {
+	source, err := r.GetPlaylist(accountId, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	videos, err := r.GetVideosByIDs(source.VideoIDs)
+	if err != nil {
+		return nil, err
+	}
+	videoIds := []string{}
+	for _, video := range r.FilterVisibleVideoRefs(accountId, videos) {
+		videoIds = append(videoIds, video.VideoID)
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = source.Title + " (copy)"
+	}
+
+	copied, err := datatypes.NewPlaylistData(accountId, title, source.Description, videoIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize playlist data: %w", err)
+	}
+	if indexOf(videoIds, source.CoverVideoID) >= 0 && source.CoverFrameSec == nil {
+		copied.CoverVideoID = source.CoverVideoID
+	}
+	return r.diskDataStorage.InsertPlaylist(copied)
+}
+
+func indexOf(ids []string, id string) int {
+	for i, candidate := range ids {
+		if candidate == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertAt returns ids with items inserted at position, clamped to the bounds of ids.
+func insertAt(ids, items []string, position int) []string {
+	if position < 0 {
+		position = 0
+	}
+	if position > len(ids) {
+		position = len(ids)
+	}
+
+	result := make([]string, 0, len(ids)+len(items))
+	result = append(result, ids[:position]...)
+	result = append(result, items...)
+	return append(result, ids[position:]...)
+}
@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"strings"
+)
+
+// playlistPermission returns what an account may do with a playlist through ownership or sharing.
+// Owners and admins are editors; an empty result means the playlist is not shared with the account.
+// When a user is reached by several shares, the strongest permission wins.
+func (r *RepoManager) playlistPermission(accountId string, playlist *datatypes.PlaylistData) datatypes.PlaylistPermission {
+	if !r.AuthEnabled || (accountId != "" && (playlist.OwnerAccountId == accountId || r.IsAdmin(accountId))) {
+		return datatypes.PlaylistEditor
+	}
+	if accountId == "" {
+		return ""
+	}
+
+	var permission datatypes.PlaylistPermission
+	for _, share := range playlist.Shares {
+		if permission == datatypes.PlaylistEditor {
+			break
+		}
+		switch share.Kind {
+		case datatypes.ShareWithUser:
+			if share.Target != accountId {
+				continue
+			}
+		case datatypes.ShareWithSpace:
+			if !r.isSpaceMember(accountId, share.Target) {
+				continue
+			}
+		default:
+			continue
+		}
+		if share.Permission == datatypes.PlaylistEditor || permission == "" {
+			permission = share.Permission
+		}
+	}
+	return permission
+}
+
+// isSpaceMember reports whether an account owns or belongs to a space.
+func (r *RepoManager) isSpaceMember(accountId, spaceName string) bool {
+	space, err := r.diskDataStorage.GetSpace(spaceName)
+	if err != nil {
+		return false
+	}
+	if space.SpaceOwner == accountId {
+		return true
+	}
+	for _, member := range space.MemberIds {
+		if member == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+// CanEditPlaylist reports whether an account may add and remove videos of a playlist.
+func (r *RepoManager) CanEditPlaylist(accountId string, playlist *datatypes.PlaylistData) bool {
+	return r.playlistPermission(accountId, playlist) == datatypes.PlaylistEditor
+}
+
+// SharePlaylist shares a playlist owned by the user with a user or a space.
+// Sharing again with the same target changes its permission.
+func (r *RepoManager) SharePlaylist(ownerId, playlistId string, share datatypes.PlaylistShare) (*datatypes.PlaylistData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	if !datatypes.IsValidPlaylistPermission(share.Permission) {
+		return nil, fmt.Errorf("permission must be viewer or editor")
+	}
+
+	share.Target = strings.TrimSpace(share.Target)
+	switch share.Kind {
+	case datatypes.ShareWithUser:
+		if _, err := r.diskDataStorage.GetUserByAccountID(share.Target); err != nil {
+			return nil, fmt.Errorf("user %q not found", share.Target)
+		}
+		if share.Target == ownerId {
+			return nil, fmt.Errorf("a playlist cannot be shared with its owner")
+		}
+	case datatypes.ShareWithSpace:
+		if share.Target == "" || strings.ContainsAny(share.Target, "/\\") {
+			return nil, fmt.Errorf("invalid space name %q", share.Target)
+		}
+	default:
+		return nil, fmt.Errorf("share kind must be user or space")
+	}
+
+	playlist, err := r.diskDataStorage.GetPlaylistByID(ownerId, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]datatypes.PlaylistShare, 0, len(playlist.Shares)+1)
+	for _, existing := range playlist.Shares {
+		if existing.Kind != share.Kind || existing.Target != share.Target {
+			shares = append(shares, existing)
+		}
+	}
+	shares = append(shares, share)
+
+	if err := r.diskDataStorage.UpdatePlaylistShares(ownerId, playlistId, shares); err != nil {
+		return nil, err
+	}
+	playlist.Shares = shares
+	return playlist, nil
+}
+
+// UnsharePlaylist stops sharing a playlist owned by the user with a user or a space.
+func (r *RepoManager) UnsharePlaylist(ownerId, playlistId string, kind datatypes.PlaylistShareKind, target string) (*datatypes.PlaylistData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	playlist, err := r.diskDataStorage.GetPlaylistByID(ownerId, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]datatypes.PlaylistShare, 0, len(playlist.Shares))
+	for _, existing := range playlist.Shares {
+		if existing.Kind != kind || existing.Target != target {
+			shares = append(shares, existing)
+		}
+	}
+	if len(shares) == len(playlist.Shares) {
+		return nil, fmt.Errorf("playlist %q is not shared with %s %q", playlistId, kind, target)
+	}
+
+	if err := r.diskDataStorage.UpdatePlaylistShares(ownerId, playlistId, shares); err != nil {
+		return nil, err
+	}
+	playlist.Shares = shares
+	return playlist, nil
+}
+
+// GetPlaylistsSharedWith returns the playlists other users shared with an account,
+// directly or through one of its spaces.
+func (r *RepoManager) GetPlaylistsSharedWith(accountId string) ([]datatypes.PlaylistData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	playlists, err := r.diskDataStorage.GetAllPlaylists()
+	if err != nil {
+		return nil, err
+	}
+
+	shared := []datatypes.PlaylistData{}
+	for i := range playlists {
+		playlist := &playlists[i]
+		if playlist.OwnerAccountId == accountId || len(playlist.Shares) == 0 {
+			continue
+		}
+		// Only look at the shares, so admins do not see every playlist here
+		for _, share := range playlist.Shares {
+			if (share.Kind == datatypes.ShareWithUser && share.Target == accountId) ||
+				(share.Kind == datatypes.ShareWithSpace && r.isSpaceMember(accountId, share.Target)) {
+				shared = append(shared, *playlist)
+				break
+			}
+		}
+	}
+	return shared, nil
+}
+
+// GetPlaylistPermission returns the permission an account has on a playlist it can open:
+// "owner" for the owner, otherwise viewer or editor.
+func (r *RepoManager) GetPlaylistPermission(accountId string, playlist *datatypes.PlaylistData) string {
+	if playlist.OwnerAccountId == accountId {
+		return "owner"
+	}
+	if permission := r.playlistPermission(accountId, playlist); permission != "" {
+		return string(permission)
+	}
+	return string(datatypes.PlaylistViewer)
+}
@@ -0,0 +1,273 @@
+package repo
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/filehash"
+	"ova-cli/source/internal/playlistfile"
+	"ova-cli/source/internal/utils"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Playlist export modes: entries point either at the stream endpoint or at the video files.
+const (
+	ExportModeStream = "stream"
+	ExportModePath   = "path"
+)
+
+// StreamGrantTTL is how long the stream URLs of an exported playlist keep working.
+const StreamGrantTTL = 24 * time.Hour
+
+// PlaylistExportOptions controls where exported entries point.
+type PlaylistExportOptions struct {
+	Mode    string // ExportModeStream (default) or ExportModePath
+	BaseURL string // Server address used for stream URLs, e.g. "https://host:8080"
+	// API token the export was requested with. Stream URLs then carry a grant for their video
+	// only, so players without cookies can play them without seeing the token itself.
+	Token *datatypes.APITokenData
+}
+
+// UnresolvedEntry is a playlist file entry that did not match any video the account can see.
+type UnresolvedEntry struct {
+	Index    int    `json:"index"` // Zero-based position in the file
+	Location string `json:"location"`
+	Title    string `json:"title,omitempty"`
+}
+
+// ExportPlaylist builds a playlist file for a playlist the viewer may open.
+// Videos the viewer may not see are left out.
+func (r *RepoManager) ExportPlaylist(viewerId, playlistId string, opts PlaylistExportOptions) (playlistfile.Playlist, error) {
+	playlist, err := r.GetPlaylist(viewerId, playlistId)
+	if err != nil {
+		return playlistfile.Playlist{}, err
+	}
+
+	videos, err := r.GetVideosByIDs(playlist.VideoIDs)
+	if err != nil {
+		return playlistfile.Playlist{}, err
+	}
+	videos = r.FilterVisibleVideoRefs(viewerId, videos)
+
+	grantExpiry := time.Now().Add(StreamGrantTTL)
+	if opts.Token != nil && opts.Token.ExpiresAt.Before(grantExpiry) {
+		grantExpiry = opts.Token.ExpiresAt
+	}
+
+	out := playlistfile.Playlist{Title: playlist.Title}
+	for _, video := range videos {
+		location, err := r.exportLocation(video.VideoID, opts, grantExpiry)
+		if err != nil {
+			return playlistfile.Playlist{}, err
+		}
+		out.Entries = append(out.Entries, playlistfile.Entry{
+			Location:    location,
+			Title:       video.Title,
+			DurationSec: video.Codecs.DurationSec,
+			Hash:        video.VideoID,
+		})
+	}
+	return out, nil
+}
+
+func (r *RepoManager) exportLocation(videoId string, opts PlaylistExportOptions, grantExpiry time.Time) (string, error) {
+	if opts.Mode == ExportModePath {
+		relativePath, err := r.GetVideoPathByID(videoId)
+		if err != nil {
+			return "", err
+		}
+		return filepath.ToSlash(relativePath), nil
+	}
+
+	location := strings.TrimRight(opts.BaseURL, "/") + "/api/v1/stream/" + url.PathEscape(videoId)
+	if opts.Token != nil {
+		grant, err := r.createStreamGrant(opts.Token.ID, videoId, grantExpiry)
+		if err != nil {
+			return "", err
+		}
+		location += "?grant=" + url.QueryEscape(grant)
+	}
+	return location, nil
+}
+
+// createStreamGrant signs "<tokenId>.<expiry>.<signature>", which streams one video as the
+// owner of an API token. It is signed with the share link key.
+func (r *RepoManager) createStreamGrant(tokenId, videoId string, expiresAt time.Time) (string, error) {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	sig, err := r.signShare("stream", tokenId, videoId, exp)
+	if err != nil {
+		return "", err
+	}
+	return tokenId + "." + exp + "." + sig, nil
+}
+
+// VerifyStreamGrant returns the API token a grant from an exported playlist streams the video
+// as. The grant stops working when it expires or when the token is revoked or expires.
+func (r *RepoManager) VerifyStreamGrant(videoId, grant string) (*datatypes.APITokenData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	parts := strings.Split(grant, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed stream grant")
+	}
+	tokenId, exp, sig := parts[0], parts[1], parts[2]
+
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return nil, fmt.Errorf("stream grant has expired")
+	}
+	if !r.verifyShare(sig, "stream", tokenId, videoId, exp) {
+		return nil, fmt.Errorf("invalid stream grant")
+	}
+
+	token, err := r.diskDataStorage.GetAPITokenByID(tokenId)
+	if err != nil || token.IsExpired() {
+		return nil, fmt.Errorf("stream grant token is revoked or expired")
+	}
+	owner, err := r.diskDataStorage.GetUserByAccountID(token.AccountID)
+	if err != nil || owner.Disabled {
+		return nil, fmt.Errorf("stream grant owner is disabled or missing")
+	}
+	return token, nil
+}
+
+// ResolvePlaylistEntries matches playlist file entries to indexed videos the account may see.
+// An entry matches by its content hash, by a stream or download URL of this server, by its
+// path relative to the repository, or by hashing the file it points at inside the repository.
+// Duplicates are dropped; entries that match nothing are returned as unresolved.
+func (r *RepoManager) ResolvePlaylistEntries(accountId string, entries []playlistfile.Entry) ([]string, []UnresolvedEntry, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	lookups, err := r.diskDataStorage.GetAllVideoLookups()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load video lookups: %w", err)
+	}
+	byPath := make(map[string]string, len(lookups))
+	for videoId, relativePath := range lookups {
+		byPath[cleanPlaylistPath(relativePath)] = videoId
+	}
+
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved := []string{}
+	unresolved := []UnresolvedEntry{}
+	seen := map[string]bool{}
+	for i, entry := range entries {
+		videoId := r.resolvePlaylistEntry(entry, byPath)
+		video, err := r.diskDataStorage.GetVideoByID(videoId)
+		// Hidden videos are reported like missing ones so their existence is not confirmed
+		if videoId == "" || err != nil || !access.canView(video) {
+			unresolved = append(unresolved, UnresolvedEntry{Index: i, Location: entry.Location, Title: entry.Title})
+			continue
+		}
+		if !seen[videoId] {
+			seen[videoId] = true
+			resolved = append(resolved, videoId)
+		}
+	}
+	return resolved, unresolved, nil
+}
+
+func (r *RepoManager) resolvePlaylistEntry(entry playlistfile.Entry, byPath map[string]string) string {
+	if entry.Hash != "" && r.CheckVideoIndexedByID(entry.Hash) {
+		return entry.Hash
+	}
+
+	location := entry.Location
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		switch u.Scheme {
+		case "http", "https":
+			for _, prefix := range []string{"/api/v1/stream/", "/api/v1/download/"} {
+				if id, ok := strings.CutPrefix(u.Path, prefix); ok {
+					return strings.Trim(id, "/")
+				}
+			}
+			return ""
+		case "file":
+			location = u.Path
+		default:
+			return ""
+		}
+	}
+
+	// Paths relative to the repository, as written by the path export mode
+	if !filepath.IsAbs(location) {
+		if videoId, ok := byPath[cleanPlaylistPath(location)]; ok {
+			return videoId
+		}
+	}
+
+	// Absolute paths, or files moved since they were indexed: only files inside the
+	// repository are looked at, so imports cannot probe the rest of the disk.
+	absolutePath := location
+	if !filepath.IsAbs(absolutePath) {
+		absolutePath = filepath.Join(r.rootDir, absolutePath)
+	}
+	relativePath, err := utils.MakeRelative(r.rootDir, absolutePath)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	if videoId, ok := byPath[cleanPlaylistPath(relativePath)]; ok {
+		return videoId
+	}
+	if info, err := os.Stat(absolutePath); err != nil || info.IsDir() {
+		return ""
+	}
+	hash, err := filehash.Sha256FileHash(absolutePath)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// ImportPlaylist creates a private playlist from a playlist file. The title defaults to the
+// one in the file. Unresolved entries are reported and left out.
+func (r *RepoManager) ImportPlaylist(accountId string, data []byte, title string) (*datatypes.PlaylistData, []UnresolvedEntry, error) {
+	parsed, err := playlistfile.Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	videoIds, unresolved, err := r.ResolvePlaylistEntries(accountId, parsed.Entries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = parsed.Title
+	}
+	if title == "" {
+		title = "Imported playlist"
+	}
+
+	playlist, err := datatypes.NewPlaylistData(accountId, title, "", videoIds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize playlist data: %w", err)
+	}
+	for _, videoId := range videoIds {
+		playlist.RecordChange(accountId, "add", videoId)
+	}
+	created, err := r.diskDataStorage.InsertPlaylist(playlist)
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, unresolved, nil
+}
+
+// cleanPlaylistPath normalizes a relative path so lookups match regardless of slashes.
+func cleanPlaylistPath(p string) string {
+	return strings.TrimPrefix(path.Clean(strings.ReplaceAll(p, "\\", "/")), "./")
+}
@@ -0,0 +1,365 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// Reasons given with recommendations.
+const (
+	ReasonCoWatched = "co-watched" // Watched, saved or liked by the same users as BasedOn
+	ReasonSimilar   = "similar"    // Shares tags, title words or folder with BasedOn
+	ReasonPopular   = "popular"    // Trending, for users without enough history
+)
+
+// Weights of the seeds a user's recommendations are drawn from.
+const (
+	recommendMaxSeeds      = 50  // Most recent history entries used as seeds
+	recommendSavedWeight   = 1.5 // Added for a saved seed
+	recommendLikedWeight   = 2.0 // Added for a liked seed
+	upNextPersonalWeight   = 0.25
+	recommendSimilarWeight = 0.1 // Score of content-similar fillers, below any co-watched video
+)
+
+// Recommendation is a recommended video, why it was picked and how strongly.
+type Recommendation struct {
+	datatypes.VideoData
+	Score   float64 `json:"score"`
+	Reason  string  `json:"reason"`
+	BasedOn string  `json:"basedOn,omitempty"` // Video the recommendation comes from
+}
+
+// recordInteraction feeds a watch, save or like into the recommendation model. It is best
+// effort; a missed interaction is picked up by the next rebuild.
+func (r *RepoManager) recordInteraction(accountId, videoId string) {
+	_ = r.diskDataStorage.AddRecommendationInteraction(accountId, videoId)
+}
+
+// RebuildRecommendations recomputes the recommendation model from every user's history,
+// watched list, saved videos and likes. Afterwards the model is kept current incrementally.
+func (r *RepoManager) RebuildRecommendations() (*datatypes.RecommendationModel, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	r.recommendMu.Lock()
+	defer r.recommendMu.Unlock()
+	return r.rebuildRecommendations()
+}
+
+// rebuildRecommendations requires recommendMu.
+func (r *RepoManager) rebuildRecommendations() (*datatypes.RecommendationModel, error) {
+	users, err := r.diskDataStorage.GetAllUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	likes, err := r.diskDataStorage.GetAllVideoLikes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get likes: %w", err)
+	}
+
+	type interaction struct {
+		videoId string
+		at      time.Time
+	}
+	byAccount := map[string][]interaction{}
+	for videoId, likedBy := range likes {
+		for accountId, likedAt := range likedBy {
+			byAccount[accountId] = append(byAccount[accountId], interaction{videoId, likedAt})
+		}
+	}
+
+	model := datatypes.NewRecommendationModel()
+	for _, user := range users {
+		// Lists without times go first, so the timed history decides what is most recent
+		interactions := []interaction{}
+		watched, _ := r.diskDataStorage.GetUserWatchedVideos(user.AccountID)
+		saved, _ := r.diskDataStorage.GetSavedVideosByAccountId(user.AccountID)
+		for _, videoId := range append(watched, saved...) {
+			interactions = append(interactions, interaction{videoId: videoId})
+		}
+		events, err := r.diskDataStorage.GetWatchEvents(user.AccountID, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get watch history of %s: %w", user.AccountID, err)
+		}
+		for _, event := range events {
+			interactions = append(interactions, interaction{event.VideoID, event.StartedAt})
+		}
+		interactions = append(interactions, byAccount[user.AccountID]...)
+
+		sort.SliceStable(interactions, func(i, j int) bool {
+			return interactions[i].at.Before(interactions[j].at)
+		})
+		for _, item := range interactions {
+			if _, err := r.diskDataStorage.GetVideoByID(item.videoId); err != nil {
+				continue // Removed since
+			}
+			model.AddInteraction(user.AccountID, item.videoId)
+		}
+	}
+
+	model.BuiltAt = time.Now().UTC()
+	model.UpdatedAt = model.BuiltAt
+	if err := r.diskDataStorage.SaveRecommendationModel(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// ensureRecommendationModel returns the stored model, building it first when it never was.
+func (r *RepoManager) ensureRecommendationModel() (*datatypes.RecommendationModel, error) {
+	r.recommendMu.Lock()
+	defer r.recommendMu.Unlock()
+
+	model, err := r.diskDataStorage.GetRecommendationModel()
+	if err != nil {
+		return nil, err
+	}
+	if model.BuiltAt.IsZero() {
+		return r.rebuildRecommendations()
+	}
+	return model, nil
+}
+
+// GetRecommendations returns up to limit videos for the account's home feed. Videos watched,
+// saved or liked by the same users as the account's own recent videos come first; videos
+// the account has already watched are left out. Users without enough history get videos
+// similar to their latest one, then trending videos.
+func (r *RepoManager) GetRecommendations(accountId string, limit int) ([]Recommendation, error) {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return nil, err
+	}
+	model, err := r.ensureRecommendationModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recommendation model: %w", err)
+	}
+
+	seeds, latest, err := r.recommendationSeeds(accountId)
+	if err != nil {
+		return nil, err
+	}
+	watched, err := r.watchedVideoSet(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	picks := newRecommendationPicks(access, watched, limit)
+	picks.addScored(r, scoreFromSeeds(model, seeds, 1))
+	if latest != "" {
+		picks.addSimilar(r, latest)
+	}
+	picks.addPopular(r, accountId)
+	return picks.list, nil
+}
+
+// GetUpNext returns up to limit videos to play after videoId: videos watched by the same
+// users first, leaning towards the account's own taste, then similar and trending videos.
+// The current video and videos the account has already watched are left out.
+func (r *RepoManager) GetUpNext(accountId, videoId string, limit int) ([]Recommendation, error) {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return nil, err
+	}
+	model, err := r.ensureRecommendationModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recommendation model: %w", err)
+	}
+
+	seeds, _, err := r.recommendationSeeds(accountId)
+	if err != nil {
+		return nil, err
+	}
+	watched, err := r.watchedVideoSet(accountId)
+	if err != nil {
+		return nil, err
+	}
+	watched[videoId] = true
+
+	scored := scoreFromSeeds(model, map[string]float64{videoId: 1}, 1)
+	for candidate, personal := range scoreFromSeeds(model, seeds, upNextPersonalWeight) {
+		// Only lean towards the user's taste among videos that belong after this one
+		if current, ok := scored[candidate]; ok {
+			current.score += personal.score
+			scored[candidate] = current
+		}
+	}
+
+	picks := newRecommendationPicks(access, watched, limit)
+	picks.addScored(r, scored)
+	picks.addSimilar(r, videoId)
+	picks.addPopular(r, accountId)
+	return picks.list, nil
+}
+
+// recommendationSeeds weighs the account's own videos: recent history counts more than old
+// history, and saving or liking a video adds to its weight. It also returns the most
+// recently watched video.
+func (r *RepoManager) recommendationSeeds(accountId string) (map[string]float64, string, error) {
+	recent, err := r.getRecentlyWatchedIDs(accountId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	seeds := map[string]float64{}
+	latest := ""
+	for i, videoId := range recent {
+		if i == recommendMaxSeeds {
+			break
+		}
+		if latest == "" {
+			latest = videoId
+		}
+		seeds[videoId] = 1 / float64(1+i)
+	}
+
+	saved, _ := r.diskDataStorage.GetSavedVideosByAccountId(accountId)
+	for _, videoId := range saved {
+		seeds[videoId] += recommendSavedWeight
+		if latest == "" {
+			latest = videoId
+		}
+	}
+	_, liked, err := r.GetVideoLikes(accountId)
+	if err != nil {
+		return nil, "", err
+	}
+	for videoId := range liked {
+		seeds[videoId] += recommendLikedWeight
+		if latest == "" {
+			latest = videoId
+		}
+	}
+	return seeds, latest, nil
+}
+
+// watchedVideoSet returns the videos the account has watched, in their history, watched list
+// or finished playback.
+func (r *RepoManager) watchedVideoSet(accountId string) (map[string]bool, error) {
+	recent, err := r.getRecentlyWatchedIDs(accountId)
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[string]bool, len(recent))
+	for _, videoId := range recent {
+		watched[videoId] = true
+	}
+
+	states, err := r.diskDataStorage.GetPlaybackStates(accountId)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		if state.Completed {
+			watched[state.VideoID] = true
+		}
+	}
+	return watched, nil
+}
+
+// scoredCandidate is a candidate video and the seed that contributed most to it.
+type scoredCandidate struct {
+	score   float64
+	basedOn string
+}
+
+// scoreFromSeeds sums the similarity of every neighbour of the seeds, weighted by the seeds.
+func scoreFromSeeds(model *datatypes.RecommendationModel, seeds map[string]float64, weight float64) map[string]scoredCandidate {
+	scored := map[string]scoredCandidate{}
+	best := map[string]float64{}
+	for seed, seedWeight := range seeds {
+		for candidate, similarity := range model.Neighbors(seed) {
+			contribution := weight * seedWeight * similarity
+			current := scored[candidate]
+			current.score += contribution
+			if contribution > best[candidate] {
+				best[candidate] = contribution
+				current.basedOn = seed
+			}
+			scored[candidate] = current
+		}
+	}
+	return scored
+}
+
+// recommendationPicks collects recommendations until the limit, skipping hidden, watched
+// and already picked videos.
+type recommendationPicks struct {
+	access *videoAccess
+	skip   map[string]bool
+	limit  int
+	list   []Recommendation
+}
+
+func newRecommendationPicks(access *videoAccess, watched map[string]bool, limit int) *recommendationPicks {
+	skip := make(map[string]bool, len(watched))
+	for videoId := range watched {
+		skip[videoId] = true
+	}
+	return &recommendationPicks{access: access, skip: skip, limit: limit, list: []Recommendation{}}
+}
+
+func (p *recommendationPicks) full() bool {
+	return len(p.list) >= p.limit
+}
+
+func (p *recommendationPicks) add(video datatypes.VideoData, score float64, reason, basedOn string) {
+	if p.full() || p.skip[video.VideoID] || !p.access.canView(&video) {
+		return
+	}
+	p.skip[video.VideoID] = true
+	p.list = append(p.list, Recommendation{VideoData: video, Score: score, Reason: reason, BasedOn: basedOn})
+}
+
+func (p *recommendationPicks) addScored(r *RepoManager, scored map[string]scoredCandidate) {
+	ids := make([]string, 0, len(scored))
+	for videoId := range scored {
+		ids = append(ids, videoId)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scored[ids[i]].score != scored[ids[j]].score {
+			return scored[ids[i]].score > scored[ids[j]].score
+		}
+		return ids[i] < ids[j]
+	})
+
+	for _, videoId := range ids {
+		if p.full() {
+			return
+		}
+		video, err := r.diskDataStorage.GetVideoByID(videoId)
+		if err != nil {
+			continue
+		}
+		candidate := scored[videoId]
+		p.add(*video, candidate.score, ReasonCoWatched, candidate.basedOn)
+	}
+}
+
+func (p *recommendationPicks) addSimilar(r *RepoManager, videoId string) {
+	if p.full() {
+		return
+	}
+	similar, err := r.diskDataStorage.SimilarSearch(videoId)
+	if err != nil {
+		return
+	}
+	for _, video := range similar {
+		p.add(video, recommendSimilarWeight, ReasonSimilar, videoId)
+	}
+}
+
+func (p *recommendationPicks) addPopular(r *RepoManager, accountId string) {
+	if p.full() {
+		return
+	}
+	trending, _, err := r.GetTrendingVideos(accountId, DefaultTrendingDays, 1, p.limit+len(p.skip))
+	if err != nil {
+		return
+	}
+	for _, ranked := range trending {
+		p.add(ranked.VideoData, 0, ReasonPopular, "")
+	}
+}
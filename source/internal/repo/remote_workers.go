@@ -0,0 +1,651 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+const (
+	remoteWorkerTimeout = taskLease  // A worker silent this long is forgotten
+	remoteWorkerMaxCPU  = 90         // Workers this busy, in percent, get no new jobs
+	maxArtifactSize     = 1024 << 20 // Largest file a worker may upload for one job
+)
+
+// RemoteTaskLease is how long a remote worker holds a task without renewing its lease.
+const RemoteTaskLease = taskLease
+
+// RemoteTaskTypes are the task types remote workers may run.
+var RemoteTaskTypes = []datatypes.TaskType{datatypes.TaskCooking}
+
+// remoteArtifacts are the artifacts remote workers make; the server makes the others.
+var remoteArtifacts = []string{datatypes.ArtifactStoryboard}
+
+// ErrTaskNotLeased is returned when a worker reports on a task it no longer holds, because
+// its lease expired or the task was cancelled and finished.
+var ErrTaskNotLeased = errors.New("task is not leased to this worker")
+
+// ErrArtifactMissing is returned when a worker completes a task without uploading a file it
+// says it made.
+var ErrArtifactMissing = errors.New("artifact was not uploaded")
+
+// RemoteWorker is a worker process that runs tasks over gRPC.
+type RemoteWorker struct {
+	ID           string               `json:"id"`
+	Hostname     string               `json:"hostname"`
+	Capabilities []datatypes.TaskType `json:"capabilities"`
+	MaxJobs      int                  `json:"maxJobs"`
+	ActiveJobs   int                  `json:"activeJobs"` // As reported by the worker
+	CPUUsage     float64              `json:"cpuUsage"`   // Percent
+	RAMUsage     float64              `json:"ramUsage"`   // Percent
+	TaskIDs      []string             `json:"taskIds"`    // Tasks leased to the worker
+	FirstSeen    time.Time            `json:"firstSeen"`
+	LastSeen     time.Time            `json:"lastSeen"`
+}
+
+// canRun reports whether the worker runs the task.
+func (w *RemoteWorker) canRun(task datatypes.TaskData) bool {
+	return slices.Contains(w.Capabilities, task.Type) && runsRemotely(task)
+}
+
+// runsRemotely reports whether remote workers may run the task. A cooking task limited to
+// artifacts only the server makes is left to the local runner.
+func runsRemotely(task datatypes.TaskData) bool {
+	if !slices.Contains(RemoteTaskTypes, task.Type) {
+		return false
+	}
+	if task.Type == datatypes.TaskCooking && len(task.Only) > 0 {
+		return slices.ContainsFunc(task.Only, func(artifact string) bool {
+			return slices.Contains(remoteArtifacts, artifact)
+		})
+	}
+	return true
+}
+
+// remoteWorkerName is what TaskData.Worker holds for tasks leased to a remote worker.
+func remoteWorkerName(workerId string) string {
+	return "remote:" + workerId
+}
+
+// GetRemoteWorkers returns the remote workers heard from recently, with the tasks leased to
+// them, sorted by ID.
+func (r *RepoManager) GetRemoteWorkers() ([]RemoteWorker, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	tasks, err := r.diskDataStorage.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	workers := []RemoteWorker{}
+	for _, worker := range r.liveRemoteWorkersLocked(now) {
+		worker.TaskIDs = []string{}
+		for _, task := range tasks {
+			if task.Status == datatypes.TaskProcessing && task.Worker == remoteWorkerName(worker.ID) {
+				worker.TaskIDs = append(worker.TaskIDs, task.ID)
+			}
+		}
+		workers = append(workers, worker)
+	}
+	sort.Slice(workers, func(i, j int) bool {
+		return workers[i].ID < workers[j].ID
+	})
+	return workers, nil
+}
+
+// LeaseRemoteTask hands the most urgent due task the worker can run to it, or returns nil
+// when there is none or the worker is busy. The worker must renew the lease with
+// RemoteWorkerHeartbeat or ReportRemoteProgress, or the task is queued again. ctx is the
+// worker's request; the task is queued again without counting the attempt when it ends
+// before the task is handed over.
+func (r *RepoManager) LeaseRemoteTask(ctx context.Context, info RemoteWorker) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	task, err := r.leaseRemoteTask(info)
+	if err != nil || task == nil {
+		return nil, err
+	}
+
+	done, prepareErr := r.prepareRemoteTask(task)
+	if prepareErr == nil && !done && ctx.Err() == nil {
+		return task, nil
+	}
+
+	// Finished, or the worker left, before it got the task; the worker asks again
+	finished, err := r.modifyTask(task.ID, func(current *datatypes.TaskData) error {
+		if current.Status != datatypes.TaskProcessing || current.Worker != task.Worker {
+			return fmt.Errorf("%w: %s, given up by another process", ErrTaskNotLeased, task.ID)
+		}
+		if prepareErr == nil && !done && !current.CancelRequested {
+			current.Status = datatypes.TaskPending
+			current.Attempts--
+			current.Worker = ""
+			current.LeaseUntil = time.Time{}
+			current.AddLog("Worker %s left before the task was handed over, will resume", info.ID)
+			return nil
+		}
+		r.finishTaskAttempt(current, prepareErr, false)
+		return nil
+	})
+	if errors.Is(err, ErrTaskNotLeased) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if finished.Status == datatypes.TaskFailed {
+		taskLogger.Warn("Task %s (%s) failed: %s", finished.ID, finished.Type, finished.Error)
+	}
+	return nil, nil
+}
+
+func (r *RepoManager) leaseRemoteTask(info RemoteWorker) (*datatypes.TaskData, error) {
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	now := time.Now().UTC()
+	worker, err := r.touchRemoteWorkerLocked(info, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := r.diskDataStorage.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	// The serve process gives up expired leases on its own; a gRPC-only process has no runner
+	leased := map[string]int{}
+	due := []datatypes.TaskData{}
+	for _, task := range tasks {
+		switch {
+		case task.Status == datatypes.TaskProcessing && now.After(task.LeaseUntil) && !r.runsLocally(task.ID):
+			if err := r.releaseExpiredLeaseLocked(task); err != nil {
+				return nil, err
+			}
+
+		case task.Status == datatypes.TaskProcessing:
+			leased[task.Worker]++
+
+		case task.Status == datatypes.TaskPending && !now.Before(task.NotBefore) && worker.canRun(task):
+			due = append(due, task)
+		}
+	}
+
+	own := leased[remoteWorkerName(worker.ID)]
+	if len(due) == 0 || own >= worker.MaxJobs || worker.CPUUsage >= remoteWorkerMaxCPU {
+		return nil, nil
+	}
+
+	// Leave the work to less loaded workers while there are not enough tasks for everyone
+	lessLoaded := 0
+	for _, other := range r.liveRemoteWorkersLocked(now) {
+		if other.ID == worker.ID || other.CPUUsage >= remoteWorkerMaxCPU {
+			continue
+		}
+		load := leased[remoteWorkerName(other.ID)]
+		if load < other.MaxJobs && load*worker.MaxJobs < own*other.MaxJobs && other.canRun(due[0]) {
+			lessLoaded++
+		}
+	}
+	if lessLoaded >= len(due) {
+		return nil, nil
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Priority > due[j].Priority
+	})
+	task := due[0]
+	previous := task
+	task.Status = datatypes.TaskProcessing
+	task.Attempts++
+	task.Worker = remoteWorkerName(worker.ID)
+	task.StartedAt = now
+	task.LeaseUntil = now.Add(taskLease)
+	task.AddLog("Attempt %d of %d started on remote worker %s (%s)", task.Attempts, task.MaxAttempts, worker.ID, worker.Hostname)
+	if err := r.storeTaskLocked(&previous, task); errors.Is(err, datatypes.ErrTaskChanged) {
+		return nil, nil // Claimed by another process first, the worker asks again
+	} else if err != nil {
+		return nil, err
+	}
+	// Drop what an earlier attempt uploaded
+	if err := os.RemoveAll(r.getTaskArtifactsDir(task.ID)); err != nil {
+		taskLogger.Warn("Failed to clear uploads of task %s: %v", task.ID, err)
+	}
+	return &task, nil
+}
+
+// prepareRemoteTask checks what the local handler would check before doing the work and
+// records the videos the task works on. Workers only make storyboards, so the thumbnail
+// and preview the task needs are queued as a cooking task of their own for the local
+// runner. It reports done when nothing is left for the worker.
+func (r *RepoManager) prepareRemoteTask(task *datatypes.TaskData) (bool, error) {
+	if _, err := os.Stat(task.VideoPath); err != nil {
+		return false, permanentTaskFailure(fmt.Errorf("video file is not readable: %w", err))
+	}
+	videoId, err := r.GenerateVideoID(task.VideoPath)
+	if err != nil {
+		return false, err
+	}
+	task.VideoIDs = []string{videoId}
+	_, err = r.modifyTask(task.ID, func(current *datatypes.TaskData) error {
+		current.VideoIDs = task.VideoIDs
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if !r.CheckVideoIndexedByID(videoId) {
+		return false, permanentTaskFailure(fmt.Errorf("video %s is not indexed", videoId))
+	}
+	profileName, _, artifacts, err := r.planCooking(videoId, cookingOptionsOf(task))
+	if err != nil {
+		return false, permanentTaskFailure(err)
+	}
+
+	remote := false
+	var local []string
+	for _, artifact := range artifacts {
+		if slices.Contains(remoteArtifacts, artifact) {
+			remote = true
+		} else {
+			local = append(local, artifact)
+		}
+	}
+
+	var logs []string
+	if len(local) > 0 {
+		cooking, err := newCookingTask(task.VideoPath, task.Priority, CookingOptions{Profile: task.Profile, Only: local, Force: task.Force})
+		if err != nil {
+			return false, err
+		}
+		cooking.AccountID = task.AccountID
+		cooking.ParentID = task.ID
+		if cooking, err = r.EnqueueTask(cooking); err != nil {
+			return false, fmt.Errorf("failed to queue %s: %w", strings.Join(local, ", "), err)
+		}
+		logs = append(logs, fmt.Sprintf("Queued %s of video %s as task %s on the server", strings.Join(local, ", "), videoId, cooking.ID))
+	}
+	if len(artifacts) == 0 {
+		logs = append(logs, fmt.Sprintf("Video %s is up to date with cooking profile %q", videoId, profileName))
+	}
+	if len(logs) > 0 {
+		_, err = r.modifyTask(task.ID, func(current *datatypes.TaskData) error {
+			for _, line := range logs {
+				current.AddLog("%s", line)
+			}
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return !remote, nil
+}
+
+// RemoteWorkerHeartbeat records that the worker is alive and renews the leases of the
+// given tasks. It returns the tasks the worker must stop: cancelled ones and ones that are
+// no longer leased to it.
+func (r *RepoManager) RemoteWorkerHeartbeat(info RemoteWorker, taskIds []string) ([]string, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	now := time.Now().UTC()
+	worker, err := r.touchRemoteWorkerLocked(info, now)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := []string{}
+	for _, id := range taskIds {
+		task, err := r.diskDataStorage.GetTaskByID(id)
+		if err != nil || task.Status != datatypes.TaskProcessing || task.Worker != remoteWorkerName(worker.ID) || task.CancelRequested {
+			stop = append(stop, id)
+			continue
+		}
+		previous := *task
+		task.LeaseUntil = now.Add(taskLease)
+		// A task changed by another process keeps its lease until the next heartbeat
+		if err := r.diskDataStorage.UpdateTask(previous, *task); err != nil && !errors.Is(err, datatypes.ErrTaskChanged) {
+			return nil, err
+		}
+	}
+	return stop, nil
+}
+
+// ReportRemoteProgress records the progress of a leased task and an optional log line, and
+// renews its lease. It reports whether the task was cancelled and should be stopped.
+func (r *RepoManager) ReportRemoteProgress(workerId, taskId string, progress int, message string) (bool, error) {
+	if !r.IsDataStorageInitialized() {
+		return false, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	task, err := r.modifyLeasedTaskLocked(workerId, taskId, func(task *datatypes.TaskData) error {
+		task.Progress = max(0, min(progress, 100))
+		if message != "" {
+			task.AddLog("%s", message)
+		}
+		task.LeaseUntil = time.Now().UTC().Add(taskLease)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return task.CancelRequested, nil
+}
+
+// OpenRemoteTaskVideo opens the video file of a leased task for a worker that cannot read
+// it where the server does.
+func (r *RepoManager) OpenRemoteTaskVideo(workerId, taskId string) (*os.File, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.tasksMu.Lock()
+	task, err := r.diskDataStorage.GetTaskByID(taskId)
+	r.tasksMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != datatypes.TaskProcessing || task.Worker != remoteWorkerName(workerId) {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotLeased, taskId)
+	}
+	return os.Open(task.VideoPath)
+}
+
+// StoreRemoteArtifact appends a chunk to a file a worker makes for a leased task. Chunks of
+// a file must arrive in order; a chunk at offset 0 starts the file over.
+func (r *RepoManager) StoreRemoteArtifact(workerId, taskId, name string, offset int64, data []byte) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid artifact name %q", name)
+	}
+	if offset < 0 || offset+int64(len(data)) > maxArtifactSize {
+		return fmt.Errorf("artifact %q is larger than %d bytes", name, maxArtifactSize)
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	task, err := r.diskDataStorage.GetTaskByID(taskId)
+	if err != nil {
+		return err
+	}
+	if task.Status != datatypes.TaskProcessing || task.Worker != remoteWorkerName(workerId) {
+		return fmt.Errorf("%w: %s", ErrTaskNotLeased, taskId)
+	}
+
+	dir := r.getTaskArtifactsDir(taskId)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(filepath.Join(dir, name), flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if size != offset {
+		return fmt.Errorf("artifact %q has %d bytes, chunk starts at %d", name, size, offset)
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+// CompleteRemoteTask installs what the worker uploaded for a leased task and marks the task
+// completed. Outputs are recorded in the task's log. When one of the artifacts the worker
+// lists is missing, the task is left as it is.
+func (r *RepoManager) CompleteRemoteTask(workerId, taskId string, artifacts []string, outputs map[string]string) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	task, err := r.diskDataStorage.GetTaskByID(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != datatypes.TaskProcessing || task.Worker != remoteWorkerName(workerId) {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotLeased, taskId)
+	}
+	for _, name := range artifacts {
+		if name != filepath.Base(name) {
+			return nil, fmt.Errorf("invalid artifact name %q", name)
+		}
+		if _, err := os.Stat(filepath.Join(r.getTaskArtifactsDir(taskId), name)); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrArtifactMissing, name)
+		}
+	}
+
+	// Moving the files is not repeatable, so it happens once and not in the change below,
+	// which is retried when another process changes the task meanwhile
+	var installErr error
+	if !task.CancelRequested {
+		installErr = r.installRemoteArtifacts(task)
+	}
+
+	task, err = r.modifyLeasedTaskLocked(workerId, taskId, func(task *datatypes.TaskData) error {
+		keys := make([]string, 0, len(outputs))
+		for key := range outputs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			task.AddLog("Output %s: %s", key, outputs[key])
+		}
+		r.finishTaskAttempt(task, installErr, false)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.removeTaskArtifacts(taskId)
+	if task.Status == datatypes.TaskFailed {
+		taskLogger.Warn("Task %s (%s) failed: %s", task.ID, task.Type, task.Error)
+	}
+	return task, nil
+}
+
+// FailRemoteTask records that a leased task failed on its worker. Unless retryable, the
+// task fails without further attempts. A task interrupted because the worker shuts down is
+// queued again without counting the attempt.
+func (r *RepoManager) FailRemoteTask(workerId, taskId, message string, retryable, interrupted bool) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	if message == "" {
+		message = "worker reported a failure"
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	task, err := r.modifyLeasedTaskLocked(workerId, taskId, func(task *datatypes.TaskData) error {
+		err := fmt.Errorf("%s", message)
+		if !retryable {
+			err = permanentTaskFailure(err)
+		}
+		r.finishTaskAttempt(task, err, interrupted)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.removeTaskArtifacts(taskId)
+	if task.Status == datatypes.TaskFailed {
+		taskLogger.Warn("Task %s (%s) failed: %s", task.ID, task.Type, task.Error)
+	}
+	return task, nil
+}
+
+// installRemoteArtifacts moves the uploaded files of a task to where the local handler of
+// its type would have written them.
+func (r *RepoManager) installRemoteArtifacts(task *datatypes.TaskData) error {
+	dir := r.getTaskArtifactsDir(task.ID)
+	switch task.Type {
+	case datatypes.TaskCooking:
+		if len(task.VideoIDs) == 0 {
+			return permanentTaskFailure(fmt.Errorf("task has no video"))
+		}
+		if _, err := os.Stat(filepath.Join(dir, "thumbnails.vtt")); err != nil {
+			return fmt.Errorf("worker did not upload thumbnails.vtt")
+		}
+		profileName, profile, err := r.GetCookingProfile(task.Profile)
+		if err != nil {
+			return permanentTaskFailure(err)
+		}
+		// Sprite sheets of an older storyboard would outlive it otherwise
+		storyboardDir := r.GetPreviewThumbnailsFolderPathByVideoID(task.VideoIDs[0])
+		if err := os.RemoveAll(storyboardDir); err != nil {
+			return err
+		}
+		if err := moveFolderContents(dir, storyboardDir); err != nil {
+			return err
+		}
+		return r.recordArtifact(task.VideoIDs[0], datatypes.ArtifactStoryboard, profileName, profile)
+	default:
+		return permanentTaskFailure(fmt.Errorf("task type %q cannot run remotely", task.Type))
+	}
+}
+
+func (r *RepoManager) removeTaskArtifacts(taskId string) {
+	if err := os.RemoveAll(r.getTaskArtifactsDir(taskId)); err != nil {
+		taskLogger.Warn("Failed to remove uploads of task %s: %v", taskId, err)
+	}
+}
+
+// moveFolderContents moves the files of src into dst, creating dst.
+func moveFolderContents(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	// The VTT file marks a video as cooked, so it goes last
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[j].Name() == "thumbnails.vtt"
+	})
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := os.Rename(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modifyLeasedTaskLocked applies a change to a task leased to the remote worker. It requires
+// tasksMu.
+func (r *RepoManager) modifyLeasedTaskLocked(workerId, taskId string, change func(task *datatypes.TaskData) error) (*datatypes.TaskData, error) {
+	return r.modifyTaskLocked(taskId, func(task *datatypes.TaskData) error {
+		if task.Status != datatypes.TaskProcessing || task.Worker != remoteWorkerName(workerId) {
+			return fmt.Errorf("%w: %s", ErrTaskNotLeased, taskId)
+		}
+		return change(task)
+	})
+}
+
+// touchRemoteWorkerLocked records what a worker reported about itself. It requires tasksMu.
+func (r *RepoManager) touchRemoteWorkerLocked(info RemoteWorker, now time.Time) (*RemoteWorker, error) {
+	if info.ID == "" {
+		return nil, fmt.Errorf("worker ID is required")
+	}
+	if r.remoteWorkers == nil {
+		r.remoteWorkers = map[string]*RemoteWorker{}
+	}
+
+	worker := r.remoteWorkers[info.ID]
+	if worker == nil || now.Sub(worker.LastSeen) > remoteWorkerTimeout {
+		worker = &RemoteWorker{ID: info.ID, FirstSeen: now}
+		r.remoteWorkers[info.ID] = worker
+		taskLogger.Info("Remote worker %s (%s) connected", info.ID, info.Hostname)
+	}
+	if info.Hostname != "" {
+		worker.Hostname = info.Hostname
+	}
+	// Heartbeats may leave out what the worker sent when it asked for a job
+	if len(info.Capabilities) > 0 {
+		worker.Capabilities = info.Capabilities
+	}
+	if info.MaxJobs > 0 {
+		worker.MaxJobs = info.MaxJobs
+	} else if worker.MaxJobs == 0 {
+		worker.MaxJobs = 1
+	}
+	worker.ActiveJobs = info.ActiveJobs
+	worker.CPUUsage = info.CPUUsage
+	worker.RAMUsage = info.RAMUsage
+	worker.LastSeen = now
+	return worker, nil
+}
+
+// liveRemoteWorkersLocked returns copies of the workers heard from recently and forgets
+// the others. It requires tasksMu.
+func (r *RepoManager) liveRemoteWorkersLocked(now time.Time) []RemoteWorker {
+	live := []RemoteWorker{}
+	for id, worker := range r.remoteWorkers {
+		if now.Sub(worker.LastSeen) > remoteWorkerTimeout {
+			delete(r.remoteWorkers, id)
+			continue
+		}
+		live = append(live, *worker)
+	}
+	return live
+}
+
+// leftToRemoteWorkersLocked reports whether the task is left to remote workers because one
+// that can run it is connected. It requires tasksMu.
+func (r *RepoManager) leftToRemoteWorkersLocked(task datatypes.TaskData, now time.Time) bool {
+	for _, worker := range r.liveRemoteWorkersLocked(now) {
+		if worker.canRun(task) {
+			return true
+		}
+	}
+	return false
+}
+
+// runsLocally reports whether the task runner of this process runs the task. It requires
+// tasksMu.
+func (r *RepoManager) runsLocally(taskId string) bool {
+	return r.taskRunner != nil && r.taskRunner.running[taskId] != nil
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// newCookingFixture is a repository with one indexed video and a cooking task for all of
+// its artifacts queued.
+func newCookingFixture(t *testing.T) (*RepoManager, *datatypes.TaskData) {
+	t.Helper()
+	r, err := NewRepoManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("open repository: %v", err)
+	}
+
+	videoPath := filepath.Join(r.GetRootPath(), "clip.mp4")
+	if err := os.WriteFile(videoPath, []byte("video"), 0644); err != nil {
+		t.Fatalf("write video: %v", err)
+	}
+	videoId, err := r.GenerateVideoID(videoPath)
+	if err != nil {
+		t.Fatalf("video id: %v", err)
+	}
+	if err := r.AddVideo(datatypes.NewVideoData("clip", videoId)); err != nil {
+		t.Fatalf("add video: %v", err)
+	}
+
+	task, err := datatypes.NewTaskData(datatypes.TaskCooking, datatypes.TaskPriorityNormal)
+	if err != nil {
+		t.Fatalf("new task: %v", err)
+	}
+	task.VideoPath = videoPath
+	if _, err := r.EnqueueTask(task); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	return r, task
+}
+
+// TestLeasedTaskResumesWhenTheWorkerLeaves leases a cooking task to a worker that already
+// left. The task is queued again without using up an attempt.
+func TestLeasedTaskResumesWhenTheWorkerLeaves(t *testing.T) {
+	r, task := newCookingFixture(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker := RemoteWorker{ID: "w1", Hostname: "gone", Capabilities: RemoteTaskTypes, MaxJobs: 1}
+	leased, err := r.LeaseRemoteTask(ctx, worker)
+	if err != nil || leased != nil {
+		t.Fatalf("lease: got %v, %v, want no task", leased, err)
+	}
+
+	stored, err := r.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if stored.Status != datatypes.TaskPending || stored.Attempts != 0 || stored.Worker != "" {
+		t.Errorf("task is %s with %d attempts on %q, want pending with none", stored.Status, stored.Attempts, stored.Worker)
+	}
+}
+
+// TestLeasedTaskLeavesThumbnailAndPreviewToTheServer leases a cooking task that needs all
+// artifacts. The worker gets it for the storyboard, the rest is queued for the local runner,
+// which does not leave it to the connected worker.
+func TestLeasedTaskLeavesThumbnailAndPreviewToTheServer(t *testing.T) {
+	r, task := newCookingFixture(t)
+
+	worker := RemoteWorker{ID: "w1", Hostname: "cook", Capabilities: RemoteTaskTypes, MaxJobs: 1}
+	leased, err := r.LeaseRemoteTask(context.Background(), worker)
+	if err != nil || leased == nil || leased.ID != task.ID {
+		t.Fatalf("lease: got %v, %v, want task %s", leased, err, task.ID)
+	}
+
+	tasks, err := r.GetTasks()
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	var local *datatypes.TaskData
+	for i := range tasks {
+		if tasks[i].ParentID == task.ID {
+			local = &tasks[i]
+		}
+	}
+	if local == nil {
+		t.Fatalf("no task was queued for the thumbnail and preview")
+	}
+	if want := []string{datatypes.ArtifactThumbnail, datatypes.ArtifactPreview}; !slices.Equal(local.Only, want) || local.Status != datatypes.TaskPending {
+		t.Errorf("queued task is %s for %v, want pending for %v", local.Status, local.Only, want)
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+	now := time.Now().UTC()
+	if r.leftToRemoteWorkersLocked(*local, now) {
+		t.Errorf("the thumbnail and preview are left to the remote worker")
+	}
+	if !r.leftToRemoteWorkersLocked(*task, now) {
+		t.Errorf("a full cooking task is not left to the remote worker")
+	}
+}
+
+// TestCompleteRemoteTaskInstallsTheStoryboard completes a leased cooking task with an
+// uploaded storyboard, which replaces the video's preview thumbnails.
+func TestCompleteRemoteTaskInstallsTheStoryboard(t *testing.T) {
+	r, task := newCookingFixture(t)
+
+	worker := RemoteWorker{ID: "w1", Hostname: "cook", Capabilities: RemoteTaskTypes, MaxJobs: 1}
+	leased, err := r.LeaseRemoteTask(context.Background(), worker)
+	if err != nil || leased == nil {
+		t.Fatalf("lease: got %v, %v", leased, err)
+	}
+	if _, err := r.CompleteRemoteTask("w1", task.ID, []string{"thumbnails.vtt"}, nil); !errors.Is(err, ErrArtifactMissing) {
+		t.Fatalf("complete before the upload: got %v, want ErrArtifactMissing", err)
+	}
+	if err := r.StoreRemoteArtifact("w1", task.ID, "thumbnails.vtt", 0, []byte("WEBVTT\n")); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	done, err := r.CompleteRemoteTask("w1", task.ID, []string{"thumbnails.vtt"}, map[string]string{"sprites": "1"})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if done.Status != datatypes.TaskCompleted {
+		t.Errorf("task is %s, want completed: %s", done.Status, done.Error)
+	}
+	vtt := filepath.Join(r.GetPreviewThumbnailsFolderPathByVideoID(leased.VideoIDs[0]), "thumbnails.vtt")
+	if _, err := os.Stat(vtt); err != nil {
+		t.Errorf("storyboard was not installed: %v", err)
+	}
+
+	if _, err := r.CompleteRemoteTask("w1", task.ID, []string{"thumbnails.vtt"}, nil); !errors.Is(err, ErrTaskNotLeased) {
+		t.Errorf("complete again: got %v, want ErrTaskNotLeased", err)
+	}
+}
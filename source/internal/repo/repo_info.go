@@ -48,7 +48,7 @@ func (r *RepoManager) GetRepoInfo(accountId string) (RepoInfo, error) {
 	// Format the storage size (in bytes) to a human-readable format
 	storageUsed := formatSize(repoSize) // Convert the repo size to a human-readable string
 
-	tags, _ := r.GetAllTags()
+	tags, _ := r.GetVisibleTags(accountId)
 
 	playlist, _ := r.GetPlaylistsByUser(accountId)
 
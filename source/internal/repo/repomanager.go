@@ -2,8 +2,11 @@ package repo
 
 import (
 	"fmt"
+	"ova-cli/source/internal/authprovider"
 	"ova-cli/source/internal/datastorage"
 	"ova-cli/source/internal/datatypes"
+	"sync"
+	"time"
 )
 
 // RepoManager handles video registration, thumbnails, previews, etc.
@@ -13,6 +16,54 @@ type RepoManager struct {
 	AuthEnabled        bool
 	diskDataStorage    datastorage.DiskDataStorage
 	sessionDataStorage datastorage.SessionDataStorage
+
+	// pending logins: second-factor challenges and OIDC redirects
+	challengesMu        sync.Mutex
+	twoFactorChallenges map[string]*twoFactorChallenge
+	oidcStates          map[string]*oidcState
+
+	// serializes admitting and counting login attempts
+	loginMu sync.Mutex
+
+	// serializes creating users, so the check that a username or a provider identity is
+	// not taken yet still holds when the user is stored
+	usersMu sync.Mutex
+
+	// serializes changes to TOTP enrollments, so a code or recovery code is consumed once
+	twoFactorMu sync.Mutex
+
+	// identity sources built from configs.AuthProviders on first use
+	providersMu   sync.Mutex
+	authProviders []authprovider.Provider
+
+	// audit log retention is applied at most once a day, and failed logins are folded per IP
+	auditMu           sync.Mutex
+	lastAuditPrune    time.Time
+	failedLoginAudits map[string]*failedLoginAudit
+
+	// serializes read-modify-write changes to playlists made by owners and collaborators
+	playlistMu sync.Mutex
+
+	// secret used to sign share links, loaded from storage on first use
+	shareKeyMu sync.Mutex
+	shareKey   []byte
+
+	// per-account random orders behind the random feed and "surprise me" picks
+	randomMu    sync.Mutex
+	randomPools map[string]*randomPool
+
+	// serializes rebuilds of the recommendation model
+	recommendMu sync.Mutex
+
+	// serializes changes to the records of how each video's artifacts were cooked
+	artifactsMu sync.Mutex
+
+	// serializes read-modify-write changes to tasks and guards the runner started by serve
+	// and the remote workers connected over gRPC
+	tasksMu       sync.Mutex
+	taskRunner    *taskRunner
+	taskListeners []func(TaskUpdate)
+	remoteWorkers map[string]*RemoteWorker
 }
 
 // NewRepoManager creates a new instance of RepoManager and initializes data storage.
@@ -1,8 +1,18 @@
 package repo
 
+import "ova-cli/source/internal/datatypes"
+
 // CreateUser creates a new user with a hashed password and an optional role.
 func (r *RepoManager) GetRepoOwnerID() string {
 
 	// return the root username
 	return r.configs.RootUser
 }
+
+// GetUserRole returns the effective role of a user. The repository owner is always an admin.
+func (r *RepoManager) GetUserRole(user *datatypes.UserData) datatypes.UserRole {
+	if user.AccountID == r.configs.RootUser {
+		return datatypes.RoleAdmin
+	}
+	return user.GetRole()
+}
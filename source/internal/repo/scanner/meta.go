@@ -1,14 +1,15 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"ova-cli/source/internal/thirdparty"
 )
 
 // GetVideoMetadata extracts technical details from a specific video file.
-func (r *Scanner) GetVideoMetadata(videoPath string) (VideoMetadata, error) {
+func (r *Scanner) GetVideoMetadata(ctx context.Context, videoPath string) (VideoMetadata, error) {
 	// Calling your thirdparty wrapper
-	details, err := thirdparty.GetVideoDetails(videoPath)
+	details, err := thirdparty.GetVideoDetails(ctx, videoPath)
 	if err != nil {
 		return VideoMetadata{}, fmt.Errorf("failed to get metadata for file %s: %w", videoPath, err)
 	}
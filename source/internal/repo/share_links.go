@@ -0,0 +1,316 @@
+package repo
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"ova-cli/source/internal/datatypes"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// DefaultShareLinkTTL is used when a share link is created without an explicit lifetime.
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+	// MaxShareLinkTTL caps how long a share link may stay valid.
+	MaxShareLinkTTL = 90 * 24 * time.Hour
+	// ShareGrantTTL is how long playback stays unlocked after a share link is opened.
+	ShareGrantTTL = 6 * time.Hour
+
+	shareKeyFileName = "share-links.key"
+)
+
+var (
+	// ErrShareLinkPassword is returned when a share link is opened with a wrong or missing password.
+	ErrShareLinkPassword = errors.New("incorrect share link password")
+	// ErrShareLinkViewLimit is returned when a share link has been opened as often as allowed.
+	ErrShareLinkViewLimit = errors.New("share link has reached its view limit")
+)
+
+// ShareLinkThrottledError is returned while password guesses on a link, or from the
+// client IP, are backed off. The password is not checked.
+type ShareLinkThrottledError struct {
+	Wait time.Duration
+}
+
+func (e *ShareLinkThrottledError) Error() string {
+	return fmt.Sprintf("too many wrong share link passwords, retry in %s", e.Wait.Round(time.Second))
+}
+
+// ShareLinkOptions describes the limits of a new share link.
+type ShareLinkOptions struct {
+	TTL           time.Duration
+	StartSec      float64
+	EndSec        float64
+	AllowDownload bool
+	Password      string
+	MaxViews      int
+}
+
+// CreateShareLink issues a signed link to one video and returns it with its token.
+// Only accounts that can manage the video may share it.
+func (r *RepoManager) CreateShareLink(accountId, videoId string, opts ShareLinkOptions) (*datatypes.ShareLinkData, string, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, "", fmt.Errorf("data storage is not initialized")
+	}
+
+	video, err := r.diskDataStorage.GetVideoByID(videoId)
+	if err != nil {
+		return nil, "", err
+	}
+	if !r.CanManageVideo(accountId, video) {
+		return nil, "", fmt.Errorf("only the uploader or an admin can share this video")
+	}
+
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultShareLinkTTL
+	}
+	if opts.TTL > MaxShareLinkTTL {
+		return nil, "", fmt.Errorf("share link lifetime cannot exceed %d days", int(MaxShareLinkTTL.Hours()/24))
+	}
+	if opts.StartSec < 0 || opts.EndSec < 0 {
+		return nil, "", fmt.Errorf("clip times cannot be negative")
+	}
+	if opts.EndSec > 0 && opts.EndSec <= opts.StartSec {
+		return nil, "", fmt.Errorf("clip end must be after its start")
+	}
+	if opts.MaxViews < 0 {
+		return nil, "", fmt.Errorf("max views cannot be negative")
+	}
+
+	link, err := datatypes.NewShareLinkData(videoId, accountId, opts.TTL)
+	if err != nil {
+		return nil, "", err
+	}
+	link.StartSec = opts.StartSec
+	link.EndSec = opts.EndSec
+	link.AllowDownload = opts.AllowDownload
+	link.MaxViews = opts.MaxViews
+	if opts.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		link.PasswordHash = string(hash)
+	}
+
+	token, err := r.ShareLinkToken(link)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := r.diskDataStorage.InsertShareLink(link); err != nil {
+		return nil, "", fmt.Errorf("failed to store share link: %w", err)
+	}
+	return link, token, nil
+}
+
+// ShareLinkToken returns the signed token for a link: "<id>.<expiry>.<signature>".
+// Tokens are derived from the stored record, so they can be shown again when listing.
+func (r *RepoManager) ShareLinkToken(link *datatypes.ShareLinkData) (string, error) {
+	exp := strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	sig, err := r.signShare("link", link.ID, link.VideoID, exp)
+	if err != nil {
+		return "", err
+	}
+	return link.ID + "." + exp + "." + sig, nil
+}
+
+// ResolveShareLink checks a token's signature and returns its link if it can still be used.
+// It does not count a view; OpenShareLink does.
+func (r *RepoManager) ResolveShareLink(token string) (*datatypes.ShareLinkData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed share link")
+	}
+	id, exp, sig := parts[0], parts[1], parts[2]
+
+	link, err := r.diskDataStorage.GetShareLinkByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share link")
+	}
+	if !r.verifyShare(sig, "link", link.ID, link.VideoID, exp) ||
+		exp != strconv.FormatInt(link.ExpiresAt.Unix(), 10) {
+		return nil, fmt.Errorf("invalid share link")
+	}
+	if link.Revoked {
+		return nil, fmt.Errorf("share link has been revoked")
+	}
+	if link.IsExpired() {
+		return nil, fmt.Errorf("share link has expired")
+	}
+	if _, err := r.diskDataStorage.GetVideoByID(link.VideoID); err != nil {
+		return nil, fmt.Errorf("shared video no longer exists")
+	}
+	return link, nil
+}
+
+// OpenShareLink checks the password, counts a view and returns a grant that unlocks
+// streaming and downloading for a few hours without counting further views.
+// Password guesses are throttled per link and per client IP like logins.
+func (r *RepoManager) OpenShareLink(token, password, ip string) (*datatypes.ShareLinkData, string, error) {
+	link, err := r.ResolveShareLink(token)
+	if err != nil {
+		return nil, "", err
+	}
+	if link.HasPassword() {
+		if err := r.checkShareLinkPassword(link, password, ip); err != nil {
+			return nil, "", err
+		}
+	}
+
+	link, err = r.diskDataStorage.AddShareLinkView(link.ID)
+	if err != nil {
+		return nil, "", ErrShareLinkViewLimit
+	}
+	if err := r.countShareLinkView(link); err != nil {
+		return nil, "", err
+	}
+
+	expiresAt := time.Now().Add(ShareGrantTTL)
+	if link.ExpiresAt.Before(expiresAt) {
+		expiresAt = link.ExpiresAt
+	}
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	sig, err := r.signShare("grant", link.ID, exp)
+	if err != nil {
+		return nil, "", err
+	}
+	return link, exp + "." + sig, nil
+}
+
+// checkShareLinkPassword compares the password once the link and the IP are admitted by the
+// login backoff. A correct password clears the link's streak and refunds the IP's count.
+func (r *RepoManager) checkShareLinkPassword(link *datatypes.ShareLinkData, password, ip string) error {
+	wait, err := r.beginGuardedAttempt([]guardedKey{
+		{shareLinkLoginKey(link.ID), shareLinkPasswordPolicy},
+		{ipLoginKey(ip), ipLoginPolicy},
+	})
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &ShareLinkThrottledError{Wait: wait}
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)); err != nil {
+		return ErrShareLinkPassword
+	}
+
+	r.loginMu.Lock()
+	_ = r.diskDataStorage.DeleteLoginAttempt(shareLinkLoginKey(link.ID))
+	r.loginMu.Unlock()
+	return r.RefundLoginAttempt(ip)
+}
+
+// VerifyShareGrant reports whether a grant from OpenShareLink is valid for the link.
+func (r *RepoManager) VerifyShareGrant(link *datatypes.ShareLinkData, grant string) bool {
+	exp, sig, ok := strings.Cut(grant, ".")
+	if !ok {
+		return false
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return false
+	}
+	return r.verifyShare(sig, "grant", link.ID, exp)
+}
+
+// GetShareLinks lists share links. Admins see every link for a video; other accounts
+// only the links they created. An empty videoId lists links across all videos.
+func (r *RepoManager) GetShareLinks(accountId, videoId string) ([]datatypes.ShareLinkData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	createdBy := accountId
+	if !r.AuthEnabled || r.IsAdmin(accountId) {
+		createdBy = ""
+	}
+	return r.diskDataStorage.GetShareLinks(videoId, createdBy)
+}
+
+// RevokeShareLink disables a link. Its creator, the video's uploader and admins may revoke it.
+func (r *RepoManager) RevokeShareLink(accountId, linkId string) (*datatypes.ShareLinkData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	link, err := r.diskDataStorage.GetShareLinkByID(linkId)
+	if err != nil {
+		return nil, err
+	}
+	if r.AuthEnabled && link.CreatedBy != accountId && !r.IsAdmin(accountId) {
+		video, err := r.diskDataStorage.GetVideoByID(link.VideoID)
+		if err != nil || video.UploaderID != accountId {
+			return nil, fmt.Errorf("share link %q not found", linkId)
+		}
+	}
+
+	if err := r.diskDataStorage.RevokeShareLink(linkId); err != nil {
+		return nil, err
+	}
+	link.Revoked = true
+	return link, nil
+}
+
+// signShare returns the base64url HMAC-SHA256 of the parts joined by "|".
+func (r *RepoManager) signShare(parts ...string) (string, error) {
+	key, err := r.getShareKey()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(parts, "|")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (r *RepoManager) verifyShare(sig string, parts ...string) bool {
+	expected, err := r.signShare(parts...)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(expected))
+}
+
+// getShareKey loads the signing key from storage, creating it on first use.
+// Deleting the key file invalidates every share link issued so far.
+func (r *RepoManager) getShareKey() ([]byte, error) {
+	r.shareKeyMu.Lock()
+	defer r.shareKeyMu.Unlock()
+
+	if r.shareKey != nil {
+		return r.shareKey, nil
+	}
+
+	keyPath := filepath.Join(r.GetStoragePath(), shareKeyFileName)
+	key, err := os.ReadFile(keyPath)
+	if err == nil && len(key) >= 32 {
+		r.shareKey = key
+		return key, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read share link key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate share link key: %w", err)
+	}
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write share link key: %w", err)
+	}
+	r.shareKey = key
+	return key, nil
+}
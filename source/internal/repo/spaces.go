@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"path"
+	"strings"
+)
+
+// RootSpaceName is the space of videos stored directly in the repository root.
+const RootSpaceName = "root"
+
+// SpaceNameFromPath returns the space a repository-relative video path belongs to:
+// its top-level folder, or RootSpaceName for files in the root.
+func SpaceNameFromPath(relativePath string) string {
+	relativePath = strings.TrimPrefix(path.Clean(strings.ReplaceAll(relativePath, "\\", "/")), "/")
+	if first, _, found := strings.Cut(relativePath, "/"); found {
+		return first
+	}
+	return RootSpaceName
+}
+
+// GetVideoSpaceName returns the name of the space that holds a video.
+func (r *RepoManager) GetVideoSpaceName(videoId string) (string, error) {
+	relativePath, err := r.GetVideoPathByID(videoId)
+	if err != nil {
+		return "", err
+	}
+	return SpaceNameFromPath(relativePath), nil
+}
+
+// GetSpaces returns every space that has stored settings.
+// Spaces without settings are open and have no members.
+func (r *RepoManager) GetSpaces() ([]datatypes.SpaceData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetAllSpaces()
+}
+
+// GetSpace returns the stored settings of a space.
+func (r *RepoManager) GetSpace(spaceName string) (*datatypes.SpaceData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetSpace(spaceName)
+}
+
+// SetSpacePrivate restricts a space to its members, or opens it again.
+func (r *RepoManager) SetSpacePrivate(spaceName string, private bool) (*datatypes.SpaceData, error) {
+	return r.updateSpace(spaceName, func(space *datatypes.SpaceData) error {
+		space.SpaceSettings.IsPrivate = private
+		return nil
+	})
+}
+
+// AddSpaceMember grants an account membership of a space.
+func (r *RepoManager) AddSpaceMember(spaceName, accountId string) (*datatypes.SpaceData, error) {
+	return r.updateSpace(spaceName, func(space *datatypes.SpaceData) error {
+		for _, member := range space.MemberIds {
+			if member == accountId {
+				return fmt.Errorf("account is already a member of space %q", spaceName)
+			}
+		}
+		space.MemberIds = append(space.MemberIds, accountId)
+		return nil
+	})
+}
+
+// RemoveSpaceMember revokes an account's membership of a space.
+func (r *RepoManager) RemoveSpaceMember(spaceName, accountId string) (*datatypes.SpaceData, error) {
+	return r.updateSpace(spaceName, func(space *datatypes.SpaceData) error {
+		for i, member := range space.MemberIds {
+			if member == accountId {
+				space.MemberIds = append(space.MemberIds[:i:i], space.MemberIds[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("account is not a member of space %q", spaceName)
+	})
+}
+
+// updateSpace loads a space, creating open settings for it on first use, applies fn and saves it.
+func (r *RepoManager) updateSpace(spaceName string, fn func(space *datatypes.SpaceData) error) (*datatypes.SpaceData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	spaceName = strings.TrimSpace(spaceName)
+	if spaceName == "" || strings.ContainsAny(spaceName, `/\`) {
+		return nil, fmt.Errorf("invalid space name %q", spaceName)
+	}
+
+	space, err := r.diskDataStorage.GetSpace(spaceName)
+	if err != nil {
+		created := datatypes.CreateDefaultSpaceData(spaceName, "")
+		created.SpaceId = spaceName
+		created.MemberIds = []string{}
+		created.SpaceSettings.IsPrivate = false
+		space = &created
+	}
+
+	if err := fn(space); err != nil {
+		return nil, err
+	}
+	if err := r.diskDataStorage.SaveSpace(*space); err != nil {
+		return nil, fmt.Errorf("failed to save space: %w", err)
+	}
+	return space, nil
+}
@@ -0,0 +1,459 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/logs"
+)
+
+var taskLogger = logs.Loggers("Tasks")
+
+const (
+	taskPollInterval   = 2 * time.Second  // Checks for due retries and tasks queued by other processes
+	taskLease          = time.Minute      // A running task whose worker stops renewing this long is given up
+	taskRetryBackoff   = 30 * time.Second // Wait before the second attempt, doubling per attempt
+	taskMaxBackoff     = 30 * time.Minute
+	taskRetention      = 7 * 24 * time.Hour // Finished tasks are removed after this long
+	taskPruneInterval  = time.Hour
+	taskStopTimeout    = 10 * time.Second // How long StopTaskRunner waits for running tasks
+	taskMediaInterval  = 3 * time.Second  // Live ffmpeg speed is stored at most this often when the percent stands still
+	taskUpdateAttempts = 5                // Tries to store a change to a task other processes keep changing
+)
+
+// taskRunner runs queued tasks on a bounded number of workers.
+type taskRunner struct {
+	worker    string // Identifies this process in TaskData.Worker
+	stop      context.CancelFunc
+	wake      chan struct{}
+	running   map[string]context.CancelFunc // Task ID to the cancel of its attempt
+	lastPrune time.Time
+	wg        sync.WaitGroup
+}
+
+// taskRun lets a task handler report on the task it runs.
+type taskRun struct {
+	r      *RepoManager
+	taskId string
+
+	lastPercent int // Last progress stored by SetVideoProgress
+	lastStored  time.Time
+}
+
+// Logf appends a line to the task's log.
+func (t *taskRun) Logf(format string, args ...interface{}) {
+	_, _ = t.r.modifyTask(t.taskId, func(task *datatypes.TaskData) error {
+		task.AddLog(format, args...)
+		return nil
+	})
+}
+
+// SetProgress records how far the task is, from 0 to 100.
+func (t *taskRun) SetProgress(progress int) {
+	_, _ = t.r.modifyTask(t.taskId, func(task *datatypes.TaskData) error {
+		task.Progress = max(0, min(progress, 100))
+		return nil
+	})
+}
+
+// SetVideoProgress records live progress of the video the task works on. ffmpeg reports
+// twice a second, so the task is only stored when the percent moves or every few seconds.
+func (t *taskRun) SetVideoProgress(percent int, media *datatypes.MediaProgress) {
+	percent = max(0, min(percent, 100))
+	if percent == t.lastPercent && time.Since(t.lastStored) < taskMediaInterval {
+		return
+	}
+	t.lastPercent, t.lastStored = percent, time.Now()
+	_, _ = t.r.modifyTask(t.taskId, func(task *datatypes.TaskData) error {
+		task.Progress = percent
+		task.Media = media
+		return nil
+	})
+}
+
+// SetVideoIDs records the videos the task works on.
+func (t *taskRun) SetVideoIDs(videoIds ...string) {
+	_, _ = t.r.modifyTask(t.taskId, func(task *datatypes.TaskData) error {
+		task.VideoIDs = videoIds
+		return nil
+	})
+}
+
+// permanentTaskError marks a failure that retrying cannot fix.
+type permanentTaskError struct {
+	err error
+}
+
+func (e *permanentTaskError) Error() string { return e.err.Error() }
+func (e *permanentTaskError) Unwrap() error { return e.err }
+
+func permanentTaskFailure(err error) error {
+	return &permanentTaskError{err: err}
+}
+
+// StartTaskRunner starts running queued tasks in the background until StopTaskRunner.
+// Starting it twice does nothing.
+func (r *RepoManager) StartTaskRunner() error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+	if r.taskRunner != nil {
+		return nil
+	}
+
+	host, _ := os.Hostname()
+	ctx, stop := context.WithCancel(context.Background())
+	runner := &taskRunner{
+		worker:  fmt.Sprintf("%s:%d", host, os.Getpid()),
+		stop:    stop,
+		wake:    make(chan struct{}, 1),
+		running: map[string]context.CancelFunc{},
+	}
+	r.taskRunner = runner
+
+	runner.wg.Add(1)
+	go r.runTaskLoop(ctx, runner)
+	taskLogger.Info("Task runner started with %d workers", r.taskWorkerCount())
+	return nil
+}
+
+// StopTaskRunner stops taking new tasks and interrupts the running ones, which are queued
+// again so the next runner resumes them.
+func (r *RepoManager) StopTaskRunner() {
+	r.tasksMu.Lock()
+	runner := r.taskRunner
+	r.taskRunner = nil
+	r.tasksMu.Unlock()
+	if runner == nil {
+		return
+	}
+
+	runner.stop()
+	done := make(chan struct{})
+	go func() {
+		runner.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(taskStopTimeout):
+		taskLogger.Warn("Stopped waiting for running tasks; they resume once their lease expires")
+	}
+}
+
+// wakeTaskRunner makes the runner look for work now instead of at its next poll.
+func (r *RepoManager) wakeTaskRunner() {
+	r.tasksMu.Lock()
+	runner := r.taskRunner
+	r.tasksMu.Unlock()
+	if runner != nil {
+		runner.nudge()
+	}
+}
+
+func (runner *taskRunner) nudge() {
+	select {
+	case runner.wake <- struct{}{}:
+	default:
+	}
+}
+
+// taskWorkerCount is how many tasks run at once.
+func (r *RepoManager) taskWorkerCount() int {
+	if r.configs.TaskWorkers > 0 {
+		return r.configs.TaskWorkers
+	}
+	return max(1, runtime.NumCPU()/2)
+}
+
+func (r *RepoManager) runTaskLoop(ctx context.Context, runner *taskRunner) {
+	defer runner.wg.Done()
+
+	ticker := time.NewTicker(taskPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.scheduleTasks(ctx, runner); err != nil {
+			taskLogger.Error("Failed to schedule tasks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-runner.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// scheduleTasks renews the leases of running tasks, passes on cancellations, gives up tasks
+// of workers that stopped and starts due tasks on free workers.
+func (r *RepoManager) scheduleTasks(ctx context.Context, runner *taskRunner) error {
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(runner.lastPrune) >= taskPruneInterval {
+		runner.lastPrune = now
+		if removed, err := r.diskDataStorage.DeleteFinishedTasks(now.Add(-taskRetention)); err != nil {
+			taskLogger.Warn("Failed to remove old tasks: %v", err)
+		} else if removed > 0 {
+			taskLogger.Info("Removed %d finished tasks", removed)
+		}
+	}
+
+	tasks, err := r.diskDataStorage.GetAllTasks()
+	if err != nil {
+		return err
+	}
+
+	due := []datatypes.TaskData{}
+	for _, task := range tasks {
+		switch {
+		case runner.running[task.ID] != nil && (task.Status != datatypes.TaskProcessing || task.Worker != runner.worker):
+			// Another process gave the attempt up after its lease ran out
+			runner.running[task.ID]()
+
+		case task.Status == datatypes.TaskProcessing && runner.running[task.ID] != nil:
+			if task.CancelRequested {
+				runner.running[task.ID]()
+			}
+			if task.LeaseUntil.Sub(now) < taskLease/2 {
+				previous := task
+				task.LeaseUntil = now.Add(taskLease)
+				// A task changed by another process is renewed on the next poll instead
+				if err := r.diskDataStorage.UpdateTask(previous, task); err != nil && !errors.Is(err, datatypes.ErrTaskChanged) {
+					return err
+				}
+			}
+
+		case task.Status == datatypes.TaskProcessing && now.After(task.LeaseUntil):
+			if err := r.releaseExpiredLeaseLocked(task); err != nil {
+				return err
+			}
+
+		case task.Status == datatypes.TaskPending && !now.Before(task.NotBefore) && !r.leftToRemoteWorkersLocked(task, now):
+			due = append(due, task)
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Priority > due[j].Priority
+	})
+	for _, task := range due {
+		if len(runner.running) >= r.taskWorkerCount() {
+			break
+		}
+
+		previous := task
+		task.Status = datatypes.TaskProcessing
+		task.Attempts++
+		task.Worker = runner.worker
+		task.StartedAt = now
+		task.LeaseUntil = now.Add(taskLease)
+		task.AddLog("Attempt %d of %d started on %s", task.Attempts, task.MaxAttempts, runner.worker)
+		if err := r.storeTaskLocked(&previous, task); errors.Is(err, datatypes.ErrTaskChanged) {
+			continue // Claimed or changed by another process since it was read
+		} else if err != nil {
+			return err
+		}
+
+		taskCtx, cancel := context.WithCancel(ctx)
+		runner.running[task.ID] = cancel
+		runner.wg.Add(1)
+		go r.runTask(taskCtx, runner, task)
+	}
+	return nil
+}
+
+// releaseExpiredLeaseLocked gives up the attempt of a task whose worker exited without
+// finishing it. It requires tasksMu.
+func (r *RepoManager) releaseExpiredLeaseLocked(task datatypes.TaskData) error {
+	previous := task
+	r.finishTaskAttempt(&task, fmt.Errorf("worker %s stopped responding", task.Worker), false)
+	if err := r.storeTaskLocked(&previous, task); errors.Is(err, datatypes.ErrTaskChanged) {
+		return nil // Renewed or released by another process since it was read
+	} else if err != nil {
+		return err
+	}
+	if strings.HasPrefix(previous.Worker, remoteWorkerName("")) {
+		r.removeTaskArtifacts(task.ID)
+	}
+	return nil
+}
+
+// runTask runs one attempt of a task and records how it ended.
+func (r *RepoManager) runTask(ctx context.Context, runner *taskRunner, task datatypes.TaskData) {
+	defer runner.wg.Done()
+
+	err := r.executeTask(ctx, task, &taskRun{r: r, taskId: task.ID})
+
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+	runner.running[task.ID]()
+	delete(runner.running, task.ID)
+
+	stopping := r.taskRunner != runner
+	finished, updateErr := r.modifyTaskLocked(task.ID, func(current *datatypes.TaskData) error {
+		if current.Status != datatypes.TaskProcessing || current.Worker != runner.worker {
+			return fmt.Errorf("%w: %s, given up by another process", ErrTaskNotLeased, task.ID)
+		}
+		r.finishTaskAttempt(current, err, stopping)
+		return nil
+	})
+	if updateErr != nil {
+		taskLogger.Error("Failed to record the end of task %s: %v", task.ID, updateErr)
+		return
+	}
+	if finished.Status == datatypes.TaskFailed {
+		taskLogger.Warn("Task %s (%s) failed: %s", finished.ID, finished.Type, finished.Error)
+	}
+	runner.nudge()
+}
+
+// finishTaskAttempt sets the status after an attempt ended with err. Attempts interrupted
+// because the runner stopped are queued again without counting.
+func (r *RepoManager) finishTaskAttempt(task *datatypes.TaskData, err error, stopping bool) {
+	now := time.Now().UTC()
+	task.Worker = ""
+	task.LeaseUntil = time.Time{}
+	task.Media = nil
+
+	var permanent *permanentTaskError
+	switch {
+	case task.CancelRequested:
+		task.Status = datatypes.TaskCancelled
+		task.FinishedAt = now
+		task.AddLog("Cancelled")
+
+	case stopping && err != nil:
+		task.Status = datatypes.TaskPending
+		task.Attempts--
+		task.AddLog("Interrupted by shutdown, will resume")
+
+	case err == nil:
+		task.Status = datatypes.TaskCompleted
+		task.Progress = 100
+		task.Error = ""
+		task.FinishedAt = now
+		task.AddLog("Completed")
+
+	case errors.As(err, &permanent) || task.Attempts >= task.MaxAttempts:
+		task.Status = datatypes.TaskFailed
+		task.Error = err.Error()
+		task.FinishedAt = now
+		task.AddLog("Failed: %v", err)
+
+	default:
+		backoff := min(taskRetryBackoff<<(task.Attempts-1), taskMaxBackoff)
+		task.Status = datatypes.TaskPending
+		task.Error = err.Error()
+		task.NotBefore = now.Add(backoff)
+		task.AddLog("Attempt %d failed: %v; retrying in %s", task.Attempts, err, backoff)
+	}
+}
+
+// executeTask runs the handler of the task's type.
+func (r *RepoManager) executeTask(ctx context.Context, task datatypes.TaskData, run *taskRun) error {
+	switch task.Type {
+	case datatypes.TaskIndexing:
+		return r.runIndexingTask(ctx, task, run)
+	case datatypes.TaskCooking:
+		return r.runCookingTask(ctx, task, run)
+	case datatypes.TaskRecommendations:
+		_, err := r.RebuildRecommendations()
+		return err
+	default:
+		return permanentTaskFailure(fmt.Errorf("unknown task type %q", task.Type))
+	}
+}
+
+func (r *RepoManager) runIndexingTask(ctx context.Context, task datatypes.TaskData, run *taskRun) error {
+	if _, err := os.Stat(task.VideoPath); err != nil {
+		return permanentTaskFailure(fmt.Errorf("video file is not readable: %w", err))
+	}
+	videoId, err := r.GenerateVideoID(task.VideoPath)
+	if err != nil {
+		return err
+	}
+	run.SetVideoIDs(videoId)
+
+	if r.CheckVideoIndexedByID(videoId) {
+		run.Logf("Video %s is already indexed", videoId)
+	} else {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := r.IndexVideo(ctx, task.VideoPath, task.AccountID); err != nil {
+			return err
+		}
+		run.Logf("Indexed as video %s", videoId)
+	}
+	run.SetProgress(100)
+
+	if !task.CookAfter {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	cooking, err := datatypes.NewTaskData(datatypes.TaskCooking, datatypes.TaskPriorityNormal)
+	if err != nil {
+		return err
+	}
+	cooking.VideoPath = task.VideoPath
+	cooking.AccountID = task.AccountID
+	cooking.ParentID = task.ID
+	if cooking, err = r.EnqueueTask(cooking); err != nil {
+		return fmt.Errorf("failed to queue cooking: %w", err)
+	}
+	run.Logf("Queued cooking as task %s", cooking.ID)
+	return nil
+}
+
+func (r *RepoManager) runCookingTask(ctx context.Context, task datatypes.TaskData, run *taskRun) error {
+	if _, err := os.Stat(task.VideoPath); err != nil {
+		return permanentTaskFailure(fmt.Errorf("video file is not readable: %w", err))
+	}
+	videoId, err := r.GenerateVideoID(task.VideoPath)
+	if err != nil {
+		return err
+	}
+	run.SetVideoIDs(videoId)
+
+	if !r.CheckVideoIndexedByID(videoId) {
+		return permanentTaskFailure(fmt.Errorf("video %s is not indexed", videoId))
+	}
+	profileName, profile, artifacts, err := r.planCooking(videoId, cookingOptionsOf(&task))
+	if err != nil {
+		return permanentTaskFailure(err)
+	}
+	if len(artifacts) == 0 {
+		run.Logf("Video %s is up to date with cooking profile %q", videoId, profileName)
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if task.Force {
+		run.Logf("Cooking %s with profile %q, forced", strings.Join(artifacts, ", "), profileName)
+	} else {
+		run.Logf("Cooking %s with profile %q", strings.Join(artifacts, ", "), profileName)
+	}
+	return r.cookArtifacts(ctx, task.VideoPath, videoId, profileName, profile, artifacts, run.SetVideoProgress)
+}
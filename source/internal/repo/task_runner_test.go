@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// TestTaskRunnersShareTheQueue runs two task runners on one repository, like `ovacli serve`
+// and `ovacli cook` do, and checks that every task is claimed by exactly one of them.
+func TestTaskRunnersShareTheQueue(t *testing.T) {
+	root := t.TempDir()
+	serve, err := NewRepoManager(root)
+	if err != nil {
+		t.Fatalf("open repository: %v", err)
+	}
+	cli, err := NewRepoManager(root)
+	if err != nil {
+		t.Fatalf("open repository again: %v", err)
+	}
+
+	taskIds := []string{}
+	for range 20 {
+		task, err := datatypes.NewTaskData(datatypes.TaskRecommendations, datatypes.TaskPriorityNormal)
+		if err != nil {
+			t.Fatalf("new task: %v", err)
+		}
+		queued, err := serve.EnqueueTask(task)
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+		taskIds = append(taskIds, queued.ID)
+	}
+
+	for _, r := range []*RepoManager{serve, cli} {
+		// Each manager must look like its own process
+		r.configs.TaskWorkers = 4
+		if err := r.StartTaskRunner(); err != nil {
+			t.Fatalf("start runner: %v", err)
+		}
+	}
+	serve.taskRunner.worker = "serve"
+	cli.taskRunner.worker = "cli"
+	defer serve.StopTaskRunner()
+	defer cli.StopTaskRunner()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	finished, err := cli.WaitForTasks(ctx, taskIds, nil)
+	if err != nil {
+		t.Fatalf("wait for tasks: %v", err)
+	}
+
+	for _, task := range finished {
+		started := 0
+		for _, entry := range task.Logs {
+			if strings.HasPrefix(entry.Message, "Attempt ") && strings.Contains(entry.Message, " started on ") {
+				started++
+			}
+		}
+		if task.Status != datatypes.TaskCompleted || task.Attempts != 1 || started != 1 {
+			t.Errorf("task %s: status %s after %d attempts and %d starts, want one completed attempt", task.ID, task.Status, task.Attempts, started)
+		}
+	}
+}
+
+// TestTaskClaimIsCompareAndSwap claims one pending task from two repository managers that
+// both read it as pending. Only the first claim may be stored.
+func TestTaskClaimIsCompareAndSwap(t *testing.T) {
+	root := t.TempDir()
+	serve, err := NewRepoManager(root)
+	if err != nil {
+		t.Fatalf("open repository: %v", err)
+	}
+	cli, err := NewRepoManager(root)
+	if err != nil {
+		t.Fatalf("open repository again: %v", err)
+	}
+
+	task, err := datatypes.NewTaskData(datatypes.TaskRecommendations, datatypes.TaskPriorityNormal)
+	if err != nil {
+		t.Fatalf("new task: %v", err)
+	}
+	if _, err := serve.EnqueueTask(task); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// Both read the pending task before either stores its claim
+	serveRead, _ := serve.diskDataStorage.GetTaskByID(task.ID)
+	cliRead, _ := cli.diskDataStorage.GetTaskByID(task.ID)
+	for _, c := range []struct {
+		r       *RepoManager
+		read    *datatypes.TaskData
+		worker  string
+		wantErr bool
+	}{
+		{serve, serveRead, "serve", false},
+		{cli, cliRead, "cli", true},
+	} {
+		claimed := *c.read
+		claimed.Status = datatypes.TaskProcessing
+		claimed.Worker = c.worker
+		err := c.r.storeTaskLocked(c.read, claimed)
+		if c.wantErr && !errors.Is(err, datatypes.ErrTaskChanged) {
+			t.Errorf("claim by %s: got %v, want ErrTaskChanged", c.worker, err)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("claim by %s: %v", c.worker, err)
+		}
+	}
+
+	stored, err := cli.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if stored.Worker != "serve" {
+		t.Errorf("task is claimed by %q, want serve", stored.Worker)
+	}
+}
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// Changes reported in TaskUpdate.Change.
+const (
+	TaskChangeQueued     = "queued"
+	TaskChangeStarted    = "started"
+	TaskChangeProgress   = "progress"
+	TaskChangeLog        = "log"
+	TaskChangeRetrying   = "retrying" // Failed, or was retried by hand, and waits for its next attempt
+	TaskChangeRequeued   = "requeued" // Interrupted by a shutdown or a worker that stopped
+	TaskChangeCompleted  = "completed"
+	TaskChangeFailed     = "failed"
+	TaskChangeCancelled  = "cancelled"
+	TaskChangeCancelling = "cancelling"
+	TaskChangePriority   = "priority"
+	TaskChangeUpdated    = "updated"
+)
+
+// Stages of an upload, as shown to the uploader.
+const (
+	TaskStageProcessing = "processing"
+	TaskStageComplete   = "complete"
+	TaskStageFailed     = "failed"
+	TaskStageCancelled  = "cancelled"
+)
+
+// TaskUpdate describes a change to a task, for live monitoring.
+type TaskUpdate struct {
+	Change         string               `json:"change"`
+	PreviousStatus datatypes.TaskStatus `json:"previousStatus,omitempty"`
+	Stage          string               `json:"stage"`
+	Task           datatypes.TaskData   `json:"task"` // Its log holds only the latest line
+}
+
+// OnTaskUpdate registers a listener that is called after every change to a task made by
+// this process. Listeners are called with tasks locked and must not block.
+func (r *RepoManager) OnTaskUpdate(listener func(TaskUpdate)) {
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+	r.taskListeners = append(r.taskListeners, listener)
+}
+
+// storeTaskLocked inserts the task when previous is nil and replaces it otherwise, then tells
+// the listeners what changed. It requires tasksMu.
+func (r *RepoManager) storeTaskLocked(previous *datatypes.TaskData, task datatypes.TaskData) error {
+	if previous == nil {
+		if err := r.diskDataStorage.InsertTask(task); err != nil {
+			return err
+		}
+	} else if err := r.diskDataStorage.UpdateTask(*previous, task); err != nil {
+		return err
+	}
+
+	change := taskChange(previous, &task)
+	if change == "" || len(r.taskListeners) == 0 {
+		return nil
+	}
+
+	update := TaskUpdate{Change: change, Stage: taskStage(&task), Task: task}
+	if previous != nil && previous.Status != task.Status {
+		update.PreviousStatus = previous.Status
+	}
+	if len(task.Logs) > 1 {
+		update.Task.Logs = task.Logs[len(task.Logs)-1:]
+	}
+	for _, listener := range r.taskListeners {
+		listener(update)
+	}
+	return nil
+}
+
+// taskChange names the most important difference between two versions of a task, or returns
+// "" when nothing a monitor shows changed.
+func taskChange(previous, task *datatypes.TaskData) string {
+	if previous == nil {
+		return TaskChangeQueued
+	}
+
+	if previous.Status != task.Status {
+		switch task.Status {
+		case datatypes.TaskProcessing:
+			return TaskChangeStarted
+		case datatypes.TaskCompleted:
+			return TaskChangeCompleted
+		case datatypes.TaskFailed:
+			return TaskChangeFailed
+		case datatypes.TaskCancelled:
+			return TaskChangeCancelled
+		case datatypes.TaskPending:
+			if previous.Status == datatypes.TaskProcessing && !task.NotBefore.After(time.Now()) {
+				return TaskChangeRequeued
+			}
+			return TaskChangeRetrying
+		}
+	}
+
+	switch {
+	case previous.CancelRequested != task.CancelRequested:
+		return TaskChangeCancelling
+	case previous.Priority != task.Priority:
+		return TaskChangePriority
+	case previous.Progress != task.Progress || mediaOf(previous) != mediaOf(task):
+		return TaskChangeProgress
+	case len(previous.VideoIDs) != len(task.VideoIDs) || previous.CookAfter != task.CookAfter:
+		return TaskChangeUpdated
+	case lastTaskLog(previous) != lastTaskLog(task):
+		return TaskChangeLog
+	}
+	return ""
+}
+
+func mediaOf(task *datatypes.TaskData) datatypes.MediaProgress {
+	if task.Media == nil {
+		return datatypes.MediaProgress{}
+	}
+	return *task.Media
+}
+
+func lastTaskLog(task *datatypes.TaskData) datatypes.TaskLogEntry {
+	if len(task.Logs) == 0 {
+		return datatypes.TaskLogEntry{}
+	}
+	return task.Logs[len(task.Logs)-1]
+}
+
+// taskStage maps a task to the stage of the upload it belongs to. Indexing that queues
+// cooking stays processing until the cooking task, whose ParentID points back, completes.
+func taskStage(task *datatypes.TaskData) string {
+	switch task.Status {
+	case datatypes.TaskCompleted:
+		if task.Type == datatypes.TaskIndexing && task.CookAfter {
+			return TaskStageProcessing
+		}
+		return TaskStageComplete
+	case datatypes.TaskFailed:
+		return TaskStageFailed
+	case datatypes.TaskCancelled:
+		return TaskStageCancelled
+	default:
+		return TaskStageProcessing
+	}
+}
@@ -0,0 +1,298 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// EnqueueIndexing queues indexing of a video file. With cookAfter the video is queued for
+// cooking once it is indexed.
+func (r *RepoManager) EnqueueIndexing(absolutePath, accountId string, cookAfter bool, priority int) (*datatypes.TaskData, error) {
+	task, err := datatypes.NewTaskData(datatypes.TaskIndexing, priority)
+	if err != nil {
+		return nil, err
+	}
+	task.VideoPath = absolutePath
+	task.AccountID = accountId
+	task.CookAfter = cookAfter
+	return r.EnqueueTask(task)
+}
+
+// EnqueueCooking queues cooking of an indexed video file as the options say.
+func (r *RepoManager) EnqueueCooking(absolutePath string, priority int, options CookingOptions) (*datatypes.TaskData, error) {
+	task, err := newCookingTask(absolutePath, priority, options)
+	if err != nil {
+		return nil, err
+	}
+	return r.EnqueueTask(task)
+}
+
+// EnqueueVideoCooking queues cooking of an indexed video by its ID, for the account that
+// asked. The options are checked before anything is queued.
+func (r *RepoManager) EnqueueVideoCooking(videoId, accountId string, priority int, options CookingOptions) (*datatypes.TaskData, error) {
+	if _, err := r.CheckCookingOptions(options); err != nil {
+		return nil, err
+	}
+	videoPath, err := r.GetVideoPathByID(videoId)
+	if err != nil {
+		return nil, err
+	}
+	task, err := newCookingTask(filepath.Join(r.rootDir, videoPath), priority, options)
+	if err != nil {
+		return nil, err
+	}
+	task.AccountID = accountId
+	return r.EnqueueTask(task)
+}
+
+func newCookingTask(absolutePath string, priority int, options CookingOptions) (*datatypes.TaskData, error) {
+	task, err := datatypes.NewTaskData(datatypes.TaskCooking, priority)
+	if err != nil {
+		return nil, err
+	}
+	task.VideoPath = absolutePath
+	task.Profile = options.Profile
+	// In making order, so the same request queued twice is recognised
+	for _, artifact := range datatypes.AllArtifacts {
+		if slices.Contains(options.Only, artifact) {
+			task.Only = append(task.Only, artifact)
+		}
+	}
+	task.Force = options.Force
+	return task, nil
+}
+
+// EnqueueTask stores a new task and wakes the task runner. When an unfinished task of the
+// same type for the same file is already queued, that task is returned instead.
+func (r *RepoManager) EnqueueTask(task *datatypes.TaskData) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	queued, err := r.enqueueTask(task)
+	if err != nil {
+		return nil, err
+	}
+	r.wakeTaskRunner()
+	return queued, nil
+}
+
+func (r *RepoManager) enqueueTask(task *datatypes.TaskData) (*datatypes.TaskData, error) {
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+
+	tasks, err := r.diskDataStorage.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range tasks {
+		if existing.IsFinished() || existing.Type != task.Type || existing.VideoPath != task.VideoPath || !sameCookingOptions(existing, *task) {
+			continue
+		}
+		if task.CookAfter && !existing.CookAfter {
+			previous := existing
+			existing.CookAfter = true
+			existing.AddLog("Cooking requested after indexing")
+			if err := r.storeTaskLocked(&previous, existing); err != nil {
+				return nil, err
+			}
+		}
+		return &existing, nil
+	}
+
+	task.AddLog("Queued")
+	if err := r.storeTaskLocked(nil, *task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// GetTask returns one task.
+func (r *RepoManager) GetTask(taskId string) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetTaskByID(taskId)
+}
+
+// GetTasks returns all tasks, oldest first.
+func (r *RepoManager) GetTasks() ([]datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetAllTasks()
+}
+
+// QueryTasks returns the tasks passing the filter, newest first.
+func (r *RepoManager) QueryTasks(filter datatypes.TaskFilter) ([]datatypes.TaskData, error) {
+	tasks, err := r.GetTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []datatypes.TaskData{}
+	for i := len(tasks) - 1; i >= 0; i-- {
+		if filter.Matches(&tasks[i]) {
+			matched = append(matched, tasks[i])
+		}
+	}
+	return matched, nil
+}
+
+// CancelTask cancels a task. Pending tasks are cancelled right away; running tasks are
+// stopped by their worker and marked cancelled once the current step returns.
+func (r *RepoManager) CancelTask(taskId string) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	task, err := r.modifyTask(taskId, func(task *datatypes.TaskData) error {
+		switch {
+		case task.IsFinished():
+			return fmt.Errorf("task %q is already %s", task.ID, task.Status)
+		case task.Status == datatypes.TaskPending:
+			task.Status = datatypes.TaskCancelled
+			task.FinishedAt = time.Now().UTC()
+			task.AddLog("Cancelled")
+		default:
+			task.CancelRequested = true
+			task.AddLog("Cancellation requested")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.wakeTaskRunner()
+	return task, nil
+}
+
+// RetryTask queues a failed or cancelled task again with a fresh set of attempts.
+func (r *RepoManager) RetryTask(taskId string) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	task, err := r.modifyTask(taskId, func(task *datatypes.TaskData) error {
+		if task.Status != datatypes.TaskFailed && task.Status != datatypes.TaskCancelled {
+			return fmt.Errorf("only failed or cancelled tasks can be retried, task %q is %s", task.ID, task.Status)
+		}
+		task.Status = datatypes.TaskPending
+		task.Attempts = 0
+		task.Progress = 0
+		task.Error = ""
+		task.CancelRequested = false
+		task.NotBefore = time.Time{}
+		task.FinishedAt = time.Time{}
+		task.AddLog("Retry requested")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.wakeTaskRunner()
+	return task, nil
+}
+
+// SetTaskPriority changes the priority of a task that has not finished. It only affects
+// tasks that have not started yet.
+func (r *RepoManager) SetTaskPriority(taskId string, priority int) (*datatypes.TaskData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	task, err := r.modifyTask(taskId, func(task *datatypes.TaskData) error {
+		if task.IsFinished() {
+			return fmt.Errorf("task %q is already %s", task.ID, task.Status)
+		}
+		task.AddLog("Priority changed from %d to %d", task.Priority, priority)
+		task.Priority = priority
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.wakeTaskRunner()
+	return task, nil
+}
+
+// WaitForTasks blocks until every given task is finished or ctx is done. onChange, when not
+// nil, is called with the current state of the tasks each time one of them changes.
+func (r *RepoManager) WaitForTasks(ctx context.Context, taskIds []string, onChange func([]datatypes.TaskData)) ([]datatypes.TaskData, error) {
+	lastUpdate := time.Time{}
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		current := make([]datatypes.TaskData, 0, len(taskIds))
+		finished := true
+		changed := time.Time{}
+		for _, id := range taskIds {
+			task, err := r.GetTask(id)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, *task)
+			finished = finished && task.IsFinished()
+			if task.UpdatedAt.After(changed) {
+				changed = task.UpdatedAt
+			}
+		}
+
+		if onChange != nil && changed.After(lastUpdate) {
+			lastUpdate = changed
+			onChange(current)
+		}
+		if finished {
+			return current, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return current, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// modifyTask applies a change to a stored task under tasksMu.
+func (r *RepoManager) modifyTask(taskId string, change func(task *datatypes.TaskData) error) (*datatypes.TaskData, error) {
+	r.tasksMu.Lock()
+	defer r.tasksMu.Unlock()
+	return r.modifyTaskLocked(taskId, change)
+}
+
+// modifyTaskLocked requires tasksMu. When another process changes the task between reading
+// and storing it, the change is applied again to the new version.
+func (r *RepoManager) modifyTaskLocked(taskId string, change func(task *datatypes.TaskData) error) (*datatypes.TaskData, error) {
+	for attempt := 1; ; attempt++ {
+		task, err := r.diskDataStorage.GetTaskByID(taskId)
+		if err != nil {
+			return nil, err
+		}
+		previous := *task
+		if err := change(task); err != nil {
+			return nil, err
+		}
+		task.UpdatedAt = time.Now().UTC()
+		err = r.storeTaskLocked(&previous, *task)
+		if errors.Is(err, datatypes.ErrTaskChanged) && attempt < taskUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return task, nil
+	}
+}
+
+// sameCookingOptions reports whether two tasks cook alike, so one can stand in for the other.
+func sameCookingOptions(a, b datatypes.TaskData) bool {
+	return a.Profile == b.Profile && a.Force == b.Force && slices.Equal(a.Only, b.Only)
+}
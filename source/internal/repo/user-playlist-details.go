@@ -13,14 +13,32 @@ func (r *RepoManager) GetPlaylistByID(userId, playlistId string) (*datatypes.Pla
 	return r.diskDataStorage.GetPlaylistByID(userId, playlistId)
 }
 
-// AddVideoToPlaylist adds a video ID to a specific playlist.
+// AddVideoToPlaylist adds a video ID to a playlist the user owns or may edit.
 func (r *RepoManager) AddVideoToPlaylist(userId, playlistId, videoID string) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
+	r.playlistMu.Lock()
+	defer r.playlistMu.Unlock()
+
+	if err := r.requirePlaylistEditor(userId, playlistId); err != nil {
+		return err
+	}
 	return r.diskDataStorage.AddVideoToPlaylist(userId, playlistId, videoID)
 }
 
+// requirePlaylistEditor fails unless the user may add and remove videos of the playlist.
+func (r *RepoManager) requirePlaylistEditor(userId, playlistId string) error {
+	playlist, err := r.diskDataStorage.GetPlaylist(playlistId)
+	if err != nil {
+		return err
+	}
+	if !r.CanEditPlaylist(userId, playlist) {
+		return fmt.Errorf("playlist with id %q cannot be edited by user %q", playlistId, userId)
+	}
+	return nil
+}
+
 func (r *RepoManager) AddVideosToPlaylists(userId string, videoIDs, playlistIDs []string) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
@@ -31,6 +49,15 @@ func (r *RepoManager) AddVideosToPlaylists(userId string, videoIDs, playlistIDs
 		return fmt.Errorf("videoIDs or playlistIDs are empty")
 	}
 
+	r.playlistMu.Lock()
+	defer r.playlistMu.Unlock()
+
+	for _, playlistID := range playlistIDs {
+		if err := r.requirePlaylistEditor(userId, playlistID); err != nil {
+			return err
+		}
+	}
+
 	// Iterate through the video IDs and add them to all playlists
 	for _, videoID := range videoIDs {
 		for _, playlistID := range playlistIDs {
@@ -44,28 +71,99 @@ func (r *RepoManager) AddVideosToPlaylists(userId string, videoIDs, playlistIDs
 	return nil
 }
 
-// RemoveVideoFromPlaylist removes a video ID from a specific playlist.
+// RemoveVideoFromPlaylist removes a video ID from a playlist the user owns or may edit.
 func (r *RepoManager) RemoveVideoFromPlaylist(userId, playlistId, videoID string) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
+	r.playlistMu.Lock()
+	defer r.playlistMu.Unlock()
+
+	if err := r.requirePlaylistEditor(userId, playlistId); err != nil {
+		return err
+	}
 	return r.diskDataStorage.RemoveVideoFromPlaylist(userId, playlistId, videoID)
 }
 
-func (r *RepoManager) GetPlaylistVideoIDsPaginated(userId, playlistId string, page, limit int) ([]*datatypes.VideoData, int, error) {
+// GetPlaylistVideoIDsPaginated returns one page of a playlist's videos for a viewer.
+// The playlist must be visible to the viewer, and videos the viewer may not see are skipped.
+func (r *RepoManager) GetPlaylistVideoIDsPaginated(viewerId, playlistId string, page, limit int) ([]*datatypes.VideoData, int, error) {
 	if !r.IsDataStorageInitialized() {
 		return nil, 0, fmt.Errorf("data storage is not initialized")
 	}
 
-	ids, total, err := r.diskDataStorage.GetPlaylistVideoIDsPaginated(userId, playlistId, page, limit)
+	playlist, err := r.diskDataStorage.GetPlaylist(playlistId)
 	if err != nil {
 		return nil, 0, err
 	}
+	if !r.CanViewPlaylist(viewerId, playlist) {
+		return nil, 0, fmt.Errorf("access denied")
+	}
 
-	videos, err := r.GetVideosByIDs(ids)
+	videos, err := r.GetVideosByIDs(playlist.VideoIDs)
 	if err != nil {
 		return nil, 0, err
 	}
+	videos = r.FilterVisibleVideoRefs(viewerId, videos)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	total := len(videos)
+	start := (page - 1) * limit
+	if start >= total {
+		return []*datatypes.VideoData{}, total, nil
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return videos[start:end], total, nil
+}
+
+// GetPlaylist returns a playlist by ID if the viewer may open it.
+func (r *RepoManager) GetPlaylist(viewerId, playlistId string) (*datatypes.PlaylistData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
 
-	return videos, total, nil
+	playlist, err := r.diskDataStorage.GetPlaylist(playlistId)
+	if err != nil {
+		return nil, err
+	}
+	if !r.CanViewPlaylist(viewerId, playlist) {
+		return nil, fmt.Errorf("playlist with id %q not found", playlistId)
+	}
+	return playlist, nil
+}
+
+// SetPlaylistPrivacy changes the privacy of a playlist owned by the user.
+func (r *RepoManager) SetPlaylistPrivacy(accountId, playlistId string, privacy datatypes.PrivacySetting) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	if !datatypes.IsValidPrivacySetting(privacy) {
+		return fmt.Errorf("unknown privacy setting %q", privacy)
+	}
+	return r.diskDataStorage.UpdatePlaylistPrivacy(accountId, playlistId, privacy)
+}
+
+// GetPublicPlaylistsByUser returns the playlists of a user that are listed publicly.
+func (r *RepoManager) GetPublicPlaylistsByUser(accountId string) ([]datatypes.PlaylistData, error) {
+	playlists, err := r.GetPlaylistsByUser(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	public := make([]datatypes.PlaylistData, 0, len(playlists))
+	for _, playlist := range playlists {
+		if playlist.Privacy == datatypes.Public {
+			public = append(public, playlist)
+		}
+	}
+	return public, nil
 }
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserAccountUpdate holds the changes an admin makes to an account; nil fields are kept.
+type UserAccountUpdate struct {
+	DisplayName *string
+	Role        *datatypes.UserRole
+	Disabled    *bool
+}
+
+// UpdateUserAccount checks all the changes and then saves them at once, so a rejected
+// change leaves the account as it was. The repository owner always stays an enabled admin.
+// Disabling also ends all of the account's sessions.
+func (r *RepoManager) UpdateUserAccount(accountId string, update UserAccountUpdate) (*datatypes.UserData, error) {
+	if update.Role != nil {
+		if !datatypes.IsValidUserRole(*update.Role) {
+			return nil, fmt.Errorf("unknown role %q", *update.Role)
+		}
+		if accountId == r.configs.RootUser && *update.Role != datatypes.RoleAdmin {
+			return nil, fmt.Errorf("the repository owner must stay an admin")
+		}
+	}
+	disable := update.Disabled != nil && *update.Disabled
+	if disable && accountId == r.configs.RootUser {
+		return nil, fmt.Errorf("the repository owner cannot be disabled")
+	}
+
+	user, err := r.updateUser(accountId, func(user *datatypes.UserData) error {
+		if update.DisplayName != nil {
+			user.DisplayName = strings.TrimSpace(*update.DisplayName)
+		}
+		if update.Role != nil {
+			user.Role = *update.Role
+		}
+		if update.Disabled != nil {
+			user.Disabled = *update.Disabled
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if disable {
+		r.DeleteSessionsByAccountID(accountId)
+	}
+	return user, nil
+}
+
+// ResetUserPassword sets a new password for a local user and ends all of their sessions.
+func (r *RepoManager) ResetUserPassword(accountId, newPassword string) error {
+	if newPassword == "" {
+		return fmt.Errorf("password cannot be empty")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("cannot create hash from password: %w", err)
+	}
+
+	_, err = r.updateUser(accountId, func(user *datatypes.UserData) error {
+		if user.AuthProvider != "" {
+			return fmt.Errorf("password is managed by the %q provider", user.AuthProvider)
+		}
+		user.PasswordHash = string(hash)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.DeleteSessionsByAccountID(accountId)
+	return nil
+}
+
+// UpdateUserLastLogin records the time of a successful login.
+func (r *RepoManager) UpdateUserLastLogin(accountId string) error {
+	_, err := r.updateUser(accountId, func(user *datatypes.UserData) error {
+		user.LastLoginAt = time.Now().UTC()
+		return nil
+	})
+	return err
+}
+
+// updateUser loads a user, applies change and saves the result.
+func (r *RepoManager) updateUser(accountId string, change func(user *datatypes.UserData) error) (*datatypes.UserData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(user); err != nil {
+		return nil, err
+	}
+	if err := r.diskDataStorage.UpdateUser(*user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return user, nil
+}
+
+// IsAdmin reports whether the account has the admin role.
+func (r *RepoManager) IsAdmin(accountId string) bool {
+	if accountId == r.configs.RootUser {
+		return true
+	}
+	user, err := r.GetUserByAccountID(accountId)
+	if err != nil {
+		return false
+	}
+	return r.GetUserRole(user) == datatypes.RoleAdmin
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPITokenTTL is used when a token is created without an explicit lifetime.
+	DefaultAPITokenTTL = 90 * 24 * time.Hour
+	// MaxAPITokenTTL caps how long a personal API token may stay valid.
+	MaxAPITokenTTL = 365 * 24 * time.Hour
+
+	// apiTokenLastUsedInterval limits how often LastUsedAt is written back to storage.
+	apiTokenLastUsedInterval = time.Minute
+)
+
+// CreateAPIToken issues a new personal API token for a user.
+// The plain token is returned only here; storage keeps a hash of its secret.
+func (r *RepoManager) CreateAPIToken(accountId, name string, scopes []datatypes.TokenScope, ttl time.Duration) (*datatypes.APITokenData, string, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, "", fmt.Errorf("data storage is not initialized")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("token name is required")
+	}
+	if len(scopes) == 0 {
+		scopes = []datatypes.TokenScope{datatypes.TokenScopeRead}
+	}
+	for _, scope := range scopes {
+		if !datatypes.IsValidTokenScope(scope) {
+			return nil, "", fmt.Errorf("unknown token scope %q", scope)
+		}
+	}
+	if ttl <= 0 {
+		ttl = DefaultAPITokenTTL
+	}
+	if ttl > MaxAPITokenTTL {
+		return nil, "", fmt.Errorf("token lifetime cannot exceed %d days", int(MaxAPITokenTTL.Hours()/24))
+	}
+
+	token, plain, err := datatypes.NewAPITokenData(accountId, name, scopes, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := r.diskDataStorage.InsertAPIToken(token); err != nil {
+		return nil, "", fmt.Errorf("failed to store api token: %w", err)
+	}
+	return token, plain, nil
+}
+
+// AuthenticateAPIToken resolves a plain "ova_<id>.<secret>" token to its stored record.
+// Unknown, malformed and expired tokens all return an error.
+func (r *RepoManager) AuthenticateAPIToken(plain string) (*datatypes.APITokenData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	id, secret, ok := strings.Cut(strings.TrimPrefix(plain, datatypes.APITokenPrefix), ".")
+	if !ok || !strings.HasPrefix(plain, datatypes.APITokenPrefix) || id == "" || secret == "" {
+		return nil, fmt.Errorf("malformed api token")
+	}
+
+	token, err := r.diskDataStorage.GetAPITokenByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api token")
+	}
+
+	hash := datatypes.HashAPITokenSecret(secret)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(token.SecretHash)) != 1 {
+		return nil, fmt.Errorf("invalid api token")
+	}
+	if token.IsExpired() {
+		return nil, fmt.Errorf("api token has expired")
+	}
+	owner, err := r.diskDataStorage.GetUserByAccountID(token.AccountID)
+	if err != nil || owner.Disabled {
+		return nil, fmt.Errorf("api token owner is disabled or missing")
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(token.LastUsedAt) > apiTokenLastUsedInterval {
+		token.LastUsedAt = now
+		_ = r.diskDataStorage.UpdateAPITokenLastUsed(token.ID, now)
+	}
+	return token, nil
+}
+
+// GetAPITokens lists the tokens owned by a user.
+func (r *RepoManager) GetAPITokens(accountId string) ([]datatypes.APITokenData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetAPITokensByAccountID(accountId)
+}
+
+// RevokeAPIToken deletes a token owned by a user.
+func (r *RepoManager) RevokeAPIToken(accountId, tokenId string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.DeleteAPIToken(accountId, tokenId)
+}
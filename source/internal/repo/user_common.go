@@ -10,6 +10,13 @@ func (r *RepoManager) CreateUser(userdata *datatypes.UserData) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
+	// Usernames are the login key, so they must stay unique
+	r.usersMu.Lock()
+	defer r.usersMu.Unlock()
+	if _, err := r.diskDataStorage.GetUserByUsername(userdata.Username); err == nil {
+		return fmt.Errorf("username %q is already taken", userdata.Username)
+	}
+
 	// Store user
 	if err := r.diskDataStorage.InsertUser(userdata); err != nil {
 		return fmt.Errorf("failed to create user in data storage: %w", err)
@@ -2,14 +2,23 @@ package repo
 
 import (
 	"fmt"
+	"time"
 )
 
-// AddVideoToWatched adds a video ID to the watched list of a user.
-func (r *RepoManager) AddVideoToWatched(username, videoID string) error {
+// AddVideoToWatched adds a video ID to the watched list of a user and records watchedSec
+// of viewing in their history. Nothing is recorded while the user has paused their history.
+func (r *RepoManager) AddVideoToWatched(username, videoID string, watchedSec float64) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
-	return r.diskDataStorage.AddVideoToWatched(username, videoID)
+	if r.IsHistoryPaused(username) {
+		return nil
+	}
+	if err := r.diskDataStorage.AddVideoToWatched(username, videoID); err != nil {
+		return err
+	}
+	_, err := r.RecordWatch(username, videoID, watchedSec)
+	return err
 }
 
 // GetUserWatchedVideos returns the list of watched videos for a user.
@@ -20,16 +29,16 @@ func (r *RepoManager) GetUserWatchedVideos(username string) ([]string, error) {
 	return r.diskDataStorage.GetUserWatchedVideos(username)
 }
 
-// GetUserWatchedVideosInRange returns a range of watched videos for a user.
+// GetUserWatchedVideosInRange returns a range of the user's recently watched videos, newest first.
 func (r *RepoManager) GetUserWatchedVideosInRange(username string, start, end int) ([]string, error) {
 	if !r.IsDataStorageInitialized() {
 		return nil, fmt.Errorf("data storage is not initialized")
 	}
 
-	// Retrieve the full list of watched videoIds first
-	videoIds, err := r.diskDataStorage.GetUserWatchedVideos(username)
+	// Recently watched is driven by the watch history
+	videoIds, err := r.getRecentlyWatchedIDs(username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get watched videos: %v", err)
+		return nil, err
 	}
 
 	// Validate the range values
@@ -41,26 +50,33 @@ func (r *RepoManager) GetUserWatchedVideosInRange(username string, start, end in
 	return videoIds[start:end], nil
 }
 
-// GetUserWatchedVideosCount returns the count of watched videos for a user.
+// GetUserWatchedVideosCount returns the number of recently watched videos of a user.
 func (r *RepoManager) GetUserWatchedVideosCount(username string) (int, error) {
 	if !r.IsDataStorageInitialized() {
 		return 0, fmt.Errorf("data storage is not initialized")
 	}
 
-	// Retrieve the full list of watched videos
-	videos, err := r.diskDataStorage.GetUserWatchedVideos(username)
+	videos, err := r.getRecentlyWatchedIDs(username)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get watched videos: %v", err)
+		return 0, err
 	}
-
-	// Return the count of watched videos
 	return len(videos), nil
 }
 
-// ClearUserWatchedHistory clears all watched videos of a user.
+// ClearUserWatchedHistory clears all watched videos of a user, along with their watch history
+// and playback positions.
 func (r *RepoManager) ClearUserWatchedHistory(username string) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
+	if err := r.diskDataStorage.ClearPlaybackStates(username); err != nil {
+		return err
+	}
+	if _, err := r.diskDataStorage.DeleteWatchEvents(username, time.Time{}, time.Time{}); err != nil {
+		return err
+	}
+	if _, err := r.diskDataStorage.GetUserWatchedVideos(username); err != nil {
+		return nil // Only history entries, no watched list to clear
+	}
 	return r.diskDataStorage.ClearUserWatchedHistory(username)
 }
@@ -0,0 +1,235 @@
+package repo
+
+import (
+	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"time"
+)
+
+// loginPolicy describes how quickly repeated failures for one key are throttled.
+type loginPolicy struct {
+	freeAttempts    int           // failures allowed before any backoff applies
+	baseDelay       time.Duration // first backoff delay, doubled on every further failure
+	maxDelay        time.Duration // upper bound for the exponential backoff
+	lockoutAttempts int           // failures that trigger a temporary lockout
+	lockoutDuration time.Duration
+}
+
+// Usernames are throttled hard; client IPs get more room because a whole
+// studio can sit behind a single NAT address.
+var (
+	usernameLoginPolicy = loginPolicy{
+		freeAttempts:    3,
+		baseDelay:       time.Second,
+		maxDelay:        5 * time.Minute,
+		lockoutAttempts: 10,
+		lockoutDuration: 15 * time.Minute,
+	}
+	ipLoginPolicy = loginPolicy{
+		freeAttempts:    10,
+		baseDelay:       time.Second,
+		maxDelay:        5 * time.Minute,
+		lockoutAttempts: 50,
+		lockoutDuration: 30 * time.Minute,
+	}
+	// a share link password is guessed like a username's, but only ever by visitors
+	shareLinkPasswordPolicy = usernameLoginPolicy
+)
+
+// loginFailureResetAfter forgets a failure streak once it has been idle this long.
+const loginFailureResetAfter = 24 * time.Hour
+
+// blockDuration returns how long a key must wait after reaching the given failure count.
+func (p loginPolicy) blockDuration(failures int) time.Duration {
+	if failures >= p.lockoutAttempts {
+		return p.lockoutDuration
+	}
+	if failures <= p.freeAttempts {
+		return 0
+	}
+
+	delay := p.baseDelay
+	for i := p.freeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= p.maxDelay {
+			return p.maxDelay
+		}
+	}
+	return delay
+}
+
+func usernameLoginKey(username string) string {
+	return "user:" + username
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+func shareLinkLoginKey(linkId string) string {
+	return "share:" + linkId
+}
+
+// guardedKey is one failure counter an attempt is charged to.
+type guardedKey struct {
+	key    string
+	policy loginPolicy
+}
+
+// BeginLoginAttempt admits a login attempt for this username and client IP, or reports how
+// long the caller must wait first. An admitted attempt is counted as a failure right away, in
+// the same locked step as the check, so parallel requests cannot all slip past the limit
+// before the first failure is recorded. A correct password gives the count back with
+// RefundLoginAttempt.
+func (r *RepoManager) BeginLoginAttempt(username, ip string) (time.Duration, error) {
+	if !r.IsDataStorageInitialized() {
+		return 0, fmt.Errorf("data storage is not initialized")
+	}
+
+	return r.beginGuardedAttempt([]guardedKey{
+		{usernameLoginKey(username), usernameLoginPolicy},
+		{ipLoginKey(ip), ipLoginPolicy},
+	})
+}
+
+// beginGuardedAttempt checks every key and, when none is blocked, counts one failure on each.
+func (r *RepoManager) beginGuardedAttempt(tracked []guardedKey) (time.Duration, error) {
+	r.loginMu.Lock()
+	defer r.loginMu.Unlock()
+
+	now := time.Now().UTC()
+	attempts := make([]*datatypes.LoginAttemptData, len(tracked))
+	var wait time.Duration
+	for i, t := range tracked {
+		attempt, err := r.diskDataStorage.GetLoginAttempt(t.key)
+		if err != nil {
+			return 0, err
+		}
+		if remaining := attempt.BlockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+		attempts[i] = attempt
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+
+	for i, t := range tracked {
+		attempt := attempts[i]
+		if now.Sub(attempt.LastFailure) > loginFailureResetAfter {
+			attempt.Failures = 0
+		}
+		attempt.Failures++
+		attempt.LastFailure = now
+		if block := t.policy.blockDuration(attempt.Failures); block > 0 {
+			attempt.BlockedUntil = now.Add(block)
+		}
+		if err := r.diskDataStorage.SaveLoginAttempt(*attempt); err != nil {
+			return 0, fmt.Errorf("failed to save login attempt: %w", err)
+		}
+	}
+	return 0, nil
+}
+
+// RegisterLoginFailure records a failed attempt admitted by BeginLoginAttempt for admins.
+// It returns the time the caller now has to wait before retrying.
+func (r *RepoManager) RegisterLoginFailure(username, ip, reason string) (time.Duration, error) {
+	if !r.IsDataStorageInitialized() {
+		return 0, fmt.Errorf("data storage is not initialized")
+	}
+
+	now := time.Now().UTC()
+	event := datatypes.LoginFailureEvent{
+		Username:  username,
+		IP:        ip,
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	if err := r.diskDataStorage.InsertLoginFailure(event); err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	var wait time.Duration
+	for _, key := range []string{usernameLoginKey(username), ipLoginKey(ip)} {
+		attempt, err := r.diskDataStorage.GetLoginAttempt(key)
+		if err != nil {
+			return 0, err
+		}
+		if remaining := attempt.BlockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait, nil
+}
+
+// RefundLoginAttempt gives back the count BeginLoginAttempt took from the client IP once the
+// password turned out to be right. The IP streak is never cleared, so an attacker holding one
+// valid account cannot reset it between guesses.
+func (r *RepoManager) RefundLoginAttempt(ip string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	r.loginMu.Lock()
+	defer r.loginMu.Unlock()
+
+	attempt, err := r.diskDataStorage.GetLoginAttempt(ipLoginKey(ip))
+	if err != nil || attempt.Failures == 0 {
+		return err
+	}
+	attempt.Failures--
+	if ipLoginPolicy.blockDuration(attempt.Failures) == 0 {
+		attempt.BlockedUntil = time.Time{}
+	}
+	if attempt.Failures == 0 {
+		return r.diskDataStorage.DeleteLoginAttempt(attempt.Key)
+	}
+	return r.diskDataStorage.SaveLoginAttempt(*attempt)
+}
+
+// RegisterLoginSuccess clears the failure streak of the username. The client IP keeps its
+// streak; see RefundLoginAttempt.
+func (r *RepoManager) RegisterLoginSuccess(username string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	r.loginMu.Lock()
+	defer r.loginMu.Unlock()
+
+	// Missing keys are the common case, so their errors are ignored.
+	_ = r.diskDataStorage.DeleteLoginAttempt(usernameLoginKey(username))
+	return nil
+}
+
+// UnlockLoginUser lifts any backoff or lockout placed on a username.
+func (r *RepoManager) UnlockLoginUser(username string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.DeleteLoginAttempt(usernameLoginKey(username))
+}
+
+// UnlockLoginIP lifts any backoff or lockout placed on a client IP.
+func (r *RepoManager) UnlockLoginIP(ip string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.DeleteLoginAttempt(ipLoginKey(ip))
+}
+
+// GetLoginAttempts returns every username and IP that currently has a failure streak.
+func (r *RepoManager) GetLoginAttempts() ([]datatypes.LoginAttemptData, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetAllLoginAttempts()
+}
+
+// GetLoginFailures returns recorded failed logins newest first, optionally for one username.
+func (r *RepoManager) GetLoginFailures(username string) ([]datatypes.LoginFailureEvent, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.GetLoginFailures(username)
+}
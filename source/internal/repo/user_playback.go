@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// ContinueWatchingItem is a video the user started and did not finish.
+type ContinueWatchingItem struct {
+	Video    *datatypes.VideoData    `json:"video"`
+	Playback datatypes.PlaybackState `json:"playback"`
+}
+
+// ReportPlayback records where a user is in a video. The player calls it periodically.
+// When durationSec is 0 the duration probed at indexing is used. Each report also extends the
+// user's watch history and counts a view once they watched enough of the video. The video is
+// added to their watched list the first time playback gets past datatypes.PlaybackCompletePercent,
+// unless they paused their history.
+func (r *RepoManager) ReportPlayback(accountId, videoId string, positionSec, durationSec float64) (*datatypes.PlaybackState, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	if positionSec < 0 || durationSec < 0 {
+		return nil, fmt.Errorf("position and duration cannot be negative")
+	}
+
+	video, err := r.diskDataStorage.GetVideoByID(videoId)
+	if err != nil {
+		return nil, err
+	}
+	if durationSec == 0 {
+		durationSec = float64(video.Codecs.DurationSec)
+	}
+
+	state := datatypes.PlaybackState{
+		VideoID:     videoId,
+		PositionSec: positionSec,
+		DurationSec: durationSec,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if durationSec > 0 {
+		state.PositionSec = math.Min(positionSec, durationSec)
+		state.Percent = math.Round(state.PositionSec/durationSec*1000) / 10
+	}
+
+	previous, err := r.diskDataStorage.GetPlaybackState(accountId, videoId)
+	hasPrevious := err == nil
+	alreadyCompleted := hasPrevious && previous.Completed
+	state.Completed = alreadyCompleted || state.Percent >= datatypes.PlaybackCompletePercent
+
+	// Time watched since the last report, capped by the time that passed so seeking forward does not count
+	watchedSec := 0.0
+	if hasPrevious {
+		if elapsed := state.UpdatedAt.Sub(previous.UpdatedAt); elapsed <= datatypes.WatchSessionGap {
+			watchedSec = math.Max(0, math.Min(state.PositionSec-previous.PositionSec, elapsed.Seconds()))
+			state.WatchedSec = previous.WatchedSec
+		}
+	}
+	state.WatchedSec += watchedSec
+
+	if err := r.diskDataStorage.SavePlaybackState(accountId, state); err != nil {
+		return nil, err
+	}
+	if _, err := r.RecordWatch(accountId, videoId, watchedSec); err != nil {
+		return nil, fmt.Errorf("failed to record watch history: %w", err)
+	}
+	// The position is saved already, a missed view must not fail the report
+	if err := r.countPlaybackView(accountId, &state); err != nil {
+		statsLogger.Warn("Failed to count view of %s: %v", videoId, err)
+	}
+	if state.Completed && !alreadyCompleted && !r.IsHistoryPaused(accountId) {
+		if err := r.diskDataStorage.AddVideoToWatched(accountId, videoId); err != nil {
+			return nil, fmt.Errorf("failed to mark video as watched: %w", err)
+		}
+	}
+	return &state, nil
+}
+
+// GetPlaybackState returns where a user is in a video. Videos the user never played
+// return a zero state.
+func (r *RepoManager) GetPlaybackState(accountId, videoId string) (datatypes.PlaybackState, error) {
+	if !r.IsDataStorageInitialized() {
+		return datatypes.PlaybackState{}, fmt.Errorf("data storage is not initialized")
+	}
+
+	state, err := r.diskDataStorage.GetPlaybackState(accountId, videoId)
+	if err != nil {
+		return datatypes.PlaybackState{VideoID: videoId}, nil
+	}
+	return *state, nil
+}
+
+// GetPlaybackStatesByVideo returns all playback states of a user keyed by video ID.
+func (r *RepoManager) GetPlaybackStatesByVideo(accountId string) (map[string]datatypes.PlaybackState, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	states, err := r.diskDataStorage.GetPlaybackStates(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	byVideo := make(map[string]datatypes.PlaybackState, len(states))
+	for _, state := range states {
+		byVideo[state.VideoID] = state
+	}
+	return byVideo, nil
+}
+
+// GetContinueWatching returns the videos a user started and did not finish, most recently
+// played first. Videos the user can no longer see are left out. limit <= 0 returns all.
+func (r *RepoManager) GetContinueWatching(accountId string, limit int) ([]ContinueWatchingItem, error) {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := r.diskDataStorage.GetPlaybackStates(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []ContinueWatchingItem{}
+	for _, state := range states {
+		if !state.InProgress() {
+			continue
+		}
+		video, err := r.diskDataStorage.GetVideoByID(state.VideoID)
+		if err != nil || !access.canView(video) {
+			continue
+		}
+		items = append(items, ContinueWatchingItem{Video: video, Playback: state})
+		if limit > 0 && len(items) == limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+// ForgetPlayback removes a video from a user's continue watching list.
+func (r *RepoManager) ForgetPlayback(accountId, videoId string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.DeletePlaybackState(accountId, videoId)
+}
@@ -9,7 +9,11 @@ func (r *RepoManager) AddVideoToSaved(username, videoID string) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
-	return r.diskDataStorage.AddVideoToSaved(username, videoID)
+	if err := r.diskDataStorage.AddVideoToSaved(username, videoID); err != nil {
+		return err
+	}
+	r.recordInteraction(username, videoID)
+	return nil
 }
 
 // GetUserSavedVideos returns the list of saved (favorite) videos for a user.
@@ -12,6 +12,11 @@ func (r *RepoManager) DeleteSession(sessionID string) error {
 	return r.sessionDataStorage.DeleteSession(sessionID)
 }
 
+// DeleteSessionsByAccountID logs an account out of every session.
+func (r *RepoManager) DeleteSessionsByAccountID(accountId string) (int, error) {
+	return r.sessionDataStorage.DeleteSessionsByAccountID(accountId)
+}
+
 func (r *RepoManager) SaveUserSessionOnDisk() error {
 	return r.sessionDataStorage.SaveOnDisk()
 }
@@ -0,0 +1,355 @@
+package repo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/totp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// twoFactorChallengeTTL is how long a password-verified login waits for its TOTP code.
+	twoFactorChallengeTTL = 5 * time.Minute
+	// twoFactorChallengeAttempts is how many wrong codes one challenge accepts before it is dropped.
+	twoFactorChallengeAttempts = 5
+	// recoveryCodeCount is how many single-use recovery codes are issued at a time.
+	recoveryCodeCount = 10
+)
+
+// twoFactorChallenge is a login that passed the password check and awaits a second factor.
+type twoFactorChallenge struct {
+	AccountID string
+	Username  string
+	ExpiresAt time.Time
+	Attempts  int
+}
+
+// recoveryCodeAlphabet leaves out characters that are easy to confuse when typed.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// BeginTwoFactorSetup generates a new pending TOTP secret for the user and returns it
+// with the otpauth:// provisioning URI. The secret becomes active only after EnableTwoFactor.
+func (r *RepoManager) BeginTwoFactorSetup(accountId string) (string, string, error) {
+	if !r.IsDataStorageInitialized() {
+		return "", "", fmt.Errorf("data storage is not initialized")
+	}
+
+	r.twoFactorMu.Lock()
+	defer r.twoFactorMu.Unlock()
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return "", "", err
+	}
+	if user.IsTwoFactorEnabled() {
+		return "", "", fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if user.TwoFactor == nil {
+		user.TwoFactor = &datatypes.TwoFactorData{}
+	}
+	user.TwoFactor.PendingSecret = secret
+	if err := r.diskDataStorage.UpdateUser(*user); err != nil {
+		return "", "", fmt.Errorf("failed to save two-factor setup: %w", err)
+	}
+
+	return secret, totp.ProvisioningURI(r.twoFactorIssuer(), user.Username, secret), nil
+}
+
+// EnableTwoFactor activates the pending secret once the user proves it with a valid code.
+// It returns freshly generated recovery codes, which are only shown this once.
+func (r *RepoManager) EnableTwoFactor(accountId, code string) ([]string, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.twoFactorMu.Lock()
+	defer r.twoFactorMu.Unlock()
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if user.TwoFactor == nil || user.TwoFactor.PendingSecret == "" {
+		return nil, fmt.Errorf("two-factor setup has not been started")
+	}
+
+	step, ok := totp.Validate(user.TwoFactor.PendingSecret, code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactor = &datatypes.TwoFactorData{
+		Enabled:            true,
+		Secret:             user.TwoFactor.PendingSecret,
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+		EnabledAt:          time.Now().UTC(),
+	}
+	if err := r.diskDataStorage.UpdateUser(*user); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor removes the user's TOTP enrollment and recovery codes.
+func (r *RepoManager) DisableTwoFactor(accountId string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	r.twoFactorMu.Lock()
+	defer r.twoFactorMu.Unlock()
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactor == nil {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	user.TwoFactor = nil
+	return r.diskDataStorage.UpdateUser(*user)
+}
+
+// RegenerateRecoveryCodes replaces all of the user's recovery codes.
+func (r *RepoManager) RegenerateRecoveryCodes(accountId string) ([]string, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	r.twoFactorMu.Lock()
+	defer r.twoFactorMu.Unlock()
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactor.RecoveryCodeHashes = hashes
+	if err := r.diskDataStorage.UpdateUser(*user); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactorCode checks a TOTP code for an enrolled user.
+// Each time step is accepted at most once; the check and the consume happen under
+// twoFactorMu, so two concurrent requests cannot both use the same code.
+func (r *RepoManager) VerifyTwoFactorCode(accountId, code string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	r.twoFactorMu.Lock()
+	defer r.twoFactorMu.Unlock()
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	step, ok := totp.Validate(user.TwoFactor.Secret, code, time.Now())
+	if !ok || step <= user.TwoFactor.LastUsedStep {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	user.TwoFactor.LastUsedStep = step
+	return r.diskDataStorage.UpdateUser(*user)
+}
+
+// UseRecoveryCode consumes one of the user's recovery codes, under twoFactorMu so a code
+// cannot be spent twice by concurrent requests.
+func (r *RepoManager) UseRecoveryCode(accountId, code string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	r.twoFactorMu.Lock()
+	defer r.twoFactorMu.Unlock()
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, stored := range user.TwoFactor.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(stored)) == 1 {
+			hashes := user.TwoFactor.RecoveryCodeHashes
+			user.TwoFactor.RecoveryCodeHashes = append(hashes[:i:i], hashes[i+1:]...)
+			return r.diskDataStorage.UpdateUser(*user)
+		}
+	}
+	return fmt.Errorf("invalid recovery code")
+}
+
+// IsTwoFactorRequired reports whether the repository policy requires TOTP for the user's role.
+func (r *RepoManager) IsTwoFactorRequired(user *datatypes.UserData) bool {
+	role := r.GetUserRole(user)
+	for _, required := range r.configs.RequireTwoFactorRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTwoFactorEnrollmentPending reports whether the account must enroll in TOTP
+// before it can use the API.
+func (r *RepoManager) IsTwoFactorEnrollmentPending(accountId string) bool {
+	if len(r.configs.RequireTwoFactorRoles) == 0 || !r.IsDataStorageInitialized() {
+		return false
+	}
+
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	if err != nil {
+		return false
+	}
+	return r.IsTwoFactorRequired(user) && !user.IsTwoFactorEnabled()
+}
+
+// CreateTwoFactorChallenge records a password-verified login and returns its challenge ID.
+func (r *RepoManager) CreateTwoFactorChallenge(user *datatypes.UserData) string {
+	r.challengesMu.Lock()
+	defer r.challengesMu.Unlock()
+
+	if r.twoFactorChallenges == nil {
+		r.twoFactorChallenges = make(map[string]*twoFactorChallenge)
+	}
+
+	// Drop expired challenges while we hold the lock
+	now := time.Now()
+	for id, challenge := range r.twoFactorChallenges {
+		if now.After(challenge.ExpiresAt) {
+			delete(r.twoFactorChallenges, id)
+		}
+	}
+
+	challengeID := uuid.NewString()
+	r.twoFactorChallenges[challengeID] = &twoFactorChallenge{
+		AccountID: user.AccountID,
+		Username:  user.Username,
+		ExpiresAt: now.Add(twoFactorChallengeTTL),
+	}
+	return challengeID
+}
+
+// GetTwoFactorChallenge returns the account ID and username of a pending challenge.
+func (r *RepoManager) GetTwoFactorChallenge(challengeID string) (string, string, error) {
+	r.challengesMu.Lock()
+	defer r.challengesMu.Unlock()
+
+	challenge, ok := r.twoFactorChallenges[challengeID]
+	if !ok || time.Now().After(challenge.ExpiresAt) {
+		delete(r.twoFactorChallenges, challengeID)
+		return "", "", fmt.Errorf("login challenge not found or expired")
+	}
+	return challenge.AccountID, challenge.Username, nil
+}
+
+// FailTwoFactorChallenge counts a wrong code and drops the challenge after too many.
+func (r *RepoManager) FailTwoFactorChallenge(challengeID string) {
+	r.challengesMu.Lock()
+	defer r.challengesMu.Unlock()
+
+	challenge, ok := r.twoFactorChallenges[challengeID]
+	if !ok {
+		return
+	}
+	challenge.Attempts++
+	if challenge.Attempts >= twoFactorChallengeAttempts {
+		delete(r.twoFactorChallenges, challengeID)
+	}
+}
+
+// DeleteTwoFactorChallenge removes a completed challenge.
+func (r *RepoManager) DeleteTwoFactorChallenge(challengeID string) {
+	r.challengesMu.Lock()
+	defer r.challengesMu.Unlock()
+	delete(r.twoFactorChallenges, challengeID)
+}
+
+// twoFactorIssuer is the issuer label shown in authenticator apps.
+func (r *RepoManager) twoFactorIssuer() string {
+	if name := strings.TrimSpace(r.configs.RepositoryName); name != "" {
+		return "OVA " + name
+	}
+	return "OVA"
+}
+
+// generateRecoveryCodes returns plain codes in the form "xxxxx-xxxxx" and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeCount; i++ {
+		var sb strings.Builder
+		for j := 0; j < 10; j++ {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			// rand.Int draws uniformly; a byte modulo 31 would favour the first letters
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not generate recovery code: %w", err)
+			}
+			sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+		}
+
+		code := sb.String()
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode makes recovery codes insensitive to case, spaces and dashes.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// hashRecoveryCode returns the hex SHA-256 stored for a recovery code.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
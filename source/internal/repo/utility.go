@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 
 	"ova-cli/source/internal/datatypes"
@@ -18,8 +19,8 @@ func (r *RepoManager) GenerateVideoID(absoluteVideoPath string) (string, error)
 }
 
 // GetVideoDuration returns the duration (in seconds) of the given video file.
-func (r *RepoManager) GetVideoDuration(videoPath string) (float64, error) {
-	duration, err := thirdparty.GetVideoDuration(videoPath)
+func (r *RepoManager) GetVideoDuration(ctx context.Context, videoPath string) (float64, error) {
+	duration, err := thirdparty.GetVideoDuration(ctx, videoPath)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get duration for %s: %w", videoPath, err)
 	}
@@ -27,9 +28,9 @@ func (r *RepoManager) GetVideoDuration(videoPath string) (float64, error) {
 }
 
 // GetVideoDuration returns the duration (in seconds) of the given video file.
-func (r *RepoManager) GetVideoCodect(videoPath string) (datatypes.VideoCodecs, error) {
+func (r *RepoManager) GetVideoCodect(ctx context.Context, videoPath string) (datatypes.VideoCodecs, error) {
 
-	codec, err := thirdparty.GetVideoDetails(videoPath)
+	codec, err := thirdparty.GetVideoDetails(ctx, videoPath)
 	if err != nil {
 		return datatypes.VideoCodecs{}, fmt.Errorf("failed to get codecs for file: %w", err)
 	}
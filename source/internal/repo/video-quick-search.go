@@ -6,11 +6,33 @@ import (
 )
 
 // QuickSearch fetches video titles based on a partial query.
-func (r *RepoManager) QuickSearch(query string) ([]datatypes.QuickSearchItemResult, error) {
+// Titles of videos the account may not see are left out.
+func (r *RepoManager) QuickSearch(accountId, query string) ([]datatypes.QuickSearchItemResult, error) {
 	if !r.IsDataStorageInitialized() {
 		return nil, fmt.Errorf("data storage is not initialized")
 	}
 
 	// Delegate the suggestion fetching to the appropriate data storage
-	return r.diskDataStorage.QuickSearch(query)
+	results, err := r.diskDataStorage.QuickSearch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	videos, err := r.diskDataStorage.GetAllVideos()
+	if err != nil {
+		return nil, err
+	}
+	visibleTitles := make(map[string]bool)
+	for _, video := range r.FilterVisibleVideos(accountId, videos) {
+		visibleTitles[video.Title] = true
+	}
+
+	filtered := make([]datatypes.QuickSearchItemResult, 0, len(results))
+	for _, result := range results {
+		if result.Type == "video" && !visibleTitles[result.Label] {
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered, nil
 }
@@ -5,10 +5,16 @@ import (
 	"ova-cli/source/internal/datatypes"
 )
 
-// GetSimilarVideos returns videos similar to the one identified by videoID.
-func (r *RepoManager) GetSimilarVideos(videoID string) ([]datatypes.VideoData, error) {
+// GetSimilarVideos returns the videos similar to the one identified by videoID
+// that the account may see.
+func (r *RepoManager) GetSimilarVideos(accountId, videoID string) ([]datatypes.VideoData, error) {
 	if !r.IsDataStorageInitialized() {
 		return nil, fmt.Errorf("data storage is not initialized")
 	}
-	return r.diskDataStorage.SimilarSearch(videoID)
+
+	similar, err := r.diskDataStorage.SimilarSearch(videoID)
+	if err != nil {
+		return nil, err
+	}
+	return r.FilterVisibleVideos(accountId, similar), nil
 }
@@ -13,9 +13,10 @@ func (r *RepoManager) SearchVideos(criteria datatypes.VideoSearchCriteria) ([]st
 	return r.diskDataStorage.SearchVideos(criteria)
 }
 
-// SearchVideosPaginated returns paginated video IDs based on search criteria.
+// SearchVideosPaginated returns paginated video IDs based on search criteria,
+// limited to the videos the account may see.
 // curent bucket start from 1
-func (r *RepoManager) SearchVideosPaginated(criteria datatypes.VideoSearchCriteria, page, limit int, sortMode SortMode) ([]datatypes.VideoData, int, error) {
+func (r *RepoManager) SearchVideosPaginated(accountId string, criteria datatypes.VideoSearchCriteria, page, limit int, sortMode SortMode) ([]datatypes.VideoData, int, error) {
 	if !r.IsDataStorageInitialized() {
 		return nil, 0, fmt.Errorf("%s", ErrDataStorageNotInitialized)
 	}
@@ -30,6 +31,7 @@ func (r *RepoManager) SearchVideosPaginated(criteria datatypes.VideoSearchCriter
 	if err != nil || videos == nil {
 		return nil, 0, fmt.Errorf("failed")
 	}
+	videos = r.FilterVisibleVideoRefs(accountId, videos)
 
 	sortedResult := make([]datatypes.VideoData, 0, len(videos))
 
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -33,20 +34,20 @@ func (r *RepoManager) AddVideo(video datatypes.VideoData) error {
 }
 
 // AddVideo adds a new video if it does not already exist.
-func (r *RepoManager) AddOneVideo(VideoPath, accountId string, cook bool) error {
+func (r *RepoManager) AddOneVideo(ctx context.Context, VideoPath, accountId string, cook bool) error {
 	if !r.IsDataStorageInitialized() {
 		return fmt.Errorf("data storage is not initialized")
 	}
 
 	// indexing video
-	_, err := r.IndexVideo(VideoPath, accountId)
+	_, err := r.IndexVideo(ctx, VideoPath, accountId)
 	if err != nil {
 		return fmt.Errorf("failed to index video with path %q: %w", VideoPath, err)
 	}
 
 	// optionality cook video if enabled
 	if cook {
-		if err := r.CookOneVideo(VideoPath); err != nil {
+		if err := r.CookOneVideo(ctx, VideoPath, CookingOptions{}, nil); err != nil {
 			return fmt.Errorf("failed to cook video with path %q: %w", VideoPath, err)
 		}
 	}
@@ -56,6 +57,7 @@ func (r *RepoManager) AddOneVideo(VideoPath, accountId string, cook bool) error
 }
 
 func (r *RepoManager) AddMultiVideos(
+	ctx context.Context,
 	VideoPaths []string,
 	accountId string,
 	indexingProgressChan chan int,
@@ -69,7 +71,7 @@ func (r *RepoManager) AddMultiVideos(
 	defer close(stateChan)
 
 	// Index all videos at once with progress and error tracking
-	_, err := r.IndexMultiVideos(VideoPaths, accountId, indexingProgressChan, indexingErrorChan)
+	_, err := r.IndexMultiVideos(ctx, VideoPaths, accountId, indexingProgressChan, indexingErrorChan)
 	if err != nil {
 		return err // The deferred close statements will handle channel cleanup
 	}
@@ -150,7 +152,8 @@ func (r *RepoManager) GetGlobalVideosInRange(start, end int) ([]string, error) {
 	return videoIds, nil
 }
 
-func (r *RepoManager) GetGlobalVideosPaginated(page int, sortMode SortMode, limit int) ([]datatypes.VideoData, int, error) {
+// GetGlobalVideosPaginated returns one page of the videos the account may see.
+func (r *RepoManager) GetGlobalVideosPaginated(accountId string, page int, sortMode SortMode, limit int) ([]datatypes.VideoData, int, error) {
 	if !r.IsDataStorageInitialized() {
 		return nil, 0, fmt.Errorf("data storage is not initialized")
 	}
@@ -159,6 +162,7 @@ func (r *RepoManager) GetGlobalVideosPaginated(page int, sortMode SortMode, limi
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get video data: %v", err)
 	}
+	videoData = r.FilterVisibleVideos(accountId, videoData)
 
 	if sortMode != "" {
 		SortVideos(videoData, sortMode)
@@ -224,11 +228,29 @@ func (r *RepoManager) GetAllTags() ([]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags: %v", err)
 	}
+	return collectTags(allVideos), nil
+}
+
+// GetVisibleTags returns the tags of the videos the account may see, so tags of
+// hidden videos are not revealed.
+func (r *RepoManager) GetVisibleTags(accountId string) ([]string, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	allVideos, err := r.diskDataStorage.GetAllVideos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+	return collectTags(r.FilterVisibleVideos(accountId, allVideos)), nil
+}
 
+// collectTags returns the distinct tags of the videos in order of first appearance.
+func collectTags(videos []datatypes.VideoData) []string {
 	tags := []string{}
 	seenTags := make(map[string]bool) // Use a map to track seen tags
 
-	for _, v := range allVideos {
+	for _, v := range videos {
 		for _, tag := range v.Tags {
 			if !seenTags[tag] { // Check if the tag has already been seen
 				tags = append(tags, tag)
@@ -237,5 +259,5 @@ func (r *RepoManager) GetAllTags() ([]string, error) {
 		}
 	}
 
-	return tags, nil
+	return tags
 }
@@ -1,11 +1,33 @@
 package repo
 
 import (
+	"context"
 	"fmt"
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/thirdparty"
 	"runtime"
 	"sync"
 )
 
+// VideoProgressFunc receives how far the work on one video is, from 0 to 100. media is the
+// running ffmpeg behind it, or nil between ffmpeg runs.
+type VideoProgressFunc func(percent int, media *datatypes.MediaProgress)
+
+func reportVideoProgress(progress VideoProgressFunc, percent int) {
+	if progress != nil {
+		progress(percent, nil)
+	}
+}
+
+func newMediaProgress(p thirdparty.FFmpegProgress) *datatypes.MediaProgress {
+	return &datatypes.MediaProgress{
+		OutTimeSec:  p.OutTime,
+		DurationSec: p.Duration,
+		Speed:       p.Speed,
+		FPS:         p.FPS,
+	}
+}
+
 func (r *RepoManager) GetTotalVideoCooked() int {
 
 	Videoes, err := r.GetAllIndexedVideos()
@@ -23,14 +45,98 @@ func (r *RepoManager) GetTotalVideoCooked() int {
 	return cooked
 }
 
-// IsVideoCooked checks if a video is already cooked by its ID.
+// IsVideoCooked checks if a video is already cooked by its ID: every artifact the default
+// cooking profile makes is on disk.
 func (r *RepoManager) IsVideoCooked(VideoID string) bool {
+	_, profile, err := r.GetCookingProfile("")
+	if err != nil {
+		profile = datatypes.DefaultCookingProfile()
+	}
+	for _, artifact := range profile.Artifacts {
+		if !r.artifactExists(VideoID, artifact) {
+			return false
+		}
+	}
+	return true
+}
+
+// artifactWeights is the share of a video's cooking time each artifact takes, for progress.
+var artifactWeights = map[string]int{
+	datatypes.ArtifactThumbnail:  5,
+	datatypes.ArtifactPreview:    15,
+	datatypes.ArtifactStoryboard: 80,
+}
+
+// planCooking resolves the cooking profile of the options for an indexed video and returns
+// the artifacts to make: the ones asked for that are missing or were made with other
+// settings, or all of them when forced.
+func (r *RepoManager) planCooking(videoId string, options CookingOptions) (string, datatypes.CookingProfile, []string, error) {
+	if _, err := r.CheckCookingOptions(options); err != nil {
+		return "", datatypes.CookingProfile{}, nil, err
+	}
+	name, profile, err := r.GetCookingProfile(options.Profile)
+	if err != nil {
+		return "", datatypes.CookingProfile{}, nil, err
+	}
+	video, err := r.diskDataStorage.GetVideoByID(videoId)
+	if err != nil {
+		return "", datatypes.CookingProfile{}, nil, err
+	}
+	return name, profile, r.cookingArtifacts(video, profile, options), nil
+}
+
+// cookArtifacts makes the given artifacts of a video with a cooking profile and records each
+// one as it is done, so an interrupted cook only redoes what it did not finish.
+func (r *RepoManager) cookArtifacts(ctx context.Context, videoPath, videoId, profileName string, profile datatypes.CookingProfile, artifacts []string, progress VideoProgressFunc) error {
+	total := 0
+	for _, artifact := range artifacts {
+		total += artifactWeights[artifact]
+	}
+	done := 0
+	for _, artifact := range artifacts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Scale the artifact's own progress into its share of the whole
+		base, weight := done, artifactWeights[artifact]
+		var onProgress VideoProgressFunc
+		if progress != nil && total > 0 {
+			onProgress = func(percent int, media *datatypes.MediaProgress) {
+				progress((base*100+weight*percent)/total, media)
+			}
+		}
+
+		var err error
+		switch artifact {
+		case datatypes.ArtifactThumbnail:
+			_, err = r.GenerateThumb(ctx, videoPath, videoId, profile.Thumbnail)
+		case datatypes.ArtifactPreview:
+			_, err = r.GeneratePreview(ctx, videoPath, videoId, profile.Preview, onProgress)
+		case datatypes.ArtifactStoryboard:
+			err = r.GenerateVideoPreviewThumbnails(ctx, videoPath, profile.Storyboard, onProgress)
+		default:
+			err = fmt.Errorf("unknown artifact %q", artifact)
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.recordArtifact(videoId, artifact, profileName, profile); err != nil {
+			return err
+		}
 
-	return r.CheckPreviewThumbnailGenerated(VideoID)
+		done += weight
+		if total > 0 {
+			reportVideoProgress(progress, done*100/total)
+		}
+	}
+	return nil
 }
 
-// CookVideo cooks a video by its ID.
-func (r *RepoManager) CookOneVideo(VideoPath string) error {
+// CookVideo cooks a video as the options say. Unless forced, only the artifacts the video is
+// missing or that were made with other settings are made. Cancelling ctx stops the running
+// ffmpeg/ffprobe call. progress, when not nil, receives live progress of the cooking.
+func (r *RepoManager) CookOneVideo(ctx context.Context, VideoPath string, options CookingOptions, progress VideoProgressFunc) error {
 
 	// Ensure the video has a valid ID before cooking
 	videoID, err := r.GenerateVideoID(VideoPath)
@@ -43,36 +149,51 @@ func (r *RepoManager) CookOneVideo(VideoPath string) error {
 		return fmt.Errorf("video with ID %s is not indexed", videoID)
 	}
 
-	if r.IsVideoCooked(videoID) {
-		return fmt.Errorf("video with ID %s is already cooked", videoID)
+	name, profile, artifacts, err := r.planCooking(videoID, options)
+	if err != nil {
+		return err
 	}
-
-	// Generate Preview Thumbnails
-	error := r.GenerateVideoPreviewThumbnails(VideoPath)
-	if error != nil {
-		return error
+	if len(artifacts) == 0 {
+		return fmt.Errorf("video with ID %s is already cooked with profile %q", videoID, name)
 	}
 
-	return nil
+	return r.cookArtifacts(ctx, VideoPath, videoID, name, profile, artifacts, progress)
 }
 
-func (r *RepoManager) CookMultiVideos(VideoPaths []string, progressChan chan int, errorChan chan error) error {
+// CookMultiVideos cooks the videos in parallel. progressChan receives the overall progress,
+// which moves while each video is cooked, not only when one finishes.
+func (r *RepoManager) CookMultiVideos(ctx context.Context, VideoPaths []string, progressChan chan int, errorChan chan error) error {
 	var wg sync.WaitGroup // WaitGroup to wait for all goroutines to finish
-	jobs := make(chan string)
+	jobs := make(chan int)
 	totalVideos := len(VideoPaths)
 
 	workerCount := runtime.NumCPU()
-	processed := make(chan struct{})
+	processed := make(chan int)
+
+	// Live progress of the videos in flight; stale reports are dropped rather than block ffmpeg
+	type videoProgress struct{ index, percent int }
+	updates := make(chan videoProgress, workerCount)
 
 	// Worker goroutine function that processes each video
 	worker := func() {
-		for path := range jobs {
-			if err := r.CookOneVideo(path); err != nil {
+		for i := range jobs {
+			// once ctx is done the remaining paths are only drained so progress still adds up
+			if err := ctx.Err(); err != nil {
+				processed <- i
+				continue
+			}
+			onProgress := func(percent int, _ *datatypes.MediaProgress) {
+				select {
+				case updates <- videoProgress{i, percent}:
+				default:
+				}
+			}
+			if err := r.CookOneVideo(ctx, VideoPaths[i], CookingOptions{}, onProgress); err != nil {
 				if errorChan != nil {
-					errorChan <- fmt.Errorf("failed processing %s: %v", path, err)
+					errorChan <- fmt.Errorf("failed processing %s: %v", VideoPaths[i], err)
 				}
 			}
-			processed <- struct{}{}
+			processed <- i
 		}
 		wg.Done()
 	}
@@ -89,18 +210,33 @@ func (r *RepoManager) CookMultiVideos(VideoPaths []string, progressChan chan int
 
 	// Send jobs
 	go func() {
-		for _, path := range VideoPaths {
-			jobs <- path
+		for i := range VideoPaths {
+			jobs <- i
 		}
 		close(jobs)
 	}()
 
 	// Track progress
-	for i := 0; i < totalVideos; i++ {
-		<-processed
+	percents := make([]int, totalVideos)
+	sent := 0
+	for done := 0; done < totalVideos; {
+		select {
+		case update := <-updates:
+			percents[update.index] = max(percents[update.index], update.percent)
+		case i := <-processed:
+			percents[i] = 100
+			done++
+		}
+
 		if progressChan != nil {
-			progress := int((float64(i+1) / float64(totalVideos)) * 100)
-			progressChan <- progress
+			sum := 0
+			for _, percent := range percents {
+				sum += percent
+			}
+			if progress := sum / totalVideos; progress > sent {
+				sent = progress
+				progressChan <- progress
+			}
 		}
 	}
 
@@ -108,5 +244,5 @@ func (r *RepoManager) CookMultiVideos(VideoPaths []string, progressChan chan int
 	wg.Wait()
 
 	// Do not close progressChan or errorChan here; let the caller close them if needed
-	return nil
+	return ctx.Err()
 }
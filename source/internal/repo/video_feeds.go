@@ -0,0 +1,164 @@
+package repo
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// Names of the global feeds, as used in the API and by `ovacli config feed`.
+const (
+	FeedMostViewed = "most-viewed"
+	FeedMostLiked  = "most-liked"
+	FeedTrending   = "trending"
+	FeedRandom     = "random"
+)
+
+// FeedNames lists the global feeds.
+var FeedNames = []string{FeedMostViewed, FeedMostLiked, FeedTrending, FeedRandom}
+
+// DefaultRandomPoolMinutes is how long a user's random order is kept when the config does not say.
+const DefaultRandomPoolMinutes = 10
+
+// randomPool is a shuffled list of the videos one account may see. Pages of the random feed
+// read from it so they do not overlap, and picks walk through it so they do not repeat.
+type randomPool struct {
+	videoIds []string
+	builtAt  time.Time
+	next     int // Next pick
+}
+
+// FeedConfig returns the switch of a feed from the repository config.
+func (r *RepoManager) FeedConfig(name string) (*datatypes.FeedConfig, error) {
+	feeds := &r.configs.Feeds
+	switch name {
+	case FeedMostViewed:
+		return &feeds.MostViewed, nil
+	case FeedMostLiked:
+		return &feeds.MostLiked, nil
+	case FeedTrending:
+		return &feeds.Trending, nil
+	case FeedRandom:
+		return &feeds.Random, nil
+	}
+	return nil, fmt.Errorf("unknown feed %q", name)
+}
+
+// IsFeedEnabled reports whether a feed is switched on in the repository config.
+func (r *RepoManager) IsFeedEnabled(name string) bool {
+	feed, err := r.FeedConfig(name)
+	return err == nil && feed.Enabled
+}
+
+// FeedWindowDays returns the configured default window of a feed, 0 when it has none.
+func (r *RepoManager) FeedWindowDays(name string) int {
+	feed, err := r.FeedConfig(name)
+	if err != nil {
+		return 0
+	}
+	return feed.WindowDays
+}
+
+// GetRandomVideos returns one page of the videos the account may see in a random order.
+// The order is kept for the configured pool time, so paging does not repeat videos.
+func (r *RepoManager) GetRandomVideos(accountId string, page, limit int) ([]datatypes.VideoData, int, error) {
+	if page < 1 || limit < 1 {
+		return nil, 0, fmt.Errorf("invalid page")
+	}
+
+	r.randomMu.Lock()
+	pool, err := r.randomPoolFor(accountId)
+	var pageIds []string
+	if err == nil {
+		start := min((page-1)*limit, len(pool.videoIds))
+		end := min(start+limit, len(pool.videoIds))
+		pageIds = append(pageIds, pool.videoIds[start:end]...)
+	}
+	r.randomMu.Unlock()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	videos := make([]datatypes.VideoData, 0, len(pageIds))
+	for _, videoId := range pageIds {
+		if video, err := r.diskDataStorage.GetVideoByID(videoId); err == nil {
+			videos = append(videos, *video)
+		}
+	}
+	// Visibility can change while a pool is cached, so recheck each page. A page may then come
+	// back short until the pool is rebuilt.
+	return r.FilterVisibleVideos(accountId, videos), len(pool.videoIds), nil
+}
+
+// PickRandomVideo returns a random video the account may see. Consecutive picks go through
+// the account's pool without repeating, and start over in a new order once it is used up.
+func (r *RepoManager) PickRandomVideo(accountId string) (*datatypes.VideoData, error) {
+	r.randomMu.Lock()
+	defer r.randomMu.Unlock()
+
+	pool, err := r.randomPoolFor(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Visibility can change while a pool is cached, so recheck each pick
+	for range len(pool.videoIds) {
+		if pool.next >= len(pool.videoIds) {
+			rand.Shuffle(len(pool.videoIds), func(i, j int) {
+				pool.videoIds[i], pool.videoIds[j] = pool.videoIds[j], pool.videoIds[i]
+			})
+			pool.next = 0
+		}
+		videoId := pool.videoIds[pool.next]
+		pool.next++
+
+		if video, err := r.GetVisibleVideoByID(accountId, videoId); err == nil {
+			return video, nil
+		}
+	}
+	return nil, fmt.Errorf("no videos to pick from")
+}
+
+// randomPoolFor returns the account's random pool, building a new one when it is missing or
+// older than the configured pool time. The caller must hold randomMu.
+func (r *RepoManager) randomPoolFor(accountId string) (*randomPool, error) {
+	minutes := r.configs.Feeds.RandomPoolMinutes
+	if minutes <= 0 {
+		minutes = DefaultRandomPoolMinutes
+	}
+
+	if pool, ok := r.randomPools[accountId]; ok && time.Since(pool.builtAt) < time.Duration(minutes)*time.Minute {
+		return pool, nil
+	}
+
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	videos, err := r.diskDataStorage.GetAllVideos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video data: %v", err)
+	}
+	videos = r.FilterVisibleVideos(accountId, videos)
+
+	pool := &randomPool{videoIds: make([]string, 0, len(videos)), builtAt: time.Now()}
+	for _, video := range videos {
+		pool.videoIds = append(pool.videoIds, video.VideoID)
+	}
+	rand.Shuffle(len(pool.videoIds), func(i, j int) {
+		pool.videoIds[i], pool.videoIds[j] = pool.videoIds[j], pool.videoIds[i]
+	})
+
+	// Drop pools that expired so accounts that stopped asking do not pile up
+	for id, old := range r.randomPools {
+		if time.Since(old.builtAt) >= time.Duration(minutes)*time.Minute {
+			delete(r.randomPools, id)
+		}
+	}
+	if r.randomPools == nil {
+		r.randomPools = map[string]*randomPool{}
+	}
+	r.randomPools[accountId] = pool
+	return pool, nil
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/utils"
@@ -9,7 +10,8 @@ import (
 )
 
 // IndexVideo handles hashing, thumbnail/preview generation, and metadata storage.
-func (r *RepoManager) IndexVideo(absolutePath, accountId string) (datatypes.VideoData, error) {
+// Cancelling ctx stops the running ffmpeg/ffprobe call.
+func (r *RepoManager) IndexVideo(ctx context.Context, absolutePath, accountId string) (datatypes.VideoData, error) {
 	if !r.IsDataStorageInitialized() {
 		return datatypes.VideoData{}, fmt.Errorf("data storage is not initialized")
 	}
@@ -44,20 +46,29 @@ func (r *RepoManager) IndexVideo(absolutePath, accountId string) (datatypes.Vide
 		return datatypes.VideoData{}, fmt.Errorf("video with ID %s is already indexed", videoID)
 	}
 
-	codec, err := r.GetVideoCodect(absolutePath)
+	codec, err := r.GetVideoCodect(ctx, absolutePath)
 	if err != nil {
 		return datatypes.VideoData{}, fmt.Errorf("failed to get codecs for file: %w", err)
 	}
 
-	// 6. Generate thumbnail and preview
-	_, err = r.GenerateThumb(absolutePath, videoID)
+	// 6. Generate thumbnail and preview with the default cooking profile
+	profileName, profile, err := r.GetCookingProfile("")
 	if err != nil {
-		return datatypes.VideoData{}, fmt.Errorf("failed to generate thumbnail: %w", err)
+		return datatypes.VideoData{}, err
+	}
+	var made []string
+	if profile.Produces(datatypes.ArtifactThumbnail) {
+		if _, err := r.GenerateThumb(ctx, absolutePath, videoID, profile.Thumbnail); err != nil {
+			return datatypes.VideoData{}, fmt.Errorf("failed to generate thumbnail: %w", err)
+		}
+		made = append(made, datatypes.ArtifactThumbnail)
 	}
 
-	_, err = r.GeneratePreview(absolutePath, videoID)
-	if err != nil {
-		return datatypes.VideoData{}, fmt.Errorf("failed to generate preview: %w", err)
+	if profile.Produces(datatypes.ArtifactPreview) {
+		if _, err := r.GeneratePreview(ctx, absolutePath, videoID, profile.Preview, nil); err != nil {
+			return datatypes.VideoData{}, fmt.Errorf("failed to generate preview: %w", err)
+		}
+		made = append(made, datatypes.ArtifactPreview)
 	}
 
 	title := strings.TrimSuffix(filepath.Base(absolutePath), filepath.Ext(absolutePath))
@@ -65,6 +76,10 @@ func (r *RepoManager) IndexVideo(absolutePath, accountId string) (datatypes.Vide
 	videoData := datatypes.NewVideoData(title, videoID)
 	videoData.Codecs = codec
 	videoData.UploaderID = accountId
+	for _, artifact := range made {
+		videoData.RecordArtifact(artifact, profileName, profile)
+	}
+	videoData.IsCooked = r.artifactExists(videoID, datatypes.ArtifactStoryboard)
 
 	// 8. Store metadata
 	if err := r.diskDataStorage.InsertVideo(videoData); err != nil {
@@ -74,7 +89,7 @@ func (r *RepoManager) IndexVideo(absolutePath, accountId string) (datatypes.Vide
 	return videoData, nil
 }
 
-func (r *RepoManager) IndexMultiVideos(absolutePaths []string, accountId string, progressChan chan int, errorChan chan error) ([]datatypes.VideoData, error) {
+func (r *RepoManager) IndexMultiVideos(ctx context.Context, absolutePaths []string, accountId string, progressChan chan int, errorChan chan error) ([]datatypes.VideoData, error) {
 
 	defer close(progressChan)
 	defer close(errorChan)
@@ -92,8 +107,12 @@ func (r *RepoManager) IndexMultiVideos(absolutePaths []string, accountId string,
 	}
 
 	for i, absPath := range absolutePaths {
+		if err := ctx.Err(); err != nil {
+			return indexedVideos, err
+		}
+
 		// Index the video
-		videoData, err := r.IndexVideo(absPath, accountId)
+		videoData, err := r.IndexVideo(ctx, absPath, accountId)
 		if err != nil {
 			if errorChan != nil {
 				errorChan <- fmt.Errorf("failed to index video %s: %w", absPath, err)
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetVideoLike likes or unlikes a video for an account and returns the video's number of likes.
+func (r *RepoManager) SetVideoLike(accountId, videoId string, liked bool) (int, error) {
+	if !r.IsDataStorageInitialized() {
+		return 0, fmt.Errorf("data storage is not initialized")
+	}
+	if _, err := r.diskDataStorage.GetVideoByID(videoId); err != nil {
+		return 0, err
+	}
+	likes, err := r.diskDataStorage.SetVideoLike(videoId, accountId, liked, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	if liked {
+		r.recordInteraction(accountId, videoId)
+	}
+	return likes, nil
+}
+
+// GetVideoLikes returns the number of likes of every liked video, and which of them the
+// account liked.
+func (r *RepoManager) GetVideoLikes(accountId string) (counts map[string]int, likedByAccount map[string]bool, err error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, nil, fmt.Errorf("data storage is not initialized")
+	}
+
+	likes, err := r.diskDataStorage.GetAllVideoLikes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts = make(map[string]int, len(likes))
+	likedByAccount = map[string]bool{}
+	for videoId, byAccount := range likes {
+		counts[videoId] = len(byAccount)
+		if _, ok := byAccount[accountId]; ok {
+			likedByAccount[videoId] = true
+		}
+	}
+	return counts, likedByAccount, nil
+}
@@ -1,14 +1,17 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/thirdparty"
 	"path/filepath"
 )
 
 // GeneratePreview generates a .webm preview clip from a video and returns the output path.
-func (r *RepoManager) GeneratePreview(videoPath, videoId string) (string, error) {
+// progress, when not nil, follows ffmpeg through the clip.
+func (r *RepoManager) GeneratePreview(ctx context.Context, videoPath, videoId string, settings datatypes.PreviewSettings, progress VideoProgressFunc) (string, error) {
 	// Get the output path for the preview using GetPreviewFilePathByVideoID
 	outputPath := r.GetPreviewFilePathByVideoID(videoId)
 
@@ -17,17 +20,25 @@ func (r *RepoManager) GeneratePreview(videoPath, videoId string) (string, error)
 		return "", fmt.Errorf("failed to create directory for preview: %w", err)
 	}
 
-	// 1. Extract video duration
-	duration, err := r.GetVideoDuration(videoPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get duration: %w", err)
+	// 1. Pick the start, the middle of the video unless the profile names one
+	startTime := settings.StartSec
+	if startTime <= 0 {
+		duration, err := r.GetVideoDuration(ctx, videoPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to get duration: %w", err)
+		}
+		startTime = duration / 2.0
 	}
 
-	// 2. Calculate center time for preview generation
-	centerTime := duration / 2.0
+	var onFFmpeg thirdparty.ProgressFunc
+	if progress != nil {
+		onFFmpeg = func(p thirdparty.FFmpegProgress) {
+			progress(int(p.Percent()), newMediaProgress(p))
+		}
+	}
 
-	// 3. Generate preview video
-	if err := thirdparty.GenerateWebMFromVideo(videoPath, outputPath, centerTime, 4.0); err != nil {
+	// 2. Generate preview video
+	if err := thirdparty.GenerateWebMFromVideo(ctx, videoPath, outputPath, startTime, settings.DurationSec, settings.Width, settings.Codec, settings.Bitrate, onFFmpeg); err != nil {
 		return "", fmt.Errorf("failed to generate preview for %s: %w", videoPath, err)
 	}
 
@@ -1,8 +1,10 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/thirdparty"
 	"path/filepath"
 )
@@ -19,8 +21,11 @@ func (r *RepoManager) CheckPreviewThumbnailGenerated(videoID string) bool {
 	return false
 }
 
-// GenerateVideoPreviewThumbnails generates sprite sheet thumbnails and VTT files for a single video.
-func (r *RepoManager) GenerateVideoPreviewThumbnails(videoPath string) error {
+// GenerateVideoPreviewThumbnails generates sprite sheet thumbnails and VTT files for a single video
+// with the storyboard settings of a cooking profile. The storyboard is built next to the
+// current one and swapped in when complete, so re-cooking never leaves a video without one.
+// progress, when not nil, follows the frame extraction live and then each remaining step.
+func (r *RepoManager) GenerateVideoPreviewThumbnails(ctx context.Context, videoPath string, settings datatypes.StoryboardSettings, progress VideoProgressFunc) error {
 	// Use existing method to generate unique video ID (content hash)
 	videoID, err := r.GenerateVideoID(videoPath)
 	if err != nil {
@@ -29,41 +34,49 @@ func (r *RepoManager) GenerateVideoPreviewThumbnails(videoPath string) error {
 
 	// Use GetStoryboardFolderPathByVideoID to get the folder path for the storyboard
 	videoSpriteDir := r.GetPreviewThumbnailsFolderPathByVideoID(videoID)
+	buildDir := videoSpriteDir + ".new"
 
-	// Create the storyboard directory if it doesn't exist
-	if err := os.MkdirAll(videoSpriteDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory for %s: %w", filepath.Base(videoPath), err)
+	// Start from an empty build directory; a cancelled cook may have left one behind
+	if err := os.RemoveAll(buildDir); err != nil {
+		return fmt.Errorf("failed to clear build dir for %s: %w", filepath.Base(videoPath), err)
 	}
+	defer os.RemoveAll(buildDir)
 
-	vttPath := filepath.Join(videoSpriteDir, "thumbnails.vtt")
-	if _, err := os.Stat(vttPath); err == nil {
-		return fmt.Errorf("thumbnails.vtt already exists, skipping %s", filepath.Base(videoPath))
-	}
-
-	keyframeDir := filepath.Join(videoSpriteDir, "keyframes")
+	keyframeDir := filepath.Join(buildDir, "keyframes")
 	if err := os.MkdirAll(keyframeDir, 0755); err != nil {
 		return fmt.Errorf("failed to create keyframe dir for %s: %w", filepath.Base(videoPath), err)
 	}
 
-	if err := thirdparty.ExtractKeyframes(videoPath, keyframeDir, 160, 90); err != nil {
+	// frame extraction reads the whole video and takes most of the time
+	var onFFmpeg thirdparty.ProgressFunc
+	if progress != nil {
+		onFFmpeg = func(p thirdparty.FFmpegProgress) {
+			progress(int(p.Percent()*0.8), newMediaProgress(p))
+		}
+	}
+	if err := thirdparty.ExtractKeyframes(ctx, videoPath, keyframeDir, settings.TileWidth, settings.TileHeight, settings.IntervalSec, onFFmpeg); err != nil {
 		return fmt.Errorf("keyframe extraction error for %s: %w", filepath.Base(videoPath), err)
 	}
+	reportVideoProgress(progress, 80)
 
-	spritePattern := filepath.Join(videoSpriteDir, "thumb_L0_%03d.jpg")
-	if err := thirdparty.GenerateSpriteSheetsFromFolder(keyframeDir, spritePattern, "5x5", 160, 90); err != nil {
+	tile := fmt.Sprintf("%dx%d", settings.Columns, settings.Rows)
+	spritePattern := filepath.Join(buildDir, "thumb_L0_%03d.jpg")
+	if err := thirdparty.GenerateSpriteSheetsFromFolder(keyframeDir, spritePattern, tile, settings.TileWidth, settings.TileHeight, settings.Quality); err != nil {
 		return fmt.Errorf("sprite generation error for %s: %w", filepath.Base(videoPath), err)
 	}
+	reportVideoProgress(progress, 90)
 
-	keyframeTimes, err := thirdparty.GetKeyframePacketTimestamps(videoPath)
+	frameTimes, err := thirdparty.StoryboardFrameTimes(ctx, videoPath, keyframeDir, settings.IntervalSec)
 	if err != nil {
 		return fmt.Errorf("failed to get keyframe timestamps for %s: %w", filepath.Base(videoPath), err)
 	}
-	if len(keyframeTimes) == 0 {
+	if len(frameTimes) == 0 {
 		return fmt.Errorf("no keyframes found for %s", filepath.Base(videoPath))
 	}
 
 	vttPattern := filepath.Join("/api/v1/preview-thumbnails", videoID, "thumb_L0_%03d.jpg")
-	if err := thirdparty.GenerateVTT(keyframeTimes, "5x5", 160, 90, vttPattern, vttPath, ""); err != nil {
+	vttPath := filepath.Join(buildDir, "thumbnails.vtt")
+	if err := thirdparty.GenerateVTT(frameTimes, tile, settings.TileWidth, settings.TileHeight, vttPattern, vttPath, ""); err != nil {
 		return fmt.Errorf("VTT generation error for %s: %w", filepath.Base(videoPath), err)
 	}
 
@@ -73,5 +86,13 @@ func (r *RepoManager) GenerateVideoPreviewThumbnails(videoPath string) error {
 		fmt.Printf("Warning: failed to delete keyframe dir for %s: %v\n", filepath.Base(videoPath), err)
 	}
 
+	// Swap the new storyboard in
+	if err := os.RemoveAll(videoSpriteDir); err != nil {
+		return fmt.Errorf("failed to remove old storyboard of %s: %w", filepath.Base(videoPath), err)
+	}
+	if err := os.Rename(buildDir, videoSpriteDir); err != nil {
+		return fmt.Errorf("failed to install storyboard of %s: %w", filepath.Base(videoPath), err)
+	}
+
 	return nil
 }
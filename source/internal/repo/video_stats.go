@@ -0,0 +1,263 @@
+package repo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/logs"
+)
+
+var statsLogger = logs.Loggers("Stats")
+
+// Defaults for the view counting settings in ConfigData.
+const (
+	DefaultViewMinWatchSec = 30
+	DefaultViewMinPercent  = 50
+	DefaultViewWindowHours = 6
+)
+
+// DefaultTrendingDays is the window of the trending feed, and TrendingHalfLifeDays how fast
+// activity in it loses weight.
+const (
+	DefaultTrendingDays  = 7
+	TrendingHalfLifeDays = 2.0
+)
+
+// RankedVideo is a video in a feed ordered by its statistics.
+type RankedVideo struct {
+	datatypes.VideoData
+	WindowViews     int     `json:"windowViews"`     // Views within the feed's window
+	WindowDownloads int     `json:"windowDownloads"` // Downloads within the feed's window
+	WindowLikes     int     `json:"windowLikes"`     // Likes given within the feed's window
+	Score           float64 `json:"score"`
+}
+
+// DailyVideoStat is one day of a video's statistics.
+type DailyVideoStat struct {
+	Date string `json:"date"` // YYYY-MM-DD, UTC
+	datatypes.VideoDailyStats
+}
+
+// viewCountingPolicy returns the view counting settings with defaults applied.
+func (r *RepoManager) viewCountingPolicy() (minSec, minPercent float64, window time.Duration) {
+	seconds, percent, hours := r.configs.ViewMinWatchSec, r.configs.ViewMinPercent, r.configs.ViewWindowHours
+	if seconds <= 0 {
+		seconds = DefaultViewMinWatchSec
+	}
+	if percent <= 0 {
+		percent = DefaultViewMinPercent
+	}
+	if hours <= 0 {
+		hours = DefaultViewWindowHours
+	}
+	return float64(seconds), float64(percent), time.Duration(hours) * time.Hour
+}
+
+// countPlaybackView counts a view once the user watched enough of the video in one sitting.
+// Each user adds at most one view per video within the configured window.
+func (r *RepoManager) countPlaybackView(accountId string, state *datatypes.PlaybackState) error {
+	minSec, minPercent, window := r.viewCountingPolicy()
+
+	enough := state.WatchedSec >= minSec
+	if state.DurationSec > 0 && state.WatchedSec/state.DurationSec*100 >= minPercent {
+		enough = true
+	}
+	if !enough {
+		return nil
+	}
+
+	_, err := r.diskDataStorage.CountVideoView(state.VideoID, "user:"+accountId, state.UpdatedAt, window)
+	return err
+}
+
+// countShareLinkView counts a view of a shared video. A link adds at most one view
+// within the configured window, however often it is opened.
+func (r *RepoManager) countShareLinkView(link *datatypes.ShareLinkData) error {
+	_, _, window := r.viewCountingPolicy()
+	_, err := r.diskDataStorage.CountVideoView(link.VideoID, "share:"+link.ID, time.Now().UTC(), window)
+	return err
+}
+
+// CountVideoDownload records a download of the video.
+func (r *RepoManager) CountVideoDownload(videoId string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+	return r.diskDataStorage.CountVideoDownload(videoId, time.Now().UTC())
+}
+
+// GetVideoDailyStats returns the views and downloads of a video for each of the last days,
+// oldest first and including today. Days without activity are included with zero counts.
+func (r *RepoManager) GetVideoDailyStats(videoId string, days int) ([]DailyVideoStat, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	if days < 1 {
+		return nil, fmt.Errorf("days must be at least 1")
+	}
+
+	today := time.Now().UTC()
+	first := today.AddDate(0, 0, -(days - 1))
+	daily, err := r.diskDataStorage.GetDailyVideoStats(first)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DailyVideoStat, 0, days)
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(datatypes.StatsDayLayout)
+		result = append(result, DailyVideoStat{Date: date, VideoDailyStats: daily[date][videoId]})
+	}
+	return result, nil
+}
+
+// videoActivity is what the feeds know about one video within their window.
+type videoActivity struct {
+	daily map[string]datatypes.VideoDailyStats // Keyed by YYYY-MM-DD
+	likes []time.Time                          // When the current likes were given
+}
+
+// GetMostViewedVideos returns one page of the videos the account may see, ordered by their
+// views in the last days. days <= 0 orders by all-time views.
+func (r *RepoManager) GetMostViewedVideos(accountId string, days, page, limit int) ([]RankedVideo, int, error) {
+	return r.rankVideos(accountId, days, page, limit, func(video datatypes.VideoData, activity videoActivity, _ time.Time) float64 {
+		if days <= 0 {
+			return float64(video.TotalViews)
+		}
+		views := 0
+		for _, counts := range activity.daily {
+			views += counts.Views
+		}
+		return float64(views)
+	})
+}
+
+// GetMostLikedVideos returns one page of the videos the account may see, ordered by the
+// likes they got in the last days. days <= 0 orders by all likes.
+func (r *RepoManager) GetMostLikedVideos(accountId string, days, page, limit int) ([]RankedVideo, int, error) {
+	return r.rankVideos(accountId, days, page, limit, func(_ datatypes.VideoData, activity videoActivity, _ time.Time) float64 {
+		return float64(len(activity.likes))
+	})
+}
+
+// GetTrendingVideos returns one page of the videos the account may see, ordered by their
+// recent activity. Views and downloads of the last days count once and likes twice, halving
+// in weight every TrendingHalfLifeDays. days <= 0 uses DefaultTrendingDays.
+func (r *RepoManager) GetTrendingVideos(accountId string, days, page, limit int) ([]RankedVideo, int, error) {
+	if days <= 0 {
+		days = DefaultTrendingDays
+	}
+	return r.rankVideos(accountId, days, page, limit, func(_ datatypes.VideoData, activity videoActivity, now time.Time) float64 {
+		decay := func(at time.Time) float64 {
+			return math.Pow(0.5, now.Sub(at).Hours()/24/TrendingHalfLifeDays)
+		}
+
+		score := 0.0
+		for date, counts := range activity.daily {
+			day, err := time.Parse(datatypes.StatsDayLayout, date)
+			if err != nil {
+				continue
+			}
+			score += float64(counts.Views+counts.Downloads) * decay(day)
+		}
+		for _, likedAt := range activity.likes {
+			score += 2 * decay(likedAt)
+		}
+		return math.Round(score*100) / 100
+	})
+}
+
+// rankVideos scores the visible videos with their activity of the last days, drops videos
+// that score 0 and returns one page ordered by score. days <= 0 uses all activity.
+// Pages start at 1.
+func (r *RepoManager) rankVideos(accountId string, days, page, limit int,
+	score func(video datatypes.VideoData, activity videoActivity, now time.Time) float64) ([]RankedVideo, int, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, 0, fmt.Errorf("data storage is not initialized")
+	}
+	if page < 1 || limit < 1 {
+		return nil, 0, fmt.Errorf("invalid page")
+	}
+
+	videos, err := r.diskDataStorage.GetAllVideos()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get video data: %v", err)
+	}
+	videos = r.FilterVisibleVideos(accountId, videos)
+
+	now := time.Now().UTC()
+	since := time.Time{}
+	if days > 0 {
+		// Whole days, so the window matches the daily statistics
+		since = now.AddDate(0, 0, -(days - 1)).Truncate(24 * time.Hour)
+	}
+	activities, err := r.collectVideoActivity(since)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ranked := []RankedVideo{}
+	for _, video := range videos {
+		activity := activities[video.VideoID]
+		item := RankedVideo{VideoData: video, Score: score(video, activity, now), WindowLikes: len(activity.likes)}
+		if item.Score <= 0 {
+			continue
+		}
+		for _, c := range activity.daily {
+			item.WindowViews += c.Views
+			item.WindowDownloads += c.Downloads
+		}
+		ranked = append(ranked, item)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Title < ranked[j].Title
+	})
+
+	start := (page - 1) * limit
+	if start >= len(ranked) {
+		return []RankedVideo{}, len(ranked), nil
+	}
+	end := min(start+limit, len(ranked))
+	return ranked[start:end], len(ranked), nil
+}
+
+// collectVideoActivity groups the daily statistics and likes from since on by video.
+func (r *RepoManager) collectVideoActivity(since time.Time) (map[string]videoActivity, error) {
+	daily, err := r.diskDataStorage.GetDailyVideoStats(since)
+	if err != nil {
+		return nil, err
+	}
+	likes, err := r.diskDataStorage.GetAllVideoLikes()
+	if err != nil {
+		return nil, err
+	}
+
+	activities := map[string]videoActivity{}
+	for date, counts := range daily {
+		for videoId, c := range counts {
+			activity := activities[videoId]
+			if activity.daily == nil {
+				activity.daily = map[string]datatypes.VideoDailyStats{}
+			}
+			activity.daily[date] = c
+			activities[videoId] = activity
+		}
+	}
+	for videoId, byAccount := range likes {
+		for _, likedAt := range byAccount {
+			if likedAt.Before(since) {
+				continue
+			}
+			activity := activities[videoId]
+			activity.likes = append(activity.likes, likedAt)
+			activities[videoId] = activity
+		}
+	}
+	return activities, nil
+}
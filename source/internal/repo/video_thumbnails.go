@@ -1,14 +1,16 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/thirdparty"
 	"path/filepath"
 )
 
 // GenerateThumb generates a thumbnail image from a video file and returns the path to the generated thumbnail.
-func (r *RepoManager) GenerateThumb(videoPath, videoId string) (string, error) {
+func (r *RepoManager) GenerateThumb(ctx context.Context, videoPath, videoId string, settings datatypes.ThumbnailSettings) (string, error) {
 	// Get the output path for the thumbnail using GetThumbnailFilePathByVideoID
 	outputPath := r.GetThumbnailFilePathByVideoID(videoId)
 
@@ -17,17 +19,18 @@ func (r *RepoManager) GenerateThumb(videoPath, videoId string) (string, error) {
 		return "", fmt.Errorf("failed to create directory for thumbnail: %w", err)
 	}
 
-	// 1. Extract video duration
-	duration, err := r.GetVideoDuration(videoPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get duration: %w", err)
+	// 1. Pick the frame, the middle of the video unless the profile names a time
+	frameTime := settings.AtSec
+	if frameTime <= 0 {
+		duration, err := r.GetVideoDuration(ctx, videoPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to get duration: %w", err)
+		}
+		frameTime = duration / 2.0
 	}
 
-	// 2. Calculate center time for thumbnail generation.
-	centerTime := duration / 2.0
-
-	// 3. Generate thumbnail image.
-	if err := thirdparty.GenerateImageFromVideo(videoPath, outputPath, centerTime); err != nil {
+	// 2. Generate thumbnail image.
+	if err := thirdparty.GenerateImageFromVideo(ctx, videoPath, outputPath, frameTime, settings.Width, settings.Quality); err != nil {
 		return "", fmt.Errorf("failed to generate thumbnail for %s: %w", videoPath, err)
 	}
 
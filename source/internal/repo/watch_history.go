@@ -0,0 +1,198 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// WatchHistoryItem is a history entry together with its video.
+type WatchHistoryItem struct {
+	datatypes.WatchEvent
+	Video *datatypes.VideoData `json:"video"`
+}
+
+// RecordWatch adds watchedSec of viewing to the user's history. Playback reported within
+// datatypes.WatchSessionGap of the last report extends the same entry. Nothing is recorded,
+// and nil is returned, while the user has paused their history.
+func (r *RepoManager) RecordWatch(accountId, videoId string, watchedSec float64) (*datatypes.WatchEvent, error) {
+	if !r.IsDataStorageInitialized() {
+		return nil, fmt.Errorf("data storage is not initialized")
+	}
+	if watchedSec < 0 {
+		watchedSec = 0
+	}
+
+	if user, err := r.diskDataStorage.GetUserByAccountID(accountId); err == nil && user.HistoryPaused {
+		return nil, nil
+	}
+	event, err := r.diskDataStorage.RecordWatchEvent(accountId, videoId, time.Now().UTC(), watchedSec)
+	if err != nil {
+		return nil, err
+	}
+	if event.StartedAt.Equal(event.LastSeenAt) {
+		r.recordInteraction(accountId, videoId) // A new sitting
+	}
+	return event, nil
+}
+
+// GetWatchHistory returns one page of the user's history within [since, until), newest first,
+// and the number of entries across all pages. Entries for videos the user can no longer see
+// are left out. Pages start at 1.
+func (r *RepoManager) GetWatchHistory(accountId string, since, until time.Time, page, pageSize int) ([]WatchHistoryItem, int, error) {
+	access, err := r.newVideoAccess(accountId)
+	if err != nil {
+		return nil, 0, err
+	}
+	if page < 1 || pageSize < 1 {
+		return nil, 0, fmt.Errorf("invalid page")
+	}
+
+	events, err := r.diskDataStorage.GetWatchEvents(accountId, since, until)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	visible := make([]WatchHistoryItem, 0, len(events))
+	for _, event := range events {
+		video, err := r.diskDataStorage.GetVideoByID(event.VideoID)
+		if err != nil || !access.canView(video) {
+			continue
+		}
+		visible = append(visible, WatchHistoryItem{WatchEvent: event, Video: video})
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(visible) {
+		return []WatchHistoryItem{}, len(visible), nil
+	}
+	end := min(start+pageSize, len(visible))
+	return visible[start:end], len(visible), nil
+}
+
+// RemoveWatchEvent deletes one entry from the user's history.
+func (r *RepoManager) RemoveWatchEvent(accountId, eventId string) error {
+	if !r.IsDataStorageInitialized() {
+		return fmt.Errorf("data storage is not initialized")
+	}
+
+	events, err := r.diskDataStorage.GetWatchEvents(accountId, time.Time{}, time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to get watch history: %w", err)
+	}
+	if err := r.diskDataStorage.DeleteWatchEvent(accountId, eventId); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if event.ID == eventId {
+			return r.forgetWatchedVideos(accountId, []string{event.VideoID})
+		}
+	}
+	return nil
+}
+
+// ClearWatchHistoryRange deletes the user's history entries that started within [since, until)
+// and returns how many were deleted. Zero bounds are open, and clearing everything also
+// empties the watched list.
+func (r *RepoManager) ClearWatchHistoryRange(accountId string, since, until time.Time) (int, error) {
+	if !r.IsDataStorageInitialized() {
+		return 0, fmt.Errorf("data storage is not initialized")
+	}
+
+	events, err := r.diskDataStorage.GetWatchEvents(accountId, since, until)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get watch history: %w", err)
+	}
+	removed, err := r.diskDataStorage.DeleteWatchEvents(accountId, since, until)
+	if err != nil {
+		return 0, err
+	}
+
+	if since.IsZero() && until.IsZero() {
+		if _, err := r.diskDataStorage.GetUserWatchedVideos(accountId); err != nil {
+			return removed, nil // No watched list to clear
+		}
+		return removed, r.diskDataStorage.ClearUserWatchedHistory(accountId)
+	}
+
+	videoIds := make([]string, 0, len(events))
+	for _, event := range events {
+		videoIds = append(videoIds, event.VideoID)
+	}
+	return removed, r.forgetWatchedVideos(accountId, videoIds)
+}
+
+// forgetWatchedVideos takes videos whose history entries were deleted off the watched list,
+// unless other entries still refer to them. Otherwise getRecentlyWatchedIDs would bring the
+// deleted entries back through the list.
+func (r *RepoManager) forgetWatchedVideos(accountId string, videoIds []string) error {
+	remaining, err := r.diskDataStorage.GetWatchEvents(accountId, time.Time{}, time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to get watch history: %w", err)
+	}
+	stillWatched := make(map[string]bool, len(remaining))
+	for _, event := range remaining {
+		stillWatched[event.VideoID] = true
+	}
+
+	forgotten := make([]string, 0, len(videoIds))
+	for _, videoId := range videoIds {
+		if !stillWatched[videoId] {
+			forgotten = append(forgotten, videoId)
+		}
+	}
+	if len(forgotten) == 0 {
+		return nil
+	}
+	if err := r.diskDataStorage.RemoveVideosFromWatched(accountId, forgotten); err != nil {
+		return fmt.Errorf("failed to update watched list: %w", err)
+	}
+	return nil
+}
+
+// SetHistoryPaused stops or resumes recording the user's watch history.
+func (r *RepoManager) SetHistoryPaused(accountId string, paused bool) (*datatypes.UserData, error) {
+	return r.updateUser(accountId, func(user *datatypes.UserData) error {
+		user.HistoryPaused = paused
+		return nil
+	})
+}
+
+// IsHistoryPaused reports whether the user has paused their watch history.
+func (r *RepoManager) IsHistoryPaused(accountId string) bool {
+	if !r.IsDataStorageInitialized() {
+		return false
+	}
+	user, err := r.diskDataStorage.GetUserByAccountID(accountId)
+	return err == nil && user.HistoryPaused
+}
+
+// getRecentlyWatchedIDs returns the videos in the user's history, most recently watched first
+// and each only once. Videos marked watched before the history existed follow, oldest last.
+func (r *RepoManager) getRecentlyWatchedIDs(accountId string) ([]string, error) {
+	events, err := r.diskDataStorage.GetWatchEvents(accountId, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watch history: %w", err)
+	}
+
+	seen := map[string]bool{}
+	videoIds := []string{}
+	for _, event := range events {
+		if !seen[event.VideoID] {
+			seen[event.VideoID] = true
+			videoIds = append(videoIds, event.VideoID)
+		}
+	}
+
+	// The watched list has no times, so it cannot be interleaved with the history
+	legacy, _ := r.diskDataStorage.GetUserWatchedVideos(accountId)
+	for i := len(legacy) - 1; i >= 0; i-- {
+		if !seen[legacy[i]] {
+			seen[legacy[i]] = true
+			videoIds = append(videoIds, legacy[i])
+		}
+	}
+	return videoIds, nil
+}
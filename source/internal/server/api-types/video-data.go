@@ -7,12 +7,16 @@ import (
 
 // Define a new struct for video status
 type UserVideoStatus struct {
-	IsWatched bool `json:"isWatched"`
-	IsSaved   bool `json:"isSaved"`
+	IsWatched         bool    `json:"isWatched"`
+	IsSaved           bool    `json:"isSaved"`
+	IsLiked           bool    `json:"isLiked"`
+	ResumePositionSec float64 `json:"resumePositionSec"` // 0 when there is nothing to resume
+	ProgressPercent   float64 `json:"progressPercent"`   // How far the user got last time
 }
 type VideoStats struct {
 	Views     int `json:"views"`
 	Downloads int `json:"downloads"`
+	Likes     int `json:"likes"`
 }
 
 type VideoDataAPIResponse struct {
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VideoVisibilityRequest is the body for changing who can see a video.
+type VideoVisibilityRequest struct {
+	IsPublic *bool `json:"isPublic"`
+}
+
+// requireVisibleVideo answers 404 and returns nil when the request's account may not
+// see the video. Anonymous requests have no account and only see public videos.
+func requireVisibleVideo(c *gin.Context, repoMgr *repo.RepoManager, videoId string) *datatypes.VideoData {
+	video, err := repoMgr.GetVisibleVideoByID(c.GetString("accountId"), videoId)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
+		return nil
+	}
+	return video
+}
+
+// setVideoVisibility handles PUT /videos/:videoId/visibility for the uploader and admins.
+func setVideoVisibility(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		videoId := c.Param("videoId")
+
+		var req VideoVisibilityRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.IsPublic == nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "isPublic is required")
+			return
+		}
+
+		video := requireVisibleVideo(c, repoMgr, videoId)
+		if video == nil {
+			return
+		}
+		if !repoMgr.CanManageVideo(c.GetString("accountId"), video) {
+			apitypes.RespondError(c, http.StatusForbidden, "Only the uploader or an admin can change visibility")
+			return
+		}
+
+		updated, err := repoMgr.SetVideoVisibility(videoId, *req.IsPublic)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to update video visibility")
+			return
+		}
+
+		recordAudit(c, repoMgr, datatypes.AuditVideoVisibility, []string{videoId}, gin.H{"isPublic": video.IsPublic}, gin.H{"isPublic": updated.IsPublic})
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"videoId": videoId, "isPublic": updated.IsPublic}, "Video visibility updated")
+	}
+}
@@ -0,0 +1,373 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// accessFixture is a repository with one video per visibility rule:
+//
+//	pubvid     public, in the open root space            seen by everyone
+//	privvid    private, uploaded by carol                seen by carol and admins
+//	studiovid  public, in the private space "studio"     seen by alice (member) and admins
+//
+// bob is a plain user with no rights on the hidden videos.
+type accessFixture struct {
+	router   *gin.Engine
+	repoMgr  *repo.RepoManager
+	sessions map[string]string // username -> session ID
+}
+
+var accessFixtureVideos = []struct {
+	id, path string
+	public   bool
+	uploader string
+	tags     []string
+}{
+	{"pubvid", "pub.mp4", true, "root", []string{"shared", "pub-tag"}},
+	{"privvid", "priv.mp4", false, "carol", []string{"shared", "priv-tag"}},
+	{"studiovid", "studio/clip.mp4", true, "root", []string{"shared", "studio-tag"}},
+}
+
+// accessFixtureVisible lists which fixture videos each user may see.
+var accessFixtureVisible = map[string][]string{
+	"root":  {"pubvid", "privvid", "studiovid"},
+	"alice": {"pubvid", "studiovid"},
+	"bob":   {"pubvid"},
+	"carol": {"pubvid", "privvid"},
+}
+
+func newAccessFixture(t *testing.T) *accessFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repoMgr := newTestRepo(t)
+	root := repoMgr.GetRootPath()
+	// Video paths are stored relative to the repository, like under `ovacli serve` run in it
+	t.Chdir(root)
+
+	f := &accessFixture{repoMgr: repoMgr, sessions: make(map[string]string)}
+	accounts := make(map[string]string)
+	for _, username := range []string{"root", "alice", "bob", "carol"} {
+		user, err := datatypes.NewUserData(username, username+"-password")
+		if err != nil {
+			t.Fatalf("new user %s: %v", username, err)
+		}
+		if username == "root" {
+			user.Role = datatypes.RoleAdmin
+		}
+		if err := repoMgr.CreateUser(&user); err != nil {
+			t.Fatalf("create user %s: %v", username, err)
+		}
+		accounts[username] = user.AccountID
+		f.sessions[username] = "session-" + username
+		repoMgr.AddSession(f.sessions[username], user.AccountID)
+	}
+
+	// Indexing needs ffprobe, so the videos and their lookup entries are stored directly
+	lookups := make(map[string]string)
+	for _, v := range accessFixtureVideos {
+		video := datatypes.NewVideoData(v.id, v.id)
+		video.IsPublic = v.public
+		video.UploaderID = accounts[v.uploader]
+		video.Tags = v.tags
+		if err := repoMgr.AddVideo(video); err != nil {
+			t.Fatalf("add video %s: %v", v.id, err)
+		}
+		lookups[v.id] = v.path
+
+		writeFixtureFile(t, filepath.Join(root, filepath.FromSlash(v.path)), "video "+v.id)
+		writeFixtureFile(t, repoMgr.GetThumbnailFilePathByVideoID(v.id), "thumbnail "+v.id)
+		writeFixtureFile(t, repoMgr.GetPreviewFilePathByVideoID(v.id), "preview "+v.id)
+	}
+	data, err := json.Marshal(lookups)
+	if err != nil {
+		t.Fatalf("encode lookups: %v", err)
+	}
+	writeFixtureFile(t, filepath.Join(repoMgr.GetStoragePath(), "lookup.json"), string(data))
+
+	if _, err := repoMgr.SetSpacePrivate("studio", true); err != nil {
+		t.Fatalf("make studio private: %v", err)
+	}
+	if _, err := repoMgr.AddSpaceMember("studio", accounts["alice"]); err != nil {
+		t.Fatalf("add alice to studio: %v", err)
+	}
+
+	f.router = gin.New()
+	v1 := f.router.Group("/api/v1")
+	v1.Use(AuthMiddleware(repoMgr, nil, nil))
+	RegisterLatestVideoRoute(v1, repoMgr)
+	RegisterGlobalFiltersRoute(v1, repoMgr)
+	RegisterSearchRoutes(v1, repoMgr)
+	RegisterVideoRoutes(v1, repoMgr)
+	RegisterStreamRoutes(v1, repoMgr)
+	RegisterDownloadRoutes(v1, repoMgr)
+	RegisterThumbnailRoutes(v1, repoMgr)
+	RegisterPreviewRoutes(v1, repoMgr)
+	RegisterVideoFeedRoutes(v1, repoMgr)
+	RegisterBatchRoutes(v1, repoMgr)
+	return f
+}
+
+func writeFixtureFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// get performs a request as username; an empty username sends no session.
+func (f *accessFixture) get(username, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if username != "" {
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: f.sessions[username]})
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+// getData performs a request as username and decodes the "data" field of a 200 response.
+func (f *accessFixture) getData(t *testing.T, username, target string, out interface{}) {
+	t.Helper()
+	w := f.get(username, target)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s as %s: got %d: %s", target, username, w.Code, w.Body.String())
+	}
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s as %s: %v", target, username, err)
+	}
+	if err := json.Unmarshal(body.Data, out); err != nil {
+		t.Fatalf("GET %s as %s: decode data: %v", target, username, err)
+	}
+}
+
+func videoIDs(videos []datatypes.VideoData) []string {
+	ids := make([]string, 0, len(videos))
+	for _, v := range videos {
+		ids = append(ids, v.VideoID)
+	}
+	return ids
+}
+
+func assertSameSet(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	got = append([]string(nil), got...)
+	want = append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", what, got, want)
+		}
+	}
+}
+
+func TestListingsHideInvisibleVideos(t *testing.T) {
+	f := newAccessFixture(t)
+
+	for username, visible := range accessFixtureVisible {
+		t.Run(username, func(t *testing.T) {
+			var global struct {
+				Videos []datatypes.VideoData `json:"videos"`
+			}
+			f.getData(t, username, "/api/v1/videos/global", &global)
+			assertSameSet(t, "global", videoIDs(global.Videos), visible)
+
+			var search struct {
+				Videos []datatypes.VideoData `json:"videos"`
+			}
+			f.getData(t, username, "/api/v1/search?tags=shared&page=1", &search)
+			assertSameSet(t, "search", videoIDs(search.Videos), visible)
+
+			var similar struct {
+				SimilarVideos []datatypes.VideoData `json:"similarVideos"`
+			}
+			f.getData(t, username, "/api/v1/videos/pubvid/similar", &similar)
+			assertSameSet(t, "similar", videoIDs(similar.SimilarVideos), without(visible, "pubvid"))
+
+			var filters struct {
+				Tags []string `json:"tags"`
+			}
+			f.getData(t, username, "/api/v1/videos/global/filters", &filters)
+			wantTags := []string{"shared"}
+			for _, v := range accessFixtureVideos {
+				if contains(visible, v.id) {
+					wantTags = append(wantTags, v.tags[1])
+				}
+			}
+			assertSameSet(t, "global filters", filters.Tags, wantTags)
+		})
+	}
+}
+
+func TestSingleVideoRoutesHideInvisibleVideos(t *testing.T) {
+	f := newAccessFixture(t)
+
+	routes := []struct {
+		name   string
+		target func(videoId string) string
+		body   func(videoId string) string // expected body of an allowed request, empty to skip
+	}{
+		{name: "video", target: func(id string) string { return "/api/v1/videos/" + id }},
+		{name: "similar", target: func(id string) string { return "/api/v1/videos/" + id + "/similar" }},
+		{
+			name:   "stream",
+			target: func(id string) string { return "/api/v1/stream/" + id },
+			body:   func(id string) string { return "video " + id },
+		},
+		{
+			name:   "download",
+			target: func(id string) string { return "/api/v1/download/" + id },
+			body:   func(id string) string { return "video " + id },
+		},
+		{
+			name:   "thumbnail",
+			target: func(id string) string { return "/api/v1/thumbnail/" + id },
+			body:   func(id string) string { return "thumbnail " + id },
+		},
+		{
+			name:   "preview",
+			target: func(id string) string { return "/api/v1/preview/" + id },
+			body:   func(id string) string { return "preview " + id },
+		},
+	}
+
+	for _, route := range routes {
+		t.Run(route.name, func(t *testing.T) {
+			for username, visible := range accessFixtureVisible {
+				for _, v := range accessFixtureVideos {
+					allowed := contains(visible, v.id)
+
+					w := f.get(username, route.target(v.id))
+					switch {
+					case allowed && w.Code != http.StatusOK:
+						t.Errorf("%s as %s: got %d, want 200: %s", v.id, username, w.Code, w.Body.String())
+					case allowed && route.body != nil && w.Body.String() != route.body(v.id):
+						t.Errorf("%s as %s: served %q, want %q", v.id, username, w.Body.String(), route.body(v.id))
+					case !allowed && w.Code != http.StatusNotFound:
+						t.Errorf("%s as %s: got %d, want 404", v.id, username, w.Code)
+					}
+				}
+			}
+
+			if w := f.get("", route.target("pubvid")); w.Code != http.StatusUnauthorized {
+				t.Errorf("pubvid without a session: got %d, want 401", w.Code)
+			}
+		})
+	}
+}
+
+func TestRandomFeedRechecksVisibility(t *testing.T) {
+	f := newAccessFixture(t)
+
+	var random struct {
+		Videos []datatypes.VideoData `json:"videos"`
+	}
+	f.getData(t, "alice", "/api/v1/videos/random", &random)
+	assertSameSet(t, "random as member", videoIDs(random.Videos), accessFixtureVisible["alice"])
+
+	// alice's shuffled pool is cached; leaving the space must still hide its video
+	alice, err := f.repoMgr.GetUserByUsername("alice")
+	if err != nil {
+		t.Fatalf("get alice: %v", err)
+	}
+	if _, err := f.repoMgr.RemoveSpaceMember("studio", alice.AccountID); err != nil {
+		t.Fatalf("remove alice from studio: %v", err)
+	}
+	f.getData(t, "alice", "/api/v1/videos/random", &random)
+	assertSameSet(t, "random after leaving the space", videoIDs(random.Videos), []string{"pubvid"})
+}
+
+func TestBatchTreatsHiddenVideosAsMissing(t *testing.T) {
+	f := newAccessFixture(t)
+	ids := []string{"pubvid", "privvid", "studiovid", "novid"}
+
+	for username, visible := range accessFixtureVisible {
+		w := f.post(username, "/api/v1/videos/batch", map[string][]string{"ids": ids}, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("batch as %s: got %d: %s", username, w.Code, w.Body.String())
+		}
+		var body struct {
+			Data []apitypes.VideoDataAPIResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("batch as %s: %v", username, err)
+		}
+		got := []string{}
+		for _, video := range body.Data {
+			got = append(got, video.VideoID)
+		}
+		assertSameSet(t, "batch as "+username, got, visible)
+	}
+}
+
+func without(list []string, value string) []string {
+	out := []string{}
+	for _, item := range list {
+		if item != value {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func TestPlaylistSummariesOnlyCountVisibleVideos(t *testing.T) {
+	f := newAccessFixture(t)
+	RegisterUserPlaylistRoutes(f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil)), f.repoMgr)
+
+	carol := f.accountID(t, "carol")
+	playlist, err := f.repoMgr.CreatePlaylist(carol, "mixed", "")
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	for _, videoID := range []string{"pubvid", "privvid"} {
+		if err := f.repoMgr.AddVideoToPlaylist(carol, playlist.ID, videoID); err != nil {
+			t.Fatalf("add %s: %v", videoID, err)
+		}
+	}
+	if _, err := f.repoMgr.SetPlaylistCover(context.Background(), carol, playlist.ID, "privvid", nil); err != nil {
+		t.Fatalf("set cover: %v", err)
+	}
+	if err := f.repoMgr.SetPlaylistPrivacy(carol, playlist.ID, datatypes.Public); err != nil {
+		t.Fatalf("make playlist public: %v", err)
+	}
+
+	for username, want := range map[string]PlaylistSummary{
+		"carol": {VideoCount: 2, CoverImage: "privvid"},
+		"bob":   {VideoCount: 1, CoverImage: ""},
+	} {
+		var listed struct {
+			Playlists []PlaylistSummary `json:"playlists"`
+		}
+		f.getData(t, username, "/api/v1/users/carol/playlists", &listed)
+		if len(listed.Playlists) != 1 {
+			t.Fatalf("%s: got %d playlists, want 1", username, len(listed.Playlists))
+		}
+		got := listed.Playlists[0]
+		if got.VideoCount != want.VideoCount || got.CoverImage != want.CoverImage {
+			t.Errorf("%s: got %d videos with cover %q, want %d with cover %q", username, got.VideoCount, got.CoverImage, want.VideoCount, want.CoverImage)
+		}
+	}
+}
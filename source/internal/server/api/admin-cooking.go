@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminCookRequest is the body for re-cooking a video. Every field is optional.
+type AdminCookRequest struct {
+	Profile  string   `json:"profile"`  // Cooking profile, empty for the default
+	Only     []string `json:"only"`     // Artifacts to cook, empty for all the profile makes
+	Force    bool     `json:"force"`    // Cook them even when they are up to date
+	Priority *int     `json:"priority"` // Defaults to high, ahead of bulk cooking
+}
+
+// RegisterAdminCookingRoutes sets up /admin/videos/:videoId for operators to inspect and redo
+// the cooked artifacts of a video.
+func RegisterAdminCookingRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	admin := rg.Group("/admin/videos/:videoId", SessionOnlyMiddleware(), AdminOnlyMiddleware(repoMgr))
+	{
+		admin.GET("/artifacts", getVideoArtifacts(repoMgr)) // GET /api/v1/admin/videos/:videoId/artifacts
+		admin.POST("/cook", cookVideo(repoMgr))             // POST /api/v1/admin/videos/:videoId/cook
+	}
+}
+
+// getVideoArtifacts lists the cook status of each artifact of a video.
+func getVideoArtifacts(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		videoId := c.Param("videoId")
+		if _, err := repoMgr.GetVideoByID(videoId); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Video not found")
+			return
+		}
+
+		artifacts, err := repoMgr.GetArtifactStatus(videoId)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to read artifacts: "+err.Error())
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"videoId":   videoId,
+			"cooked":    repoMgr.IsVideoCooked(videoId),
+			"artifacts": artifacts,
+			"profiles":  repoMgr.GetCookingProfileNames(),
+		}, "Artifacts retrieved successfully")
+	}
+}
+
+// cookVideo queues a cooking task for a video, for some or all of its artifacts.
+func cookVideo(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body AdminCookRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&body); err != nil {
+				apitypes.RespondError(c, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+
+		videoId := c.Param("videoId")
+		if _, err := repoMgr.GetVideoByID(videoId); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Video not found")
+			return
+		}
+
+		options := repo.CookingOptions{Profile: body.Profile, Only: body.Only, Force: body.Force}
+		if _, err := repoMgr.CheckCookingOptions(options); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		priority := datatypes.TaskPriorityHigh
+		if body.Priority != nil {
+			priority = *body.Priority
+		}
+
+		task, err := repoMgr.EnqueueVideoCooking(videoId, c.GetString("accountId"), priority, options)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to queue cooking: "+err.Error())
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditVideoCook, []string{videoId}, nil, body)
+		apitypes.RespondSuccess(c, http.StatusAccepted, task, "Cooking queued")
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AdminUserResponse is the admin view of an account; secrets are never included.
+type AdminUserResponse struct {
+	AccountID        string             `json:"accountId"`
+	Username         string             `json:"username"`
+	DisplayName      string             `json:"displayName"`
+	Role             datatypes.UserRole `json:"role"`
+	Disabled         bool               `json:"disabled"`
+	AuthProvider     string             `json:"authProvider,omitempty"`
+	TwoFactorEnabled bool               `json:"twoFactorEnabled"`
+	CreatedAt        time.Time          `json:"createdAt"`
+	LastLoginAt      time.Time          `json:"lastLoginAt,omitzero"`
+}
+
+// AdminCreateUserRequest is the body for creating an account.
+type AdminCreateUserRequest struct {
+	Username    string             `json:"username"`
+	Password    string             `json:"password"`
+	DisplayName string             `json:"displayName"`
+	Role        datatypes.UserRole `json:"role"` // Defaults to "user"
+}
+
+// AdminUpdateUserRequest changes only the fields that are present.
+type AdminUpdateUserRequest struct {
+	DisplayName *string             `json:"displayName"`
+	Role        *datatypes.UserRole `json:"role"`
+	Disabled    *bool               `json:"disabled"`
+}
+
+// AdminResetPasswordRequest is the body for resetting a user's password.
+type AdminResetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// RegisterAdminUserRoutes sets up /admin/users for managing accounts while the server runs.
+func RegisterAdminUserRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	admin := rg.Group("/admin/users", SessionOnlyMiddleware(), AdminOnlyMiddleware(repoMgr))
+	{
+		admin.GET("", adminListUsers(repoMgr))                          // GET /api/v1/admin/users
+		admin.POST("", adminCreateUser(repoMgr))                        // POST /api/v1/admin/users
+		admin.GET("/:accountId", adminGetUser(repoMgr))                 // GET /api/v1/admin/users/:accountId
+		admin.PATCH("/:accountId", adminUpdateUser(repoMgr))            // PATCH /api/v1/admin/users/:accountId
+		admin.POST("/:accountId/password", adminResetPassword(repoMgr)) // POST /api/v1/admin/users/:accountId/password
+	}
+}
+
+func adminListUsers(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := repoMgr.GetAllUsers()
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to load users")
+			return
+		}
+
+		sort.Slice(users, func(i, j int) bool {
+			return strings.ToLower(users[i].Username) < strings.ToLower(users[j].Username)
+		})
+
+		response := make([]AdminUserResponse, 0, len(users))
+		for i := range users {
+			response = append(response, newAdminUserResponse(repoMgr, &users[i]))
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, response, "Users retrieved successfully")
+	}
+}
+
+func adminCreateUser(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AdminCreateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+			return
+		}
+
+		req.Username = strings.TrimSpace(req.Username)
+		if req.Username == "" || req.Password == "" {
+			apitypes.RespondError(c, http.StatusBadRequest, "Username and password are required")
+			return
+		}
+		if req.Role == "" {
+			req.Role = datatypes.RoleUser
+		}
+		if !datatypes.IsValidUserRole(req.Role) {
+			apitypes.RespondError(c, http.StatusBadRequest, "Unknown role")
+			return
+		}
+
+		userdata, err := datatypes.NewUserData(req.Username, req.Password)
+		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			apitypes.RespondError(c, http.StatusBadRequest, "Password must be at most 72 bytes")
+			return
+		}
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		userdata.DisplayName = strings.TrimSpace(req.DisplayName)
+		userdata.Role = req.Role
+
+		if err := repoMgr.CreateUser(&userdata); err != nil {
+			apitypes.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		created := newAdminUserResponse(repoMgr, &userdata)
+		recordAudit(c, repoMgr, datatypes.AuditUserCreate, []string{userdata.AccountID}, nil, created)
+		apitypes.RespondSuccess(c, http.StatusCreated, created, "User created")
+	}
+}
+
+func adminGetUser(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := repoMgr.GetUserByAccountID(c.Param("accountId"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "User not found")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, newAdminUserResponse(repoMgr, user), "User retrieved successfully")
+	}
+}
+
+func adminUpdateUser(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID := c.Param("accountId")
+
+		var req AdminUpdateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+			return
+		}
+
+		existing, err := repoMgr.GetUserByAccountID(targetID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "User not found")
+			return
+		}
+		before := newAdminUserResponse(repoMgr, existing)
+
+		// Admins cannot lock themselves out
+		actorID, _ := c.Get("accountId")
+		if actorID == targetID {
+			if (req.Disabled != nil && *req.Disabled) || (req.Role != nil && *req.Role != datatypes.RoleAdmin) {
+				apitypes.RespondError(c, http.StatusBadRequest, "You cannot disable or demote your own account")
+				return
+			}
+		}
+
+		if req.DisplayName == nil && req.Role == nil && req.Disabled == nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Nothing to update")
+			return
+		}
+
+		// Everything is checked before anything is saved, so a rejected field changes nothing
+		user, err := repoMgr.UpdateUserAccount(targetID, repo.UserAccountUpdate{
+			DisplayName: req.DisplayName,
+			Role:        req.Role,
+			Disabled:    req.Disabled,
+		})
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		after := newAdminUserResponse(repoMgr, user)
+		recordAudit(c, repoMgr, datatypes.AuditUserUpdate, []string{targetID}, before, after)
+		apitypes.RespondSuccess(c, http.StatusOK, after, "User updated")
+	}
+}
+
+func adminResetPassword(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AdminResetPasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+			return
+		}
+
+		if err := repoMgr.ResetUserPassword(c.Param("accountId"), req.Password); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditUserPassword, []string{c.Param("accountId")}, nil, nil)
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"accountId": c.Param("accountId")}, "Password reset, existing sessions were signed out")
+	}
+}
+
+func newAdminUserResponse(repoMgr *repo.RepoManager, user *datatypes.UserData) AdminUserResponse {
+	return AdminUserResponse{
+		AccountID:        user.AccountID,
+		Username:         user.Username,
+		DisplayName:      user.DisplayName,
+		Role:             repoMgr.GetUserRole(user),
+		Disabled:         user.Disabled,
+		AuthProvider:     user.AuthProvider,
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
+		CreatedAt:        user.CreatedAt,
+		LastLoginAt:      user.LastLoginAt,
+	}
+}
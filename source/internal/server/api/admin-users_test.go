@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func (f *accessFixture) patch(username, target string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPatch, target, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: f.sessions[username]})
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminUpdateUserIsAllOrNothing(t *testing.T) {
+	f := newAccessFixture(t)
+	RegisterAdminUserRoutes(f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil)), f.repoMgr)
+	bob := f.accountID(t, "bob")
+	target := "/api/v1/admin/users/" + bob
+
+	// The bad role is only the second field, the display name must not be saved either
+	w := f.patch("root", target, map[string]interface{}{"displayName": "Robert", "role": "overlord", "disabled": true})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("update with a bad role: got %d, want 400: %s", w.Code, w.Body.String())
+	}
+	user, err := f.repoMgr.GetUserByAccountID(bob)
+	if err != nil {
+		t.Fatalf("get bob: %v", err)
+	}
+	if user.DisplayName == "Robert" || user.Disabled {
+		t.Errorf("rejected update was partly saved: name %q, disabled %v", user.DisplayName, user.Disabled)
+	}
+	if w := f.get("bob", "/api/v1/admin/users"); w.Code != http.StatusForbidden {
+		t.Fatalf("bob lists users: got %d, want 403", w.Code)
+	}
+
+	w = f.patch("root", target, map[string]interface{}{"displayName": " Robert ", "disabled": true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: got %d: %s", w.Code, w.Body.String())
+	}
+	var updated AdminUserResponse
+	f.getData(t, "root", target, &updated)
+	if updated.DisplayName != "Robert" || !updated.Disabled {
+		t.Errorf("got name %q, disabled %v, want Robert, disabled", updated.DisplayName, updated.Disabled)
+	}
+	if w := f.get("bob", "/api/v1/videos/pubvid"); w.Code != http.StatusUnauthorized {
+		t.Errorf("disabled bob's session still works: got %d", w.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAuditQueryLimit caps /admin/audit responses when no limit is given.
+const defaultAuditQueryLimit = 200
+
+// RegisterAdminAuditRoutes sets up /admin/audit for querying the audit log.
+func RegisterAdminAuditRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	admin := rg.Group("/admin/audit", SessionOnlyMiddleware(), AdminOnlyMiddleware(repoMgr))
+	{
+		admin.GET("", adminQueryAudit(repoMgr)) // GET /api/v1/admin/audit?since=&until=&actor=&action=&limit=
+	}
+}
+
+func adminQueryAudit(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := datatypes.AuditFilter{
+			ActorID: c.Query("actor"),
+			Action:  c.Query("action"),
+			Limit:   defaultAuditQueryLimit,
+		}
+
+		var err error
+		if filter.Since, err = datatypes.ParseAuditTime(c.Query("since")); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid 'since', use RFC 3339")
+			return
+		}
+		if filter.Until, err = datatypes.ParseAuditTime(c.Query("until")); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid 'until', use RFC 3339")
+			return
+		}
+		if limit := c.Query("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n < 0 {
+				apitypes.RespondError(c, http.StatusBadRequest, "Invalid 'limit'")
+				return
+			}
+			filter.Limit = n
+		}
+
+		// Accept a username as well as an account ID for the actor
+		if filter.ActorID != "" {
+			if user, err := repoMgr.GetUserByUsername(filter.ActorID); err == nil {
+				filter.ActorID = user.AccountID
+			}
+		}
+
+		events, err := repoMgr.QueryAuditEvents(filter)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to query audit log")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, events, "Audit events retrieved successfully")
+	}
+}
+
+// recordAudit logs an action performed by the request's account. Failures are
+// not fatal to the request, since the action itself has already happened.
+func recordAudit(c *gin.Context, repoMgr *repo.RepoManager, action string, targetIds []string, before, after any) {
+	recordAuditAs(c, repoMgr, c.GetString("accountId"), action, targetIds, before, after)
+}
+
+// recordAuditAs logs an action for an explicit actor, for routes such as login
+// that run before the request is bound to an account.
+func recordAuditAs(c *gin.Context, repoMgr *repo.RepoManager, accountId, action string, targetIds []string, before, after any) {
+	if err := repoMgr.RecordAudit(accountId, action, c.ClientIP(), targetIds, before, after); err != nil {
+		c.Error(err)
+	}
+}
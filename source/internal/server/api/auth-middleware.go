@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 	"strings"
@@ -31,6 +32,36 @@ func AuthMiddleware(repoMgr *repo.RepoManager, publicPaths map[string]bool, publ
 			}
 		}
 
+		// Stream URLs of exported playlists carry a grant for their one video
+		if grant := c.Query("grant"); grant != "" && isStreamRoute(c) {
+			token, err := repoMgr.VerifyStreamGrant(c.Param("videoId"), grant)
+			if err != nil {
+				apitypes.RespondError(c, http.StatusUnauthorized, "Invalid or expired stream grant")
+				c.Abort()
+				return
+			}
+
+			c.Set("accountId", token.AccountID)
+			c.Set("apiToken", token)
+			c.Next()
+			return
+		}
+
+		// Personal API tokens take precedence over the session cookie
+		if bearer, ok := bearerToken(c); ok {
+			token, err := repoMgr.AuthenticateAPIToken(bearer)
+			if err != nil {
+				apitypes.RespondError(c, http.StatusUnauthorized, "Invalid or expired API token")
+				c.Abort()
+				return
+			}
+
+			c.Set("accountId", token.AccountID)
+			c.Set("apiToken", token)
+			c.Next()
+			return
+		}
+
 		sessionID, err := c.Cookie("session_id")
 		if err != nil {
 			apitypes.RespondError(c, http.StatusUnauthorized, "Authentication required")
@@ -49,3 +80,114 @@ func AuthMiddleware(repoMgr *repo.RepoManager, publicPaths map[string]bool, publ
 		c.Next()
 	}
 }
+
+// TokenScopeMiddleware enforces API token scopes on a route group.
+// GET and HEAD requests need readScope, every other method needs writeScope.
+// Session-authenticated requests are not restricted.
+func TokenScopeMiddleware(readScope, writeScope datatypes.TokenScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := apiTokenFromContext(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		required := writeScope
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			required = readScope
+		}
+
+		if !token.HasScope(required) {
+			apitypes.RespondError(c, http.StatusForbidden, "API token is missing the '"+string(required)+"' scope")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SessionOnlyMiddleware rejects requests authenticated with an API token,
+// keeping account and credential management behind an interactive login.
+func SessionOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := apiTokenFromContext(c); ok {
+			apitypes.RespondError(c, http.StatusForbidden, "This endpoint is not available to API tokens")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// streamRoutes are the routes external players may authenticate on through the URL.
+var streamRoutes = map[string]bool{
+	"/api/v1/stream/:videoId": true,
+}
+
+// isStreamRoute reports whether the request is a GET or HEAD on one of streamRoutes.
+func isStreamRoute(c *gin.Context) bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	return streamRoutes[c.FullPath()]
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+// External players that cannot send headers may pass an API token as ?access_token=
+// on the stream routes only, so the token cannot be used from a URL anywhere else.
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		if isStreamRoute(c) {
+			if token := strings.TrimSpace(c.Query("access_token")); strings.HasPrefix(token, datatypes.APITokenPrefix) {
+				return token, true
+			}
+		}
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// apiTokenFromContext returns the API token the request was authenticated with, if any.
+func apiTokenFromContext(c *gin.Context) (*datatypes.APITokenData, bool) {
+	value, exists := c.Get("apiToken")
+	if !exists {
+		return nil, false
+	}
+	token, ok := value.(*datatypes.APITokenData)
+	return token, ok
+}
+
+// TwoFactorPolicyMiddleware blocks accounts that the repository policy requires to use
+// TOTP until they have enrolled. The /auth routes stay reachable so they can do so.
+func TwoFactorPolicyMiddleware(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if exists && repoMgr.IsTwoFactorEnrollmentPending(accountID.(string)) {
+			apitypes.RespondError(c, http.StatusForbidden, "Two-factor authentication must be enabled for your account")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminOnlyMiddleware restricts a route group to accounts with the admin role.
+func AdminOnlyMiddleware(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !repoMgr.AuthEnabled {
+			c.Next()
+			return
+		}
+
+		accountID, exists := c.Get("accountId")
+		if !exists || !repoMgr.IsAdmin(accountID.(string)) {
+			apitypes.RespondError(c, http.StatusForbidden, "Admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"path"
+
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authProvidersHandler lists the configured providers so the login page can offer them.
+func authProvidersHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	providers, err := repoMgr.ListAuthProviders()
+	if err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	apitypes.RespondSuccess(c, http.StatusOK, providers, "Auth providers retrieved")
+}
+
+// oidcStateCookie carries the OIDC state in the browser that started the login, so a
+// callback with a state issued to someone else is refused (login CSRF).
+const oidcStateCookie = "oidc_state"
+
+// oidcLoginHandler redirects the browser to the identity provider.
+func oidcLoginHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	authURL, state, err := repoMgr.BeginRedirectLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Lax, not Strict: the callback is a top-level redirect from the identity provider
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     path.Dir(c.Request.URL.Path), // only sent to this provider's callback
+		MaxAge:   int(repo.OIDCStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// oidcCallbackHandler finishes the authorization code flow, issues the session and sends
+// the browser back to the frontend. Users with TOTP are sent to the second login step instead.
+func oidcCallbackHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	if errCode := c.Query("error"); errCode != "" {
+		apitypes.RespondError(c, http.StatusUnauthorized, "Identity provider returned: "+errCode)
+		return
+	}
+
+	state := c.Query("state")
+	cookieState, _ := c.Cookie(oidcStateCookie)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     path.Dir(c.Request.URL.Path),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+	if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		apitypes.RespondError(c, http.StatusUnauthorized, "Login was not started from this browser")
+		return
+	}
+
+	user, err := repoMgr.CompleteRedirectLogin(c.Request.Context(), c.Param("provider"), state, c.Query("code"))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if user.IsTwoFactorEnabled() {
+		challengeID := repoMgr.CreateTwoFactorChallenge(user)
+		c.Redirect(http.StatusFound, "/login?challengeId="+url.QueryEscape(challengeID))
+		return
+	}
+
+	issueSessionCookie(c, repoMgr, user, c.ClientIP())
+	c.Redirect(http.StatusFound, "/")
+}
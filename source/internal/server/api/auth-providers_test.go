@@ -0,0 +1,318 @@
+package api
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockIssuer is a minimal OpenID Connect issuer: discovery, JWKS and a token endpoint
+// that answers one authorization code with an RS256 ID token.
+type mockIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	code      string                       // the only code the token endpoint accepts
+	nonce     string                       // nonce put into the next ID token
+	claims    func(map[string]interface{}) // lets a test tamper with the ID token claims
+	signer    *rsa.PrivateKey              // key that signs the ID token, key unless a test swaps it
+	discovery int                          // number of discovery requests served
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	m := &mockIssuer{t: t, key: key, signer: key, code: "good-code"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		m.discovery++
+		writeJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != m.code ||
+			r.PostForm.Get("client_id") != "ova" || r.PostForm.Get("client_secret") != "s3cret" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]string{"access_token": "at", "id_token": m.idToken()})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockIssuer) idToken() string {
+	claims := map[string]interface{}{
+		"iss":                m.server.URL,
+		"sub":                "u-42",
+		"aud":                []string{"ova"},
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              m.nonce,
+		"preferred_username": "alice",
+		"name":               "Alice Example",
+	}
+	if m.claims != nil {
+		m.claims(claims)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.signer, crypto.SHA256, digest[:])
+	if err != nil {
+		m.t.Fatalf("sign id_token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// newTestRepo creates an empty repository in a temporary folder.
+func newTestRepo(t *testing.T) *repo.RepoManager {
+	t.Helper()
+
+	repoMgr, err := repo.NewRepoManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("create repo: %v", err)
+	}
+	return repoMgr
+}
+
+// newOIDCTestRouter serves the auth routes of a repository whose only provider is the mock issuer.
+func newOIDCTestRouter(t *testing.T, issuer *mockIssuer) (*gin.Engine, *repo.RepoManager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repoMgr := newTestRepo(t)
+	repoMgr.GetConfigs().AuthProviders = []datatypes.AuthProviderConfig{{
+		Name:         "corp",
+		Type:         datatypes.AuthProviderOIDC,
+		IssuerURL:    issuer.server.URL,
+		ClientID:     "ova",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://ova.test/api/v1/auth/oidc/corp/callback",
+	}}
+
+	router := gin.New()
+	RegisterAuthRoutes(router.Group("/api/v1"), repoMgr)
+	return router, repoMgr
+}
+
+// startOIDCLogin follows /login and returns the state cookie the browser received.
+func startOIDCLogin(t *testing.T, router *gin.Engine, issuer *mockIssuer) *http.Cookie {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/corp/login", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("login: got %d, want 302: %s", w.Code, w.Body.String())
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("login redirect: %v", err)
+	}
+	if got := location.Scheme + "://" + location.Host + location.Path; got != issuer.server.URL+"/authorize" {
+		t.Fatalf("login redirects to %s, want the issuer's authorization endpoint", got)
+	}
+	query := location.Query()
+	if query.Get("client_id") != "ova" || query.Get("response_type") != "code" || query.Get("nonce") == "" {
+		t.Fatalf("unexpected authorization request: %s", location.RawQuery)
+	}
+	issuer.nonce = query.Get("nonce")
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == oidcStateCookie {
+			if cookie.Value != query.Get("state") {
+				t.Fatalf("state cookie %q does not match state %q", cookie.Value, query.Get("state"))
+			}
+			if !cookie.HttpOnly || cookie.Path != "/api/v1/auth/oidc/corp" {
+				t.Fatalf("state cookie must be HttpOnly and scoped to the provider, got %+v", cookie)
+			}
+			return cookie
+		}
+	}
+	t.Fatalf("login did not set the %s cookie", oidcStateCookie)
+	return nil
+}
+
+// finishOIDCLogin calls the callback as the identity provider's redirect would.
+func finishOIDCLogin(router *gin.Engine, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/corp/callback?code=good-code&state="+url.QueryEscape(state), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func hasSessionCookie(w *httptest.ResponseRecorder) bool {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_id" && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOIDCLoginProvisionsUser(t *testing.T) {
+	issuer := newMockIssuer(t)
+	router, repoMgr := newOIDCTestRouter(t, issuer)
+
+	cookie := startOIDCLogin(t, router, issuer)
+	w := finishOIDCLogin(router, cookie.Value, cookie)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/" {
+		t.Fatalf("callback: got %d to %q, want 302 to /: %s", w.Code, w.Header().Get("Location"), w.Body.String())
+	}
+	if !hasSessionCookie(w) {
+		t.Fatal("callback did not issue a session cookie")
+	}
+
+	user, err := repoMgr.GetUserByUsername("alice")
+	if err != nil {
+		t.Fatalf("user was not provisioned: %v", err)
+	}
+	if user.AuthProvider != "corp" || user.ExternalID != "u-42" || user.DisplayName != "Alice Example" {
+		t.Fatalf("provisioned user has provider %q, external id %q, name %q", user.AuthProvider, user.ExternalID, user.DisplayName)
+	}
+	if user.GetRole() != datatypes.RoleUser {
+		t.Fatalf("provisioned user has role %q, want %q", user.GetRole(), datatypes.RoleUser)
+	}
+
+	// A second login finds the same account instead of creating another one
+	cookie = startOIDCLogin(t, router, issuer)
+	if w := finishOIDCLogin(router, cookie.Value, cookie); w.Code != http.StatusFound {
+		t.Fatalf("second callback: got %d: %s", w.Code, w.Body.String())
+	}
+	users, err := repoMgr.GetAllUsers()
+	if err != nil {
+		t.Fatalf("list users: %v", err)
+	}
+	count := 0
+	for _, u := range users {
+		if u.ExternalID == "u-42" {
+			count++
+			if u.AccountID != user.AccountID {
+				t.Fatalf("second login provisioned account %s, want %s", u.AccountID, user.AccountID)
+			}
+		}
+	}
+	if count != 1 {
+		t.Fatalf("found %d accounts for the subject, want 1", count)
+	}
+	if issuer.discovery != 1 {
+		t.Fatalf("discovery fetched %d times, want it cached after the first", issuer.discovery)
+	}
+}
+
+func TestOIDCCallbackRequiresStateCookie(t *testing.T) {
+	issuer := newMockIssuer(t)
+	router, repoMgr := newOIDCTestRouter(t, issuer)
+
+	victim := startOIDCLogin(t, router, issuer)
+	attacker := startOIDCLogin(t, router, issuer)
+
+	cases := []struct {
+		name   string
+		state  string
+		cookie *http.Cookie
+	}{
+		{"no cookie", attacker.Value, nil},
+		{"cookie of another login", attacker.Value, victim},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := finishOIDCLogin(router, tc.state, tc.cookie)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("got %d, want 401: %s", w.Code, w.Body.String())
+			}
+			if hasSessionCookie(w) {
+				t.Fatal("a session was issued")
+			}
+		})
+	}
+
+	if _, err := repoMgr.GetUserByUsername("alice"); err == nil {
+		t.Fatal("a user was provisioned without a matching state cookie")
+	}
+}
+
+func TestOIDCCallbackRejectsInvalidIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		claims func(map[string]interface{})
+		signer *rsa.PrivateKey
+	}{
+		{name: "wrong signing key", signer: otherKey},
+		{name: "wrong issuer", claims: func(c map[string]interface{}) { c["iss"] = "https://evil.example" }},
+		{name: "wrong audience", claims: func(c map[string]interface{}) { c["aud"] = "someone-else" }},
+		{name: "expired", claims: func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "wrong nonce", claims: func(c map[string]interface{}) { c["nonce"] = "replayed" }},
+		{name: "no subject", claims: func(c map[string]interface{}) { delete(c, "sub") }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.claims = tc.claims
+			if tc.signer != nil {
+				issuer.signer = tc.signer
+			}
+			router, repoMgr := newOIDCTestRouter(t, issuer)
+
+			cookie := startOIDCLogin(t, router, issuer)
+			w := finishOIDCLogin(router, cookie.Value, cookie)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("got %d, want 401: %s", w.Code, w.Body.String())
+			}
+			if _, err := repoMgr.GetUserByUsername("alice"); err == nil {
+				t.Fatal("a user was provisioned from an invalid id_token")
+			}
+		})
+	}
+}
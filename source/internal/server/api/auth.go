@@ -1,9 +1,13 @@
 package api
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 
@@ -16,6 +20,7 @@ import (
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Provider string `json:"provider,omitempty"` // Optional, limits the check to one auth provider
 }
 
 // LoginResponse represents the login response body.
@@ -33,9 +38,18 @@ func RegisterAuthRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
 	auth := rg.Group("/auth")
 	{
 		auth.POST("/login", func(c *gin.Context) { loginHandler(c, repoMgr) })
+		auth.POST("/login/2fa", func(c *gin.Context) { loginTwoFactorHandler(c, repoMgr) })
+		auth.GET("/providers", func(c *gin.Context) { authProvidersHandler(c, repoMgr) })
+		auth.GET("/oidc/:provider/login", func(c *gin.Context) { oidcLoginHandler(c, repoMgr) })
+		auth.GET("/oidc/:provider/callback", func(c *gin.Context) { oidcCallbackHandler(c, repoMgr) })
 		auth.POST("/logout", func(c *gin.Context) { logoutHandler(c, repoMgr) })
 		auth.GET("/status", func(c *gin.Context) { authStatusHandler(c, repoMgr) })
 		auth.POST("/password", func(c *gin.Context) { passwordHandler(c, repoMgr) })
+		auth.GET("/2fa", func(c *gin.Context) { twoFactorStatusHandler(c, repoMgr) })
+		auth.POST("/2fa/setup", func(c *gin.Context) { twoFactorSetupHandler(c, repoMgr) })
+		auth.POST("/2fa/enable", func(c *gin.Context) { twoFactorEnableHandler(c, repoMgr) })
+		auth.POST("/2fa/disable", func(c *gin.Context) { twoFactorDisableHandler(c, repoMgr) })
+		auth.POST("/2fa/recovery-codes", func(c *gin.Context) { twoFactorRecoveryCodesHandler(c, repoMgr) })
 	}
 }
 
@@ -47,16 +61,57 @@ func loginHandler(c *gin.Context, repoMgr *repo.RepoManager) {
 		return
 	}
 
-	user, err := repoMgr.GetUserByUsername(req.Username)
+	clientIP := c.ClientIP()
+
+	// Refuse throttled usernames and IPs before spending time on bcrypt
+	wait, err := repoMgr.BeginLoginAttempt(req.Username, clientIP)
 	if err != nil {
-		apitypes.RespondError(c, http.StatusUnauthorized, "Invalid username or password")
+		apitypes.RespondError(c, http.StatusInternalServerError, "Failed to check login attempts")
+		return
+	}
+	if wait > 0 {
+		respondLoginThrottled(c, wait)
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		apitypes.RespondError(c, http.StatusUnauthorized, "Invalid username or password")
+	user, err := repoMgr.AuthenticatePassword(req.Username, req.Password, req.Provider)
+	if err != nil {
+		respondLoginFailure(c, repoMgr, req.Username, clientIP, err.Error())
 		return
 	}
+	repoMgr.RefundLoginAttempt(clientIP)
+
+	// Hold the session back until the second factor is verified
+	if user.IsTwoFactorEnabled() {
+		challengeID := repoMgr.CreateTwoFactorChallenge(user)
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"twoFactorRequired": true,
+			"challengeId":       challengeID,
+		}, "Two-factor code required")
+		return
+	}
+
+	startSession(c, repoMgr, user, clientIP)
+}
+
+// startSession completes a login: it clears failed-attempt counters, issues the session cookie
+// and tells the client whether the repository policy still expects TOTP enrollment.
+func startSession(c *gin.Context, repoMgr *repo.RepoManager, user *datatypes.UserData, clientIP string) {
+	issueSessionCookie(c, repoMgr, user, clientIP)
+
+	// Respond with a success message, without exposing the session ID
+	if repoMgr.IsTwoFactorRequired(user) && !user.IsTwoFactorEnabled() {
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"twoFactorEnrollmentRequired": true}, "Login successful, two-factor enrollment required")
+		return
+	}
+	apitypes.RespondSuccess(c, http.StatusOK, nil, "Login successful")
+}
+
+// issueSessionCookie clears failed-attempt counters, creates a session and sets its cookie.
+func issueSessionCookie(c *gin.Context, repoMgr *repo.RepoManager, user *datatypes.UserData, clientIP string) {
+	repoMgr.RegisterLoginSuccess(user.Username)
+	repoMgr.UpdateUserLastLogin(user.AccountID)
+	recordAuditAs(c, repoMgr, user.AccountID, datatypes.AuditLogin, []string{user.AccountID}, nil, nil)
 
 	// Generate a new session ID
 	sessionID := uuid.NewString()
@@ -71,9 +126,30 @@ func loginHandler(c *gin.Context, repoMgr *repo.RepoManager) {
 		HttpOnly: true,                          // Ensure the cookie is only accessible via HTTP (not JavaScript)
 		Secure:   false,                         // Use true if you're using HTTPS
 	})
+}
 
-	// Respond with a success message, without exposing the session ID
-	apitypes.RespondSuccess(c, http.StatusOK, nil, "Login successful")
+// respondLoginFailure records a failed attempt and answers with 401, or 429 once
+// the attempt pushed the username or IP into backoff.
+func respondLoginFailure(c *gin.Context, repoMgr *repo.RepoManager, username, clientIP, reason string) {
+	wait, err := repoMgr.RegisterLoginFailure(username, clientIP, reason)
+	if err := repoMgr.RecordFailedLoginAudit(username, reason, clientIP); err != nil {
+		c.Error(err)
+	}
+	if err == nil && wait > 0 {
+		respondLoginThrottled(c, wait)
+		return
+	}
+	apitypes.RespondError(c, http.StatusUnauthorized, "Invalid username or password")
+}
+
+// respondLoginThrottled answers with 429 and a Retry-After header in whole seconds.
+func respondLoginThrottled(c *gin.Context, wait time.Duration) {
+	respondThrottled(c, wait, "Too many failed login attempts, try again later")
+}
+
+func respondThrottled(c *gin.Context, wait time.Duration, message string) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	apitypes.RespondError(c, http.StatusTooManyRequests, message)
 }
 
 func logoutHandler(c *gin.Context, repoMgr *repo.RepoManager) {
@@ -90,6 +166,7 @@ func logoutHandler(c *gin.Context, repoMgr *repo.RepoManager) {
 	}
 
 	repoMgr.DeleteSession(sessionID)
+	recordAuditAs(c, repoMgr, accountId, datatypes.AuditLogout, []string{accountId}, nil, nil)
 
 	clearCookie := "session_id=; Path=/; Max-Age=0; HttpOnly; SameSite=None;"
 	c.Writer.Header().Add("Set-Cookie", clearCookie)
@@ -146,18 +223,27 @@ func passwordHandler(c *gin.Context, repoMgr *repo.RepoManager) {
 		return
 	}
 
-	user, err := repoMgr.GetUserByUsername(accountId)
+	user, err := repoMgr.GetUserByAccountID(accountId)
 	if err != nil {
 		apitypes.RespondError(c, http.StatusUnauthorized, "Invalid username")
 		return
 	}
 
+	if user.AuthProvider != "" {
+		apitypes.RespondError(c, http.StatusForbidden, "Password is managed by the '"+user.AuthProvider+"' provider")
+		return
+	}
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
 		apitypes.RespondError(c, http.StatusUnauthorized, "Invalid password")
 		return
 	}
 
 	hashBytes, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		apitypes.RespondError(c, http.StatusBadRequest, "Password must be at most 72 bytes")
+		return
+	}
 	if err != nil {
 		apitypes.RespondError(c, http.StatusInternalServerError, "Cannot create hash from password")
 		return
@@ -167,6 +253,7 @@ func passwordHandler(c *gin.Context, repoMgr *repo.RepoManager) {
 	if err := repoMgr.UpdateUserPassword(accountId, hashedPassword); err != nil {
 		apitypes.RespondError(c, http.StatusForbidden, err.Error())
 	} else {
+		recordAuditAs(c, repoMgr, accountId, datatypes.AuditPasswordChange, []string{accountId}, nil, nil)
 		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"status": "ok"}, "Password changed!")
 	}
 }
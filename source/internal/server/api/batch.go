@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 
@@ -35,16 +36,36 @@ func getVideosByIds(repoMgr *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		var matched []apitypes.VideoDataAPIResponse
+		// Playback and watched state of the caller, looked up once for all videos
+		playback, err := repoMgr.GetPlaybackStatesByVideo(accountID.(string))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve playback state")
+			return
+		}
+		watched, _ := repoMgr.GetUserWatchedVideos(accountID.(string))
+		likes, likedByCaller, err := repoMgr.GetVideoLikes(accountID.(string))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve likes")
+			return
+		}
+
+		currentuser, err := repoMgr.GetUserByAccountID(accountID.(string))
+		if err != nil || currentuser == nil {
+			apitypes.RespondError(c, http.StatusUnauthorized, "User not found")
+			return
+		}
+
+		// Missing and hidden videos are both left out, so the answer does not tell which
+		// hidden IDs exist
+		found := make([]*datatypes.VideoData, 0, len(body.IDs))
 		for _, id := range body.IDs {
-			// Get the video by ID
-			video, err := repoMgr.GetVideoByID(id)
-			if err != nil || video == nil {
-				// Handle error retrieving video
-				apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
-				return
+			if video, err := repoMgr.GetVideoByID(id); err == nil && video != nil {
+				found = append(found, video)
 			}
+		}
 
+		var matched []apitypes.VideoDataAPIResponse
+		for _, video := range repoMgr.FilterVisibleVideoRefs(accountID.(string), found) {
 			// Get user data for the video owner
 			userdata, err := repoMgr.GetUserByAccountID(video.UploaderID)
 			if err != nil || userdata == nil {
@@ -52,24 +73,22 @@ func getVideosByIds(repoMgr *repo.RepoManager) gin.HandlerFunc {
 				continue
 			}
 
-			// Get user data for the video owner
-			currentuser, err := repoMgr.GetUserByAccountID(accountID.(string))
-			if err != nil || currentuser == nil {
-				// Skip if user data is not found
-				continue
-			}
-
 			isSaved := contains(currentuser.Favorites, video.VideoID)
 
 			// Define the video user status based on user data
+			state := playback[video.VideoID]
 			video_user_status := apitypes.UserVideoStatus{
-				IsWatched: false,
-				IsSaved:   isSaved,
+				IsWatched:         state.Completed || contains(watched, video.VideoID),
+				IsSaved:           isSaved,
+				IsLiked:           likedByCaller[video.VideoID],
+				ResumePositionSec: state.ResumePositionSec(),
+				ProgressPercent:   state.Percent,
 			}
 
 			video_stats := apitypes.VideoStats{
 				Views:     video.TotalViews,
 				Downloads: video.TotalDownloads,
+				Likes:     likes[video.VideoID],
 			}
 
 			// Create the video response
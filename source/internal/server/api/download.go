@@ -2,12 +2,12 @@ package api
 
 import (
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"strconv"
+	"strings"
 
+	"ova-cli/source/internal/logs"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 	"ova-cli/source/internal/thirdparty"
@@ -15,6 +15,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+var downloadLogger = logs.Loggers("Download")
+
 // RegisterDownloadRoutes registers download endpoints using RepoManager
 func RegisterDownloadRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
 	rg.GET("/download/:videoId", downloadVideo(rm))
@@ -25,13 +27,16 @@ func downloadVideo(rm *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoId := c.Param("videoId")
 
-		video, err := rm.GetVideoByID(videoId)
-		if err != nil {
-			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
+		video := requireVisibleVideo(c, rm, videoId)
+		if video == nil {
 			return
 		}
 
-		videoPath := video.Title
+		videoPath, err := rm.GetVideoPathByID(videoId)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, ErrVideoFileNotFound)
+			return
+		}
 		info, err := os.Stat(videoPath)
 		if os.IsNotExist(err) {
 			apitypes.RespondError(c, http.StatusNotFound, ErrVideoFileNotFound)
@@ -46,6 +51,8 @@ func downloadVideo(rm *repo.RepoManager) gin.HandlerFunc {
 		c.Header("Content-Type", "application/octet-stream")
 		c.Header("Content-Length", fmt.Sprintf("%d", info.Size()))
 
+		countDownload(c, rm, videoId)
+
 		// Serve file using Gin helper
 		c.File(videoPath)
 	}
@@ -75,13 +82,16 @@ func downloadTrimmedVideo(rm *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		video, err := rm.GetVideoByID(videoId)
-		if err != nil {
-			apitypes.RespondError(c, http.StatusNotFound, "Video not found")
+		video := requireVisibleVideo(c, rm, videoId)
+		if video == nil {
 			return
 		}
 
-		videoPath := video.Title
+		videoPath, err := rm.GetVideoPathByID(videoId)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, ErrVideoFileNotFound)
+			return
+		}
 		if _, err := os.Stat(videoPath); os.IsNotExist(err) {
 			apitypes.RespondError(c, http.StatusNotFound, "Video file not found on disk")
 			return
@@ -90,94 +100,61 @@ func downloadTrimmedVideo(rm *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		ffmpegPath, err := thirdparty.GetFFmpegPath()
-		if err != nil {
-			apitypes.RespondError(c, http.StatusInternalServerError, "FFmpeg not found")
-			return
-		}
-
-		args := []string{
-			"-ss", fmt.Sprintf("%.2f", start),
-			"-i", videoPath,
-			"-t", fmt.Sprintf("%.2f", duration),
-			"-c:v", "libx264",
-			"-c:a", "aac",
-			"-preset", "fast",
-			"-movflags", "frag_keyframe+empty_moov",
-			"-f", "mp4",
-			"pipe:1",
-		}
-
-		fmt.Println("Running FFmpeg:", ffmpegPath, args)
-
-		cmd := exec.Command(ffmpegPath, args...)
+		countDownload(c, rm, videoId)
 
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to create output pipe")
-			return
-		}
-
-		stderr, err := cmd.StderrPipe()
-		if err != nil {
-			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to create stderr pipe")
-			return
-		}
+		filename := fmt.Sprintf("%s_trimmed.mp4", video.Title)
+		streamTrimmedVideo(c, videoPath, start, duration, filename)
+	}
+}
 
-		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s_trimmed.mp4\"", video.Title))
-		c.Header("Content-Type", "video/mp4")
+// streamTrimmedVideo re-encodes a time range of a video with ffmpeg and streams it
+// to the client as a fragmented MP4. ffmpeg is killed when the client disconnects.
+// An empty attachmentName plays the clip inline instead of downloading it.
+func streamTrimmedVideo(c *gin.Context, videoPath string, start, duration float64, attachmentName string) {
+	out := &trimmedVideoWriter{c: c, attachmentName: attachmentName}
+	err := thirdparty.TrimVideoTo(c.Request.Context(), out, videoPath, start, duration)
+	switch {
+	case err == nil:
+	case c.Request.Context().Err() != nil:
+		downloadLogger.Info("Client left while trimming %s", videoPath)
+	case !out.started:
+		downloadLogger.Error("Failed to trim %s: %v", videoPath, err)
+		apitypes.RespondError(c, http.StatusInternalServerError, "Failed to trim video")
+	default:
+		downloadLogger.Error("Trimming %s failed while streaming it: %v", videoPath, err)
+	}
+}
 
-		if err := cmd.Start(); err != nil {
-			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to start ffmpeg")
-			return
-		}
+// trimmedVideoWriter streams ffmpeg's output to the client as it comes. The headers go out
+// with the first bytes, so ffmpeg failing before it makes any can still be answered with an error.
+type trimmedVideoWriter struct {
+	c              *gin.Context
+	attachmentName string
+	started        bool
+}
 
-		go func() {
-			errOutput, _ := io.ReadAll(stderr)
-			if len(errOutput) > 0 {
-				fmt.Println("FFmpeg stderr:\n", string(errOutput))
-			}
-		}()
-
-		clientGone := c.Request.Context().Done()
-		go func() {
-			<-clientGone
-			fmt.Println("Client disconnected, killing ffmpeg")
-			if cmd.Process != nil {
-				_ = cmd.Process.Kill()
-			}
-		}()
-
-		flusher, ok := c.Writer.(http.Flusher)
-		if !ok {
-			fmt.Println("ResponseWriter does not implement http.Flusher")
+func (w *trimmedVideoWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		if w.attachmentName != "" {
+			w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", w.attachmentName))
 		}
+		w.c.Header("Content-Type", "video/mp4")
+		w.started = true
+	}
 
-		buf := make([]byte, 32*1024)
-		for {
-			n, err := stdout.Read(buf)
-			if n > 0 {
-				if _, wErr := c.Writer.Write(buf[:n]); wErr != nil {
-					fmt.Println("Error writing to client:", wErr)
-					break
-				}
-				if ok {
-					flusher.Flush()
-				}
-			}
-			if err != nil {
-				if err != io.EOF {
-					fmt.Println("Error reading ffmpeg output:", err)
-				}
-				break
-			}
-		}
+	n, err := w.c.Writer.Write(p)
+	w.c.Writer.Flush()
+	return n, err
+}
 
-		waitErr := cmd.Wait()
-		if waitErr != nil {
-			fmt.Println("FFmpeg exited with error:", waitErr)
-		} else {
-			fmt.Println("FFmpeg finished successfully.")
-		}
+// countDownload records a download of the video. Range requests past the first byte are
+// download managers resuming or splitting the same download, so they are not counted.
+// Failures are not fatal to the download.
+func countDownload(c *gin.Context, rm *repo.RepoManager, videoId string) {
+	if rangeHeader := c.GetHeader("Range"); rangeHeader != "" && !strings.HasPrefix(rangeHeader, "bytes=0-") {
+		return
+	}
+	if err := rm.CountVideoDownload(videoId); err != nil {
+		c.Error(err)
 	}
 }
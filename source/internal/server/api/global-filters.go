@@ -18,10 +18,11 @@ func RegisterGlobalFiltersRoute(rg *gin.RouterGroup, repoMgr *repo.RepoManager)
 }
 
 // getGlobalFilters retrieves predefined filter presets for global use.
+// Only tags of videos the caller may see are listed.
 func getGlobalFilters(repoMgr *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
-		filters, err := repoMgr.GetAllTags()
+		filters, err := repoMgr.GetVisibleTags(c.GetString("accountId"))
 		if err != nil {
 			apitypes.RespondError(c, http.StatusNotFound, "global filter error")
 			return
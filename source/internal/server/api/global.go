@@ -32,7 +32,7 @@ func getGlobalVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
 		sortParam := c.DefaultQuery("sort", "title_asc")
 		sortMode := repo.SortMode(sortParam)
 
-		videos, total, err := repoMgr.GetGlobalVideosPaginated(page, sortMode, maxPageSize)
+		videos, total, err := repoMgr.GetGlobalVideosPaginated(c.GetString("accountId"), page, sortMode, maxPageSize)
 		if err != nil || videos == nil {
 			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
 			return
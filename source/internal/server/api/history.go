@@ -2,9 +2,11 @@ package api
 
 import (
 	"net/http"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +18,11 @@ func RegisterUserWatchedRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
 		me.POST("/recent", addVideoToWatched(repoMgr))         // POST /api/v1/me/recent
 		me.GET("/recent", getUserWatchedVideos(repoMgr))       // GET /api/v1/me/recent
 		me.DELETE("/recent", clearUserWatchedHistory(repoMgr)) // DELETE /api/v1/me/recent
+
+		me.GET("/history", getWatchHistory(repoMgr))              // GET /api/v1/me/history?page=&since=&until=
+		me.DELETE("/history", clearWatchHistoryRange(repoMgr))    // DELETE /api/v1/me/history?since=&until=
+		me.DELETE("/history/:entryId", removeWatchEvent(repoMgr)) // DELETE /api/v1/me/history/:entryId
+		me.PUT("/history/paused", setWatchHistoryPaused(repoMgr)) // PUT /api/v1/me/history/paused
 	}
 }
 
@@ -86,6 +93,7 @@ func getUserWatchedVideos(r *repo.RepoManager) gin.HandlerFunc {
 			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
 			return
 		}
+		videos = r.FilterVisibleVideoRefs(accountID.(string), videos)
 
 		// Prepare the response with the videos in the current bucket, total count, and number of buckets
 		response := gin.H{
@@ -111,14 +119,19 @@ func addVideoToWatched(r *repo.RepoManager) gin.HandlerFunc {
 		}
 
 		var req struct {
-			VideoID string `json:"videoId"`
+			VideoID    string  `json:"videoId"`
+			WatchedSec float64 `json:"watchedSec"` // Optional time spent watching
 		}
 		if err := c.ShouldBindJSON(&req); err != nil || req.VideoID == "" {
 			apitypes.RespondError(c, http.StatusBadRequest, "Invalid videoId in request")
 			return
 		}
 
-		err := r.AddVideoToWatched(accountID.(string), req.VideoID)
+		if requireVisibleVideo(c, r, req.VideoID) == nil {
+			return
+		}
+
+		err := r.AddVideoToWatched(accountID.(string), req.VideoID, req.WatchedSec)
 		if err != nil {
 			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
 			return
@@ -146,3 +159,118 @@ func clearUserWatchedHistory(r *repo.RepoManager) gin.HandlerFunc {
 		apitypes.RespondSuccess(c, http.StatusOK, nil, "User watched history cleared successfully")
 	}
 }
+
+func getWatchHistory(r *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page <= 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid page parameter")
+			return
+		}
+		since, until, ok := historyRange(c)
+		if !ok {
+			return
+		}
+
+		pageSize := r.GetConfigs().MaxBucketSize
+		entries, total, err := r.GetWatchHistory(accountID.(string), since, until, page, pageSize)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve watch history")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"entries":     entries,
+			"paused":      r.IsHistoryPaused(accountID.(string)),
+			"currentPage": page,
+			"pageSize":    pageSize,
+			"totalItems":  total,
+			"totalPages":  (total + pageSize - 1) / pageSize,
+			"hasNextPage": page*pageSize < total,
+		}, "Fetched watch history")
+	}
+}
+
+func removeWatchEvent(r *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		entryId := c.Param("entryId")
+		if err := r.RemoveWatchEvent(accountID.(string), entryId); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "History entry not found")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"id": entryId}, "History entry removed")
+	}
+}
+
+func clearWatchHistoryRange(r *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		since, until, ok := historyRange(c)
+		if !ok {
+			return
+		}
+
+		removed, err := r.ClearWatchHistoryRange(accountID.(string), since, until)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to clear watch history")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"removed": removed}, "Watch history cleared")
+	}
+}
+
+func setWatchHistoryPaused(r *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		var req struct {
+			Paused *bool `json:"paused" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil || req.Paused == nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "paused is required")
+			return
+		}
+
+		if _, err := r.SetHistoryPaused(accountID.(string), *req.Paused); err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to update watch history setting")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"paused": *req.Paused}, "Watch history setting updated")
+	}
+}
+
+// historyRange parses the since and until query parameters. It answers 400 and returns
+// false when one of them is invalid.
+func historyRange(c *gin.Context) (since, until time.Time, ok bool) {
+	var err error
+	if since, err = datatypes.ParseAuditTime(c.Query("since")); err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, "Invalid 'since', use RFC 3339 or YYYY-MM-DD")
+		return since, until, false
+	}
+	if until, err = datatypes.ParseAuditTime(c.Query("until")); err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, "Invalid 'until', use RFC 3339 or YYYY-MM-DD")
+		return since, until, false
+	}
+	return since, until, true
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+)
+
+// newHistoryFixture is the access fixture with the history routes mounted. bob has marked
+// pubvid watched and carol privvid, which puts them in the history and the watched list.
+func newHistoryFixture(t *testing.T) *accessFixture {
+	t.Helper()
+	f := newAccessFixture(t)
+	RegisterUserWatchedRoutes(f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil)), f.repoMgr)
+
+	for username, videoID := range map[string]string{"bob": "pubvid", "carol": "privvid"} {
+		if err := f.repoMgr.AddVideoToWatched(f.accountID(t, username), videoID, 30); err != nil {
+			t.Fatalf("%s watches %s: %v", username, videoID, err)
+		}
+	}
+	return f
+}
+
+func (f *accessFixture) accountID(t *testing.T, username string) string {
+	t.Helper()
+	user, err := f.repoMgr.GetUserByUsername(username)
+	if err != nil {
+		t.Fatalf("get %s: %v", username, err)
+	}
+	return user.AccountID
+}
+
+func (f *accessFixture) delete(username, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodDelete, target, nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: f.sessions[username]})
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func (f *accessFixture) recentIDs(t *testing.T, username string) []string {
+	t.Helper()
+	var recent struct {
+		Videos []datatypes.VideoData `json:"videos"`
+	}
+	f.getData(t, username, "/api/v1/me/recent", &recent)
+	return videoIDs(recent.Videos)
+}
+
+func TestDeletedHistoryLeavesRecentlyWatched(t *testing.T) {
+	f := newHistoryFixture(t)
+	assertSameSet(t, "recent before", f.recentIDs(t, "bob"), []string{"pubvid"})
+
+	var history struct {
+		Entries []repo.WatchHistoryItem `json:"entries"`
+	}
+	f.getData(t, "bob", "/api/v1/me/history?page=1", &history)
+	if len(history.Entries) != 1 {
+		t.Fatalf("got %d history entries, want 1", len(history.Entries))
+	}
+
+	if w := f.delete("bob", "/api/v1/me/history/"+history.Entries[0].ID); w.Code != http.StatusOK {
+		t.Fatalf("delete entry: got %d: %s", w.Code, w.Body.String())
+	}
+	assertSameSet(t, "recent after deleting the entry", f.recentIDs(t, "bob"), []string{})
+
+	// Clearing a range that holds the entry works the same way
+	today := time.Now().UTC().Format("2006-01-02")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	if w := f.delete("carol", "/api/v1/me/history?since="+today+"&until="+tomorrow); w.Code != http.StatusOK {
+		t.Fatalf("clear range: got %d: %s", w.Code, w.Body.String())
+	}
+	assertSameSet(t, "recent after clearing the range", f.recentIDs(t, "carol"), []string{})
+}
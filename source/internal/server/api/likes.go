@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterVideoLikeRoutes registers liking and unliking videos.
+func RegisterVideoLikeRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	videos := rg.Group("/videos")
+	{
+		videos.PUT("/:videoId/like", setVideoLike(repoMgr, true))     // PUT /api/v1/videos/{videoId}/like
+		videos.DELETE("/:videoId/like", setVideoLike(repoMgr, false)) // DELETE /api/v1/videos/{videoId}/like
+	}
+}
+
+func setVideoLike(repoMgr *repo.RepoManager, liked bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, repoMgr, videoId) == nil {
+			return
+		}
+
+		likes, err := repoMgr.SetVideoLike(accountID.(string), videoId, liked)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to update like")
+			return
+		}
+
+		message := "Video liked"
+		if !liked {
+			message = "Video unliked"
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"videoId": videoId, "liked": liked, "likes": likes}, message)
+	}
+}
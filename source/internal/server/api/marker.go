@@ -31,6 +31,9 @@ func getMarkers(rm *repo.RepoManager) gin.HandlerFunc {
 			apitypes.RespondError(c, http.StatusBadRequest, "videoId parameter is required")
 			return
 		}
+		if requireVisibleVideo(c, rm, videoId) == nil {
+			return
+		}
 
 		markers, err := rm.GetMarkersByVideoID(videoId)
 		if err != nil {
@@ -60,20 +63,19 @@ func addMarker(rm *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		// 3. Verify the video exists
-		_, err := rm.GetVideoByID(videoId)
-		if err != nil {
-			// Note: If video is not found, 404 is usually better than 500
-			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
+		// 3. Verify the video exists and is visible to the caller
+		if requireVisibleVideo(c, rm, videoId) == nil {
 			return
 		}
 
 		// 4. Pass the bound data to your repository
-		rm.AddMarkerToVideo(videoId, datatypes.MarkerData{
+		marker := datatypes.MarkerData{
 			TimeSecond:  body.TimeSecond,
 			Label:       body.Label,
 			Description: body.Description,
-		})
+		}
+		rm.AddMarkerToVideo(videoId, marker)
+		recordAudit(c, rm, datatypes.AuditMarkerAdd, []string{videoId}, nil, marker)
 
 		apitypes.RespondSuccess(c, http.StatusCreated, nil, "Marker added successfully")
 	}
@@ -93,15 +95,23 @@ func removeMarker(rm *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		// 3. Verify the video exists
-		_, err := rm.GetVideoByID(videoId)
-		if err != nil {
-			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
+		// 3. Verify the video exists and is visible to the caller
+		if requireVisibleVideo(c, rm, videoId) == nil {
 			return
 		}
 
 		// 4. Pass the bound data to your repository
+		var removed *datatypes.MarkerData
+		if markers, err := rm.GetMarkersByVideoID(videoId); err == nil {
+			for i := range markers {
+				if markers[i].TimeSecond == body.TimeSecond {
+					removed = &markers[i]
+					break
+				}
+			}
+		}
 		rm.RemoveMarkerFromVideo(videoId, body.TimeSecond)
+		recordAudit(c, rm, datatypes.AuditMarkerRemove, []string{videoId}, removed, nil)
 
 		apitypes.RespondSuccess(c, http.StatusCreated, nil, "Marker removed successfully")
 	}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPlaybackRoutes registers the routes the player uses to save and resume playback.
+func RegisterPlaybackRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
+	me := rg.Group("/me")
+	{
+		me.POST("/progress", ReportPlaybackProgress(rm))                         // sent periodically by the player
+		me.GET("/progress/:videoId", GetPlaybackProgress(rm))                    // where to resume a video
+		me.GET("/continue-watching", GetContinueWatching(rm))                    // started and not finished
+		me.DELETE("/continue-watching/:videoId", RemoveFromContinueWatching(rm)) // forget a position
+	}
+}
+
+// POST /me/progress
+func ReportPlaybackProgress(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		var body struct {
+			VideoID     string   `json:"videoId" binding:"required"`
+			PositionSec *float64 `json:"positionSec" binding:"required"`
+			DurationSec float64  `json:"durationSec"` // Falls back to the indexed duration
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || body.PositionSec == nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "videoId and positionSec are required")
+			return
+		}
+
+		if requireVisibleVideo(c, rm, body.VideoID) == nil {
+			return
+		}
+
+		state, err := rm.ReportPlayback(accountID.(string), body.VideoID, *body.PositionSec, body.DurationSec)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, state, "Playback position saved")
+	}
+}
+
+// GET /me/progress/:videoId
+func GetPlaybackProgress(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, rm, videoId) == nil {
+			return
+		}
+
+		state, err := rm.GetPlaybackState(accountID.(string), videoId)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve playback position")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"playback":          state,
+			"resumePositionSec": state.ResumePositionSec(),
+		}, "Playback position retrieved")
+	}
+}
+
+// GET /me/continue-watching?limit=
+func GetContinueWatching(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if err != nil || limit < 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+
+		items, err := rm.GetContinueWatching(accountID.(string), limit)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve continue watching")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"videos": items}, "Continue watching retrieved")
+	}
+}
+
+// DELETE /me/continue-watching/:videoId
+func RemoveFromContinueWatching(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		videoId := c.Param("videoId")
+		if err := rm.ForgetPlayback(accountID.(string), videoId); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Video not found in continue watching")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"videoId": videoId}, "Video removed from continue watching")
+	}
+}
@@ -17,6 +17,9 @@ func RegisterUserPlaylistContentRoutes(rg *gin.RouterGroup, rm *repo.RepoManager
 		me.GET("/playlists/:playlistId", GetPlaylistVideos(rm))          // Get Playlist Videos Content
 		me.POST("/playlists/:playlistId/videos", AddVideoToPlaylist(rm)) // ad multi videos to a playlist
 	}
+
+	// Public and unlisted playlists can be opened by anyone with the link
+	rg.GET("/playlists/:playlistId", GetPlaylistVideos(rm))
 }
 
 // GET /me/playlists/:playlistId/videos
@@ -51,7 +54,7 @@ func GetPlaylistVideos(rm *repo.RepoManager) gin.HandlerFunc {
 		// 3. Fetch data (Notice we pass currentPage and pageSize now!)
 		videos, total, err := rm.GetPlaylistVideoIDsPaginated(accountID.(string), playlistId, currentPage, pageSize)
 		if err != nil {
-			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve playlist videos")
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
 			return
 		}
 
@@ -119,13 +122,22 @@ func AddVideoToPlaylist(rm *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
+		if requireVisibleVideo(c, rm, body.VideoID) == nil {
+			return
+		}
+
+		// Owners and collaborators with editor permission may add videos
+		if requireEditablePlaylist(c, rm, playlistId) == nil {
+			return
+		}
+
 		err := rm.AddVideoToPlaylist(accountID.(string), playlistId, body.VideoID)
 		if err != nil {
 			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to add video to playlist")
 			return
 		}
 
-		updatedPl, err := rm.GetPlaylistByID(accountID.(string), playlistId)
+		updatedPl, err := rm.GetPlaylist(accountID.(string), playlistId)
 		if err != nil {
 			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to get updated playlist")
 			return
@@ -146,11 +158,7 @@ func AddVideoToPlaylist(rm *repo.RepoManager) gin.HandlerFunc {
 			VideoCount:  len(updatedPl.VideoIDs),
 		}
 
-		if len(updatedPl.VideoIDs) > 0 {
-			resp.CoverImage = updatedPl.VideoIDs[0]
-		} else {
-			resp.CoverImage = ""
-		}
+		resp.CoverImage = updatedPl.CoverVideo()
 
 		apitypes.RespondSuccess(c, http.StatusOK, resp, "Video added to playlist")
 	}
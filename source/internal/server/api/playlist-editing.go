@@ -0,0 +1,269 @@
+package api
+
+import (
+	"net/http"
+	"os"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPlaylistEditingRoutes registers the routes for curating playlists: ordering,
+// details, covers, duplicating and adding many videos at once.
+func RegisterPlaylistEditingRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
+	me := rg.Group("/me")
+	{
+		me.PATCH("/playlists/:id", UpdatePlaylistDetails(rm))                    // title, description, privacy
+		me.PUT("/playlists/:id/videos/:videoId/position", MovePlaylistVideo(rm)) // move to an index
+		me.POST("/playlists/:playlistId/swap", SwapPlaylistVideos(rm))           // swap two videos
+		me.POST("/playlists/:playlistId/videos/bulk", InsertPlaylistVideos(rm))  // add many at an index
+		me.PUT("/playlists/:id/cover", SetPlaylistCover(rm))                     // cover video or frame
+		me.DELETE("/playlists/:id/cover", ClearPlaylistCover(rm))                // back to the first video
+		me.POST("/playlists/:playlistId/duplicate", DuplicatePlaylist(rm))       // copy into my playlists
+	}
+
+	rg.GET("/playlists/:playlistId/cover", GetPlaylistCover(rm))
+}
+
+// PATCH /me/playlists/:id
+func UpdatePlaylistDetails(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		var body struct {
+			Title       *string                   `json:"title"`
+			Description *string                   `json:"description"`
+			Privacy     *datatypes.PrivacySetting `json:"privacy"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		playlistID := c.Param("id")
+		before, err := rm.GetPlaylistByID(accountID.(string), playlistID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+
+		updated, err := rm.UpdatePlaylistDetails(accountID.(string), playlistID, repo.PlaylistDetails{
+			Title:       body.Title,
+			Description: body.Description,
+			Privacy:     body.Privacy,
+		})
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if body.Privacy != nil && *body.Privacy != before.Privacy {
+			recordAudit(c, rm, datatypes.AuditPlaylistPrivacy, []string{playlistID}, gin.H{"privacy": before.Privacy}, gin.H{"privacy": updated.Privacy})
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, newPlaylistSummaries(rm, c.GetString("accountId"), []datatypes.PlaylistData{*updated})[0], "Playlist updated successfully")
+	}
+}
+
+// PUT /me/playlists/:id/videos/:videoId/position
+func MovePlaylistVideo(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Position *int `json:"position" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || body.Position == nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "position is required")
+			return
+		}
+
+		playlist := requireEditablePlaylist(c, rm, c.Param("id"))
+		if playlist == nil {
+			return
+		}
+
+		updated, err := rm.MovePlaylistVideo(c.GetString("accountId"), playlist.ID, c.Param("videoId"), *body.Position)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Video not found in playlist")
+			return
+		}
+		respondPlaylistOrder(c, updated, "Video moved")
+	}
+}
+
+// POST /me/playlists/:playlistId/swap
+func SwapPlaylistVideos(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			First  string `json:"first" binding:"required"`
+			Second string `json:"second" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "first and second video IDs are required")
+			return
+		}
+
+		playlist := requireEditablePlaylist(c, rm, c.Param("playlistId"))
+		if playlist == nil {
+			return
+		}
+
+		updated, err := rm.SwapPlaylistVideos(c.GetString("accountId"), playlist.ID, body.First, body.Second)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Both videos must be in the playlist")
+			return
+		}
+		respondPlaylistOrder(c, updated, "Videos swapped")
+	}
+}
+
+// POST /me/playlists/:playlistId/videos/bulk
+func InsertPlaylistVideos(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			VideoIDs []string `json:"videoIds" binding:"required"`
+			Position *int     `json:"position"` // Appends when missing
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || len(body.VideoIDs) == 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "videoIds is required")
+			return
+		}
+
+		playlist := requireEditablePlaylist(c, rm, c.Param("playlistId"))
+		if playlist == nil {
+			return
+		}
+
+		for _, videoId := range body.VideoIDs {
+			if requireVisibleVideo(c, rm, videoId) == nil {
+				return
+			}
+		}
+
+		position := -1
+		if body.Position != nil {
+			position = *body.Position
+		}
+		updated, added, err := rm.InsertPlaylistVideos(c.GetString("accountId"), playlist.ID, body.VideoIDs, position)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to add videos to playlist")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"id":       updated.ID,
+			"added":    added,
+			"videoIds": updated.VideoIDs,
+		}, "Videos added to playlist")
+	}
+}
+
+// PUT /me/playlists/:id/cover
+func SetPlaylistCover(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			VideoID  string   `json:"videoId" binding:"required"`
+			FrameSec *float64 `json:"frameSec"` // Uses the video thumbnail when missing
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "videoId is required")
+			return
+		}
+
+		playlist := requireEditablePlaylist(c, rm, c.Param("id"))
+		if playlist == nil {
+			return
+		}
+		if requireVisibleVideo(c, rm, body.VideoID) == nil {
+			return
+		}
+
+		updated, err := rm.SetPlaylistCover(c.Request.Context(), c.GetString("accountId"), playlist.ID, body.VideoID, body.FrameSec)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, newPlaylistSummaries(rm, c.GetString("accountId"), []datatypes.PlaylistData{*updated})[0], "Playlist cover updated")
+	}
+}
+
+// DELETE /me/playlists/:id/cover
+func ClearPlaylistCover(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		playlist := requireEditablePlaylist(c, rm, c.Param("id"))
+		if playlist == nil {
+			return
+		}
+
+		updated, err := rm.ClearPlaylistCover(c.GetString("accountId"), playlist.ID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to reset playlist cover")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, newPlaylistSummaries(rm, c.GetString("accountId"), []datatypes.PlaylistData{*updated})[0], "Playlist cover reset")
+	}
+}
+
+// GET /playlists/:playlistId/cover
+func GetPlaylistCover(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		coverPath, err := rm.GetPlaylistCoverPath(c.GetString("accountId"), c.Param("playlistId"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist cover not found")
+			return
+		}
+		if _, err := os.Stat(coverPath); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist cover not found")
+			return
+		}
+		c.File(coverPath)
+	}
+}
+
+// POST /me/playlists/:playlistId/duplicate
+func DuplicatePlaylist(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		var body struct {
+			Title string `json:"title"` // Defaults to "<title> (copy)"
+		}
+		_ = c.ShouldBindJSON(&body)
+
+		copied, err := rm.DuplicatePlaylist(accountID.(string), c.Param("playlistId"), body.Title)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusCreated, newPlaylistSummaries(rm, c.GetString("accountId"), []datatypes.PlaylistData{*copied})[0], "Playlist duplicated successfully")
+	}
+}
+
+// requireEditablePlaylist answers 404 when the playlist cannot be opened and 403 when it
+// cannot be edited by the request's account, and returns nil in both cases.
+func requireEditablePlaylist(c *gin.Context, rm *repo.RepoManager, playlistId string) *datatypes.PlaylistData {
+	accountID := c.GetString("accountId")
+	playlist, err := rm.GetPlaylist(accountID, playlistId)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+		return nil
+	}
+	if !rm.CanEditPlaylist(accountID, playlist) {
+		apitypes.RespondError(c, http.StatusForbidden, "You cannot edit this playlist")
+		return nil
+	}
+	return playlist
+}
+
+func respondPlaylistOrder(c *gin.Context, playlist *datatypes.PlaylistData, message string) {
+	apitypes.RespondSuccess(c, http.StatusOK, gin.H{"id": playlist.ID, "videoIds": playlist.VideoIDs}, message)
+}
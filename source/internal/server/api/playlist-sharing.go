@@ -0,0 +1,250 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SharePlaylistRequest is the body for sharing a playlist with a user or a space.
+type SharePlaylistRequest struct {
+	Kind       datatypes.PlaylistShareKind  `json:"kind" binding:"required"`   // user or space
+	Target     string                       `json:"target" binding:"required"` // Username or space name
+	Permission datatypes.PlaylistPermission `json:"permission" binding:"required"`
+}
+
+// PlaylistShareResponse is a share with the user's name resolved.
+type PlaylistShareResponse struct {
+	Kind       datatypes.PlaylistShareKind  `json:"kind"`
+	Target     string                       `json:"target"` // Username or space name
+	Permission datatypes.PlaylistPermission `json:"permission"`
+}
+
+// PlaylistChangeResponse is a history entry with the user's name resolved.
+type PlaylistChangeResponse struct {
+	Time     time.Time `json:"time"`
+	Username string    `json:"username"`
+	Action   string    `json:"action"`
+	VideoID  string    `json:"videoId"`
+}
+
+// SharedPlaylistSummary is a playlist in the "shared with me" listing.
+type SharedPlaylistSummary struct {
+	PlaylistSummary
+	Owner      string `json:"owner"`      // Username of the owner
+	Permission string `json:"permission"` // viewer or editor
+}
+
+// RegisterPlaylistSharingRoutes registers the routes for sharing playlists and editing them together.
+func RegisterPlaylistSharingRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
+	me := rg.Group("/me")
+	{
+		me.GET("/playlists/shared", GetSharedPlaylists(rm))                      // playlists shared with me
+		me.GET("/playlists/:playlistId/shares", GetPlaylistShares(rm))           // who a playlist is shared with
+		me.PUT("/playlists/:id/shares", SharePlaylist(rm))                       // share with a user or space
+		me.DELETE("/playlists/:id/shares/:kind/:target", UnsharePlaylist(rm))    // stop sharing
+		me.GET("/playlists/:playlistId/history", GetPlaylistHistory(rm))         // who added and removed videos
+		me.DELETE("/playlists/:id/videos/:videoId", RemoveVideoFromPlaylist(rm)) // owners and editors
+	}
+}
+
+// GET /me/playlists/shared
+func GetSharedPlaylists(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		playlists, err := rm.GetPlaylistsSharedWith(accountID.(string))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve playlists")
+			return
+		}
+
+		summaries := newPlaylistSummaries(rm, c.GetString("accountId"), playlists)
+		response := make([]SharedPlaylistSummary, 0, len(playlists))
+		for i := range playlists {
+			response = append(response, SharedPlaylistSummary{
+				PlaylistSummary: summaries[i],
+				Owner:           usernameOf(rm, playlists[i].OwnerAccountId),
+				Permission:      rm.GetPlaylistPermission(accountID.(string), &playlists[i]),
+			})
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"playlists": response}, "Shared playlists retrieved successfully")
+	}
+}
+
+// GET /me/playlists/:playlistId/shares
+func GetPlaylistShares(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		playlist, err := rm.GetPlaylistByID(accountID.(string), c.Param("playlistId"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"shares": newPlaylistShareResponses(rm, playlist.Shares)}, "Playlist shares retrieved successfully")
+	}
+}
+
+// PUT /me/playlists/:id/shares
+func SharePlaylist(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		var body SharePlaylistRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "kind, target and permission are required")
+			return
+		}
+
+		share := datatypes.PlaylistShare{Kind: body.Kind, Target: body.Target, Permission: body.Permission}
+		if body.Kind == datatypes.ShareWithUser {
+			user, err := rm.GetUserByUsername(body.Target)
+			if err != nil {
+				apitypes.RespondError(c, http.StatusNotFound, "User not found")
+				return
+			}
+			share.Target = user.AccountID
+		}
+
+		playlistID := c.Param("id")
+		if _, err := rm.GetPlaylistByID(accountID.(string), playlistID); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+
+		playlist, err := rm.SharePlaylist(accountID.(string), playlistID, share)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		recordAudit(c, rm, datatypes.AuditPlaylistShare, []string{playlistID, share.Target}, nil, share)
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"shares": newPlaylistShareResponses(rm, playlist.Shares)}, "Playlist shared successfully")
+	}
+}
+
+// DELETE /me/playlists/:id/shares/:kind/:target
+func UnsharePlaylist(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		kind := datatypes.PlaylistShareKind(c.Param("kind"))
+		target := c.Param("target")
+		if kind == datatypes.ShareWithUser {
+			user, err := rm.GetUserByUsername(target)
+			if err != nil {
+				apitypes.RespondError(c, http.StatusNotFound, "User not found")
+				return
+			}
+			target = user.AccountID
+		}
+
+		playlistID := c.Param("id")
+		playlist, err := rm.UnsharePlaylist(accountID.(string), playlistID, kind, target)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Share not found")
+			return
+		}
+
+		recordAudit(c, rm, datatypes.AuditPlaylistUnshare, []string{playlistID, target}, gin.H{"kind": kind, "target": target}, nil)
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"shares": newPlaylistShareResponses(rm, playlist.Shares)}, "Playlist unshared successfully")
+	}
+}
+
+// GET /me/playlists/:playlistId/history
+func GetPlaylistHistory(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		playlist, err := rm.GetPlaylist(accountID.(string), c.Param("playlistId"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+
+		// Newest first
+		history := make([]PlaylistChangeResponse, 0, len(playlist.History))
+		for i := len(playlist.History) - 1; i >= 0; i-- {
+			change := playlist.History[i]
+			history = append(history, PlaylistChangeResponse{
+				Time:     change.Time,
+				Username: usernameOf(rm, change.AccountID),
+				Action:   change.Action,
+				VideoID:  change.VideoID,
+			})
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"history": history}, "Playlist history retrieved successfully")
+	}
+}
+
+// DELETE /me/playlists/:id/videos/:videoId
+func RemoveVideoFromPlaylist(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		playlistId := c.Param("id")
+		videoId := c.Param("videoId")
+
+		if requireEditablePlaylist(c, rm, playlistId) == nil {
+			return
+		}
+
+		if err := rm.RemoveVideoFromPlaylist(c.GetString("accountId"), playlistId, videoId); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Video not found in playlist")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"id": playlistId, "videoId": videoId}, "Video removed from playlist")
+	}
+}
+
+func newPlaylistShareResponses(rm *repo.RepoManager, shares []datatypes.PlaylistShare) []PlaylistShareResponse {
+	response := make([]PlaylistShareResponse, 0, len(shares))
+	for _, share := range shares {
+		target := share.Target
+		if share.Kind == datatypes.ShareWithUser {
+			target = usernameOf(rm, share.Target)
+		}
+		response = append(response, PlaylistShareResponse{
+			Kind:       share.Kind,
+			Target:     target,
+			Permission: share.Permission,
+		})
+	}
+	return response
+}
+
+// usernameOf returns the username of an account, or the account ID if it no longer exists.
+func usernameOf(rm *repo.RepoManager, accountId string) string {
+	if user, err := rm.GetUserByAccountID(accountId); err == nil {
+		return user.Username
+	}
+	return accountId
+}
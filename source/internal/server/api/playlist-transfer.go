@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/playlistfile"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+	"ova-cli/source/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPlaylistFileSize caps imported playlist files.
+const maxPlaylistFileSize = 5 << 20
+
+// RegisterPlaylistTransferRoutes registers playlist export to and import from M3U8/XSPF files.
+func RegisterPlaylistTransferRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
+	me := rg.Group("/me")
+	{
+		me.GET("/playlists/:playlistId/export", ExportPlaylist(rm)) // ?format=m3u8|xspf&mode=stream|path
+		me.POST("/playlists/import", ImportPlaylist(rm))            // multipart "file" or raw body
+	}
+}
+
+// GET /me/playlists/:playlistId/export
+func ExportPlaylist(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, err := playlistfile.ParseFormat(c.DefaultQuery("format", "m3u8"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		mode := c.DefaultQuery("mode", repo.ExportModeStream)
+		if mode != repo.ExportModeStream && mode != repo.ExportModePath {
+			apitypes.RespondError(c, http.StatusBadRequest, "mode must be stream or path")
+			return
+		}
+
+		opts := repo.PlaylistExportOptions{Mode: mode, BaseURL: requestBaseURL(c)}
+		// Requests made with an API token get stream grants, so players can stream without a session
+		if token, ok := apiTokenFromContext(c); ok {
+			opts.Token = token
+		}
+
+		exported, err := rm.ExportPlaylist(c.GetString("accountId"), c.Param("playlistId"), opts)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := playlistfile.Write(&buf, format, exported); err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to write playlist file")
+			return
+		}
+
+		name := utils.ToSlug(exported.Title)
+		if name == "" {
+			name = "playlist"
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", name, format))
+		c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+	}
+}
+
+// POST /me/playlists/import?title=&dryRun=true
+func ImportPlaylist(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		data, err := readPlaylistUpload(c)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if c.Query("dryRun") == "true" {
+			parsed, err := playlistfile.Parse(data)
+			if err != nil {
+				apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			resolved, unresolved, err := rm.ResolvePlaylistEntries(accountID.(string), parsed.Entries)
+			if err != nil {
+				apitypes.RespondError(c, http.StatusInternalServerError, "Failed to resolve playlist entries")
+				return
+			}
+			apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+				"videoIds":   resolved,
+				"unresolved": unresolved,
+			}, "Playlist file resolved")
+			return
+		}
+
+		playlist, unresolved, err := rm.ImportPlaylist(accountID.(string), data, c.Query("title"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusCreated, gin.H{
+			"playlist":   newPlaylistSummaries(rm, c.GetString("accountId"), []datatypes.PlaylistData{*playlist})[0],
+			"unresolved": unresolved,
+		}, "Playlist imported successfully")
+	}
+}
+
+// readPlaylistUpload reads the playlist file from a multipart "file" field or the raw body.
+func readPlaylistUpload(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPlaylistFileSize)
+
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("file is required")
+		}
+		opened, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read uploaded file")
+		}
+		defer opened.Close()
+		reader = opened
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxPlaylistFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist file")
+	}
+	if len(data) > maxPlaylistFileSize {
+		return nil, fmt.Errorf("playlist file is too large")
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("playlist file is empty")
+	}
+	return data, nil
+}
+
+// requestBaseURL returns the scheme and host the client used to reach the server.
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
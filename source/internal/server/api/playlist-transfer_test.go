@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/playlistfile"
+)
+
+// newExportFixture is the access fixture with playlist export mounted and a playlist of
+// root's holding every fixture video. It returns a read token of root's.
+func newExportFixture(t *testing.T) (*accessFixture, *datatypes.APITokenData, string, string) {
+	t.Helper()
+	f := newAccessFixture(t)
+	RegisterPlaylistTransferRoutes(f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil)), f.repoMgr)
+
+	user, err := f.repoMgr.GetUserByUsername("root")
+	if err != nil {
+		t.Fatalf("get root: %v", err)
+	}
+	playlist, err := f.repoMgr.CreatePlaylist(user.AccountID, "export me", "")
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	for _, v := range accessFixtureVideos {
+		if err := f.repoMgr.AddVideoToPlaylist(user.AccountID, playlist.ID, v.id); err != nil {
+			t.Fatalf("add %s to playlist: %v", v.id, err)
+		}
+	}
+
+	token, plain, err := f.repoMgr.CreateAPIToken(user.AccountID, "player", []datatypes.TokenScope{datatypes.TokenScopeRead}, 0)
+	if err != nil {
+		t.Fatalf("create api token: %v", err)
+	}
+	return f, token, plain, playlist.ID
+}
+
+func (f *accessFixture) getWithToken(token, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExportedStreamURLsCarryGrantsNotTheToken(t *testing.T) {
+	f, token, plain, playlistID := newExportFixture(t)
+
+	w := f.getWithToken(plain, "/api/v1/me/playlists/"+playlistID+"/export?format=m3u8")
+	if w.Code != http.StatusOK {
+		t.Fatalf("export: got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), plain) || strings.Contains(w.Body.String(), "access_token") {
+		t.Fatalf("exported playlist contains the api token:\n%s", w.Body.String())
+	}
+	exported, err := playlistfile.Parse(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("parse export: %v", err)
+	}
+	if len(exported.Entries) != len(accessFixtureVideos) {
+		t.Fatalf("got %d entries, want %d", len(exported.Entries), len(accessFixtureVideos))
+	}
+
+	streamURLs := map[string]string{}
+	for _, entry := range exported.Entries {
+		u, err := url.Parse(entry.Location)
+		if err != nil || u.Query().Get("grant") == "" {
+			t.Fatalf("entry %q has no grant", entry.Location)
+		}
+		streamURLs[entry.Hash] = u.RequestURI()
+	}
+
+	for videoID, target := range streamURLs {
+		if w := f.get("", target); w.Code != http.StatusOK || w.Body.String() != "video "+videoID {
+			t.Errorf("stream %s with its grant: got %d %q", videoID, w.Code, w.Body.String())
+		}
+	}
+
+	// A grant only opens the video it was issued for, and only the stream route
+	pubGrant := strings.SplitN(streamURLs["pubvid"], "?", 2)[1]
+	if w := f.get("", "/api/v1/stream/privvid?"+pubGrant); w.Code != http.StatusUnauthorized {
+		t.Errorf("grant of pubvid on privvid: got %d, want 401", w.Code)
+	}
+	if w := f.get("", "/api/v1/videos/pubvid?"+pubGrant); w.Code != http.StatusUnauthorized {
+		t.Errorf("grant on the video route: got %d, want 401", w.Code)
+	}
+
+	// Revoking the token the export was made with revokes its grants
+	if err := f.repoMgr.RevokeAPIToken(token.AccountID, token.ID); err != nil {
+		t.Fatalf("revoke token: %v", err)
+	}
+	if w := f.get("", streamURLs["pubvid"]); w.Code != http.StatusUnauthorized {
+		t.Errorf("grant of a revoked token: got %d, want 401", w.Code)
+	}
+}
+
+func TestAccessTokenQueryOnlyOnStreamRoutes(t *testing.T) {
+	f, _, plain, _ := newExportFixture(t)
+	query := "?access_token=" + url.QueryEscape(plain)
+
+	if w := f.get("", "/api/v1/stream/pubvid"+query); w.Code != http.StatusOK {
+		t.Errorf("stream with access_token: got %d, want 200", w.Code)
+	}
+	for _, target := range []string{"/api/v1/videos/pubvid", "/api/v1/download/pubvid", "/api/v1/videos/global"} {
+		if w := f.get("", target+query); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s with access_token: got %d, want 401", target, w.Code)
+		}
+	}
+}
+
+func TestRedactLoggedPath(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/videos":                             "/api/v1/videos",
+		"/api/v1/stream/x?access_token=ova_a.b":      "/api/v1/stream/x?access_token=REDACTED",
+		"/api/v1/stream/x?grant=abc&t=5":             "/api/v1/stream/x?grant=REDACTED&t=5",
+		"/api/v1/search?q=a&Access_Token=ova_a.b&x=": "/api/v1/search?q=a&Access_Token=REDACTED&x=",
+		"/api/v1/search?q=grant":                     "/api/v1/search?q=grant",
+	}
+	for path, want := range tests {
+		if got := redactLoggedPath(path); got != want {
+			t.Errorf("redactLoggedPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 
@@ -15,7 +16,10 @@ func RegisterUserPlaylistRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
 		users.GET("/playlists", GetPlaylistsByUser(rm)) // get user Playlist
 		users.POST("/playlists", CreatePlaylist(rm))    // Create New User Playlist
 		users.DELETE("/playlists/:id", DeletePlaylist(rm))
+		users.PUT("/playlists/:id/privacy", SetPlaylistPrivacy(rm)) // public, unlisted or private
 	}
+
+	rg.GET("/users/:username/playlists", GetPublicPlaylistsByUser(rm)) // public playlists of any user
 }
 
 // GET /me/playlists
@@ -33,37 +37,8 @@ func GetPlaylistsByUser(rm *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		type PlaylistResponse struct {
-			ID          string `json:"id"`
-			Title       string `json:"title"`
-			Description string `json:"description"`
-			CoverImage  string `json:"coverImageUrl"`
-			VideoCount  int    `json:"videoCount"`
-		}
-
-		resp := make([]PlaylistResponse, 0, len(playlists))
-
-		for _, pl := range playlists {
-
-			count := len(pl.VideoIDs)
-			var coverImage string
-			if count > 0 {
-				coverImage = pl.VideoIDs[0]
-			} else {
-				coverImage = ""
-			}
-
-			resp = append(resp, PlaylistResponse{
-				ID:          pl.ID,
-				Title:       pl.Title,
-				CoverImage:  coverImage,
-				Description: pl.Description,
-				VideoCount:  count,
-			})
-		}
-
 		response := gin.H{
-			"playlists": resp,
+			"playlists": newPlaylistSummaries(rm, c.GetString("accountId"), playlists),
 		}
 
 		apitypes.RespondSuccess(c, http.StatusOK, response, "Playlists retrieved successfully")
@@ -130,3 +105,99 @@ func DeletePlaylist(rm *repo.RepoManager) gin.HandlerFunc {
 		)
 	}
 }
+
+// PUT /me/playlists/:id/privacy
+func SetPlaylistPrivacy(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, "Account ID not found")
+			return
+		}
+
+		var body struct {
+			Privacy datatypes.PrivacySetting `json:"privacy" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || !datatypes.IsValidPrivacySetting(body.Privacy) {
+			apitypes.RespondError(c, http.StatusBadRequest, "Privacy must be public, unlisted or private")
+			return
+		}
+
+		playlistID := c.Param("id")
+		before, err := rm.GetPlaylistByID(accountID.(string), playlistID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Playlist not found")
+			return
+		}
+
+		if err := rm.SetPlaylistPrivacy(accountID.(string), playlistID, body.Privacy); err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to update playlist privacy")
+			return
+		}
+
+		recordAudit(c, rm, datatypes.AuditPlaylistPrivacy, []string{playlistID}, gin.H{"privacy": before.Privacy}, gin.H{"privacy": body.Privacy})
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"id": playlistID, "privacy": body.Privacy}, "Playlist privacy updated")
+	}
+}
+
+// GET /users/:username/playlists
+func GetPublicPlaylistsByUser(rm *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := rm.GetUserByUsername(c.Param("username"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "User not found")
+			return
+		}
+
+		playlists, err := rm.GetPublicPlaylistsByUser(user.AccountID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve playlists")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"playlists": newPlaylistSummaries(rm, c.GetString("accountId"), playlists),
+		}, "Playlists retrieved successfully")
+	}
+}
+
+// PlaylistSummary is a playlist as shown in playlist listings.
+type PlaylistSummary struct {
+	ID          string                   `json:"id"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Privacy     datatypes.PrivacySetting `json:"privacy"`
+	CoverImage  string                   `json:"coverImageUrl"`           // ID of the cover video
+	CoverFrame  *float64                 `json:"coverFrameSec,omitempty"` // Set when a frame is used, see /playlists/:id/cover
+	VideoCount  int                      `json:"videoCount"`
+}
+
+// newPlaylistSummaries summarises playlists as the viewer sees them: the count and cover only
+// take the videos the viewer may see into account, and a hidden cover video leaves no cover.
+func newPlaylistSummaries(rm *repo.RepoManager, viewerId string, playlists []datatypes.PlaylistData) []PlaylistSummary {
+	visible := rm.VisibleVideoIDChecker(viewerId)
+	resp := make([]PlaylistSummary, 0, len(playlists))
+	for _, pl := range playlists {
+		count := 0
+		for _, videoId := range pl.VideoIDs {
+			if visible(videoId) {
+				count++
+			}
+		}
+		cover, frame := pl.CoverVideo(), pl.CoverFrameSec
+		if cover != "" && !visible(cover) {
+			cover, frame = "", nil
+		}
+
+		resp = append(resp, PlaylistSummary{
+			ID:          pl.ID,
+			Title:       pl.Title,
+			Description: pl.Description,
+			Privacy:     pl.Privacy,
+			CoverImage:  cover,
+			CoverFrame:  frame,
+			VideoCount:  count,
+		})
+	}
+	return resp
+}
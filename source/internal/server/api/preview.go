@@ -19,6 +19,9 @@ func RegisterPreviewRoutes(rg *gin.RouterGroup, rm *repo.RepoManager) {
 func getPreview(rm *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, rm, videoId) == nil {
+			return
+		}
 
 		// Attempt to fetch the preview path using GetPreviewFilePathByVideoID
 		previewPath := rm.GetPreviewFilePathByVideoID(videoId)
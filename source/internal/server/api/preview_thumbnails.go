@@ -18,6 +18,9 @@ func RegisterStoryboardRoutes(rg *gin.RouterGroup, repoManager *repo.RepoManager
 	rg.GET("/preview-thumbnails/:videoId/:filename", func(c *gin.Context) {
 		videoId := c.Param("videoId")
 		filename := c.Param("filename")
+		if requireVisibleVideo(c, repoManager, videoId) == nil {
+			return
+		}
 
 		// Get the correct folder path for the storyboard using GetPreviewThumbnailsFolderPathByVideoID
 		storyboardPath := filepath.Join(repoManager.GetPreviewThumbnailsFolderPathByVideoID(videoId), filename)
@@ -37,7 +37,7 @@ func quickSearch(repoManager *repo.RepoManager) gin.HandlerFunc {
 		}
 
 		// Perform the search for suggestions (partial matches)
-		suggestions, err := repoManager.QuickSearch(query)
+		suggestions, err := repoManager.QuickSearch(c.GetString("accountId"), query)
 		if err != nil {
 			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve search suggestions")
 			return
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecommendations caps how many recommendations one request returns.
+const maxRecommendations = 100
+
+// RegisterRecommendationRoutes registers the personal home feed and "up next".
+func RegisterRecommendationRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	rg.GET("/me/recommendations", getRecommendations(repoMgr))   // GET /api/v1/me/recommendations?limit=
+	rg.GET("/videos/:videoId/up-next", getUpNextVideos(repoMgr)) // GET /api/v1/videos/{videoId}/up-next?limit=
+}
+
+// recommendationLimit reads the limit query parameter, answering 400 when it is invalid.
+func recommendationLimit(c *gin.Context, fallback int) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(fallback)))
+	if err != nil || limit < 1 || limit > maxRecommendations {
+		apitypes.RespondError(c, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxRecommendations))
+		return 0, false
+	}
+	return limit, true
+}
+
+func getRecommendations(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+		limit, ok := recommendationLimit(c, 20)
+		if !ok {
+			return
+		}
+
+		videos, err := repoMgr.GetRecommendations(accountID.(string), limit)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve recommendations")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"videos": videos}, "Recommendations retrieved successfully")
+	}
+}
+
+func getUpNextVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, repoMgr, videoId) == nil {
+			return
+		}
+		limit, ok := recommendationLimit(c, 8)
+		if !ok {
+			return
+		}
+
+		videos, err := repoMgr.GetUpNext(accountID.(string), videoId, limit)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve up next videos")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"videos": videos}, "Up next videos retrieved successfully")
+	}
+}
@@ -83,6 +83,7 @@ func getUserSaved(repoManager *repo.RepoManager) gin.HandlerFunc {
 			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
 			return
 		}
+		videos = repoManager.FilterVisibleVideoRefs(accountID.(string), videos)
 
 		// Prepare the response with saved video IDs, total video count, and bucket details
 		response := gin.H{
@@ -110,9 +111,8 @@ func addUserSaved(repoManager *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		// Check video existence via RepoManager
-		if _, err := repoManager.GetVideoByID(videoID); err != nil {
-			apitypes.RespondError(c, http.StatusNotFound, "Video not found")
+		// Check the video exists and is visible to the caller
+		if requireVisibleVideo(c, repoManager, videoID) == nil {
 			return
 		}
 
@@ -60,7 +60,7 @@ func searchVideos(repoManager *repo.RepoManager) gin.HandlerFunc {
 			Marker: marker,
 		}
 
-		result, total, err := repoManager.SearchVideosPaginated(criteria, currentPage, pageSize, sortMode)
+		result, total, err := repoManager.SearchVideosPaginated(c.GetString("accountId"), criteria, currentPage, pageSize, sortMode)
 		if err != nil {
 			apitypes.RespondError(c, http.StatusInternalServerError, err.Error())
 			return
@@ -0,0 +1,474 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateShareLinkRequest is the body for sharing a video with people without an account.
+type CreateShareLinkRequest struct {
+	ExpiresInHours int     `json:"expiresInHours"` // 0 uses the default lifetime
+	StartSec       float64 `json:"startSec"`
+	EndSec         float64 `json:"endSec"`
+	AllowDownload  bool    `json:"allowDownload"`
+	Password       string  `json:"password"`
+	MaxViews       int     `json:"maxViews"` // 0 means unlimited
+}
+
+// ShareLinkResponse is the owner's view of a share link; the password hash is never returned.
+type ShareLinkResponse struct {
+	ID               string    `json:"id"`
+	VideoID          string    `json:"videoId"`
+	CreatedBy        string    `json:"createdBy"`
+	CreatedAt        time.Time `json:"createdAt"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	StartSec         float64   `json:"startSec,omitempty"`
+	EndSec           float64   `json:"endSec,omitempty"`
+	AllowDownload    bool      `json:"allowDownload"`
+	RequiresPassword bool      `json:"requiresPassword"`
+	MaxViews         int       `json:"maxViews,omitempty"`
+	Views            int       `json:"views"`
+	Revoked          bool      `json:"revoked"`
+	Token            string    `json:"token,omitempty"`
+	URL              string    `json:"url,omitempty"` // Path of the public player page
+}
+
+// ShareLinkInfo is what an anonymous visitor learns about a share link before opening it.
+// A password-protected link only tells that it needs a password and when it expires until
+// it is unlocked.
+type ShareLinkInfo struct {
+	Title            string    `json:"title,omitempty"`
+	DurationSec      int       `json:"durationSec,omitempty"`
+	StartSec         float64   `json:"startSec,omitempty"`
+	EndSec           float64   `json:"endSec,omitempty"`
+	AllowDownload    bool      `json:"allowDownload,omitempty"`
+	RequiresPassword bool      `json:"requiresPassword"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// OpenShareLinkRequest carries the password for protected share links.
+type OpenShareLinkRequest struct {
+	Password string `json:"password"`
+}
+
+// RegisterShareLinkRoutes sets up the routes owners use to create, list and revoke share links.
+func RegisterShareLinkRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	rg.POST("/videos/:videoId/share", createShareLink(repoMgr))     // POST /api/v1/videos/:videoId/share
+	rg.GET("/videos/:videoId/shares", listVideoShareLinks(repoMgr)) // GET /api/v1/videos/:videoId/shares
+	rg.GET("/me/shares", listMyShareLinks(repoMgr))                 // GET /api/v1/me/shares
+	rg.DELETE("/me/shares/:linkId", revokeShareLink(repoMgr))       // DELETE /api/v1/me/shares/:linkId
+}
+
+// RegisterPublicShareRoutes sets up /share/:token, the routes used by anonymous visitors.
+// They must be listed as a public prefix since the signed token is the only credential.
+func RegisterPublicShareRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	share := rg.Group("/share/:token")
+	{
+		share.GET("", getShareLinkInfo(repoMgr))             // GET /api/v1/share/:token?grant=
+		share.POST("/open", openShareLink(repoMgr))          // POST /api/v1/share/:token/open
+		share.GET("/stream", streamSharedVideo(repoMgr))     // GET /api/v1/share/:token/stream?grant=
+		share.HEAD("/stream", streamSharedVideo(repoMgr))    // HEAD /api/v1/share/:token/stream?grant=
+		share.GET("/download", downloadSharedVideo(repoMgr)) // GET /api/v1/share/:token/download?grant=
+		share.GET("/thumbnail", getSharedThumbnail(repoMgr)) // GET /api/v1/share/:token/thumbnail
+	}
+}
+
+// RegisterSharePlayerPage serves the minimal player page that share link URLs point to.
+func RegisterSharePlayerPage(router *gin.Engine) {
+	router.GET("/share/:token", func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Header("Referrer-Policy", "no-referrer")
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(sharePlayerPage))
+	})
+}
+
+func createShareLink(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID := c.GetString("accountId")
+		videoId := c.Param("videoId")
+
+		var req CreateShareLinkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		video := requireVisibleVideo(c, repoMgr, videoId)
+		if video == nil {
+			return
+		}
+		if !repoMgr.CanManageVideo(accountID, video) {
+			apitypes.RespondError(c, http.StatusForbidden, "Only the uploader or an admin can share this video")
+			return
+		}
+
+		link, token, err := repoMgr.CreateShareLink(accountID, videoId, repo.ShareLinkOptions{
+			TTL:           time.Duration(req.ExpiresInHours) * time.Hour,
+			StartSec:      req.StartSec,
+			EndSec:        req.EndSec,
+			AllowDownload: req.AllowDownload,
+			Password:      req.Password,
+			MaxViews:      req.MaxViews,
+		})
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// The token is a credential, so the audit log only keeps the link's settings
+		response := newShareLinkResponse(link, token)
+		audited := response
+		audited.Token, audited.URL = "", ""
+		recordAudit(c, repoMgr, datatypes.AuditShareCreate, []string{link.ID, videoId}, nil, audited)
+		apitypes.RespondSuccess(c, http.StatusCreated, response, "Share link created successfully")
+	}
+}
+
+func listVideoShareLinks(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, repoMgr, videoId) == nil {
+			return
+		}
+		respondShareLinks(c, repoMgr, videoId)
+	}
+}
+
+func listMyShareLinks(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondShareLinks(c, repoMgr, "")
+	}
+}
+
+func respondShareLinks(c *gin.Context, repoMgr *repo.RepoManager, videoId string) {
+	links, err := repoMgr.GetShareLinks(c.GetString("accountId"), videoId)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, "Failed to load share links")
+		return
+	}
+
+	response := make([]ShareLinkResponse, 0, len(links))
+	for i := range links {
+		token, err := repoMgr.ShareLinkToken(&links[i])
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to sign share link")
+			return
+		}
+		response = append(response, newShareLinkResponse(&links[i], token))
+	}
+	apitypes.RespondSuccess(c, http.StatusOK, response, "Share links retrieved successfully")
+}
+
+func revokeShareLink(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		linkId := c.Param("linkId")
+
+		link, err := repoMgr.RevokeShareLink(c.GetString("accountId"), linkId)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Share link not found")
+			return
+		}
+
+		recordAudit(c, repoMgr, datatypes.AuditShareRevoke, []string{link.ID, link.VideoID}, nil, nil)
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"id": link.ID}, "Share link revoked successfully")
+	}
+}
+
+func getShareLinkInfo(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, video := requireShareLink(c, repoMgr)
+		if link == nil {
+			return
+		}
+		// Titles are often file names, so a locked link does not give them away
+		if link.HasPassword() && !repoMgr.VerifyShareGrant(link, c.Query("grant")) {
+			apitypes.RespondSuccess(c, http.StatusOK, ShareLinkInfo{
+				RequiresPassword: true,
+				ExpiresAt:        link.ExpiresAt,
+			}, "Share link retrieved successfully")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, ShareLinkInfo{
+			Title:            video.Title,
+			DurationSec:      video.Codecs.DurationSec,
+			StartSec:         link.StartSec,
+			EndSec:           link.EndSec,
+			AllowDownload:    link.AllowDownload,
+			RequiresPassword: link.HasPassword(),
+			ExpiresAt:        link.ExpiresAt,
+		}, "Share link retrieved successfully")
+	}
+}
+
+func openShareLink(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req OpenShareLinkRequest
+		_ = c.ShouldBindJSON(&req) // the body is optional for links without a password
+
+		_, grant, err := repoMgr.OpenShareLink(c.Param("token"), req.Password, c.ClientIP())
+		var throttled *repo.ShareLinkThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			respondThrottled(c, throttled.Wait, "Too many wrong passwords, try again later")
+			return
+		case errors.Is(err, repo.ErrShareLinkPassword):
+			apitypes.RespondError(c, http.StatusUnauthorized, "Incorrect password")
+			return
+		case errors.Is(err, repo.ErrShareLinkViewLimit):
+			apitypes.RespondError(c, http.StatusForbidden, "This link has reached its view limit")
+			return
+		case err != nil:
+			apitypes.RespondError(c, http.StatusNotFound, "Share link not found or expired")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"grant": grant}, "Share link opened successfully")
+	}
+}
+
+func streamSharedVideo(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, video := requireShareGrant(c, repoMgr)
+		if link == nil {
+			return
+		}
+
+		videoPath, err := repoMgr.GetVideoPathByID(link.VideoID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, ErrVideoFileNotFound)
+			return
+		}
+
+		if link.IsClip() {
+			start, duration := shareClipRange(link, video)
+			streamTrimmedVideo(c, videoPath, start, duration, "")
+			return
+		}
+		serveVideoFile(c, videoPath)
+	}
+}
+
+func downloadSharedVideo(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, video := requireShareGrant(c, repoMgr)
+		if link == nil {
+			return
+		}
+		if !link.AllowDownload {
+			apitypes.RespondError(c, http.StatusForbidden, "This link does not allow downloads")
+			return
+		}
+
+		videoPath, err := repoMgr.GetVideoPathByID(link.VideoID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, ErrVideoFileNotFound)
+			return
+		}
+
+		countDownload(c, repoMgr, link.VideoID)
+
+		if link.IsClip() {
+			start, duration := shareClipRange(link, video)
+			streamTrimmedVideo(c, videoPath, start, duration, fmt.Sprintf("%s_clip.mp4", video.Title))
+			return
+		}
+
+		if _, err := os.Stat(videoPath); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, ErrVideoFileNotFound)
+			return
+		}
+		c.FileAttachment(videoPath, video.Title+".mp4")
+	}
+}
+
+func getSharedThumbnail(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, _ := requireShareLink(c, repoMgr)
+		if link == nil {
+			return
+		}
+		// Password-protected links reveal nothing until they are unlocked
+		if link.HasPassword() && !repoMgr.VerifyShareGrant(link, c.Query("grant")) {
+			apitypes.RespondError(c, http.StatusUnauthorized, "Share link is locked")
+			return
+		}
+
+		thumbnailPath := repoMgr.GetThumbnailFilePathByVideoID(link.VideoID)
+		if _, err := os.Stat(thumbnailPath); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "Thumbnail not found")
+			return
+		}
+		c.File(thumbnailPath)
+	}
+}
+
+// requireShareLink answers 404 and returns nil when the token is not a usable share link.
+func requireShareLink(c *gin.Context, repoMgr *repo.RepoManager) (*datatypes.ShareLinkData, *datatypes.VideoData) {
+	link, err := repoMgr.ResolveShareLink(c.Param("token"))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, "Share link not found or expired")
+		return nil, nil
+	}
+	video, err := repoMgr.GetVideoByID(link.VideoID)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
+		return nil, nil
+	}
+	return link, video
+}
+
+// requireShareGrant is requireShareLink that also needs the grant returned by /open.
+func requireShareGrant(c *gin.Context, repoMgr *repo.RepoManager) (*datatypes.ShareLinkData, *datatypes.VideoData) {
+	link, video := requireShareLink(c, repoMgr)
+	if link == nil {
+		return nil, nil
+	}
+	if !repoMgr.VerifyShareGrant(link, c.Query("grant")) {
+		apitypes.RespondError(c, http.StatusUnauthorized, "Open the share link first")
+		return nil, nil
+	}
+	return link, video
+}
+
+// shareClipRange returns the start and duration of a clip link.
+// Links without an end play to the end of the video.
+func shareClipRange(link *datatypes.ShareLinkData, video *datatypes.VideoData) (float64, float64) {
+	if link.EndSec <= 0 {
+		duration := float64(video.Codecs.DurationSec) - link.StartSec
+		if duration <= 0 {
+			duration = 24 * 60 * 60 // unknown length, ffmpeg stops at the end of the file
+		}
+		return link.StartSec, duration
+	}
+	return link.StartSec, link.EndSec - link.StartSec
+}
+
+func newShareLinkResponse(link *datatypes.ShareLinkData, token string) ShareLinkResponse {
+	return ShareLinkResponse{
+		ID:               link.ID,
+		VideoID:          link.VideoID,
+		CreatedBy:        link.CreatedBy,
+		CreatedAt:        link.CreatedAt,
+		ExpiresAt:        link.ExpiresAt,
+		StartSec:         link.StartSec,
+		EndSec:           link.EndSec,
+		AllowDownload:    link.AllowDownload,
+		RequiresPassword: link.HasPassword(),
+		MaxViews:         link.MaxViews,
+		Views:            link.Views,
+		Revoked:          link.Revoked,
+		Token:            token,
+		URL:              "/share/" + token,
+	}
+}
+
+// sharePlayerPage is served for /share/:token. It reads the token from its own URL,
+// asks for a password when needed, opens the link and plays the video.
+const sharePlayerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Shared video</title>
+<style>
+  body { margin: 0; background: #111; color: #eee; font-family: system-ui, sans-serif; }
+  main { max-width: 960px; margin: 0 auto; padding: 24px; }
+  video { width: 100%; background: #000; border-radius: 6px; }
+  form, #error { margin-top: 16px; }
+  input, button, a.button { font: inherit; padding: 8px 12px; border-radius: 4px; border: 1px solid #444; }
+  button, a.button { background: #2d6cdf; color: #fff; border: none; cursor: pointer; text-decoration: none; }
+  #error { color: #f66; }
+  [hidden] { display: none !important; }
+</style>
+</head>
+<body>
+<main>
+  <h1 id="title">Shared video</h1>
+  <p id="meta"></p>
+  <form id="unlock" hidden>
+    <input id="password" type="password" placeholder="Password" autocomplete="off" required>
+    <button type="submit">Watch</button>
+  </form>
+  <video id="player" controls playsinline hidden></video>
+  <p><a id="download" class="button" hidden>Download</a></p>
+  <p id="error" hidden></p>
+</main>
+<script>
+(function () {
+  var token = decodeURIComponent(location.pathname.split("/").filter(Boolean).pop() || "");
+  var base = "/api/v1/share/" + encodeURIComponent(token);
+  var $ = function (id) { return document.getElementById(id); };
+
+  function fail(message) {
+    $("error").textContent = message;
+    $("error").hidden = false;
+  }
+
+  function request(method, url, body) {
+    return fetch(url, {
+      method: method,
+      headers: body ? { "Content-Type": "application/json" } : {},
+      body: body ? JSON.stringify(body) : undefined
+    }).then(function (res) {
+      return res.json().then(function (json) {
+        if (!res.ok) { throw new Error((json.error && json.error.message) || "Request failed"); }
+        return json.data;
+      });
+    });
+  }
+
+  function show(info) {
+    if (info.title) {
+      document.title = info.title;
+      $("title").textContent = info.title;
+    }
+    $("meta").textContent = "Link expires " + new Date(info.expiresAt).toLocaleString();
+  }
+
+  // A locked link only has its title and settings in the info asked for with the grant
+  function open(password) {
+    return request("POST", base + "/open", { password: password || "" }).then(function (data) {
+      var grant = "?grant=" + encodeURIComponent(data.grant);
+      return request("GET", base + grant).then(function (info) {
+        show(info);
+        $("unlock").hidden = true;
+        $("error").hidden = true;
+        $("player").poster = base + "/thumbnail" + grant;
+        $("player").src = base + "/stream" + grant;
+        $("player").hidden = false;
+        if (info.allowDownload) {
+          $("download").href = base + "/download" + grant;
+          $("download").hidden = false;
+        }
+      });
+    });
+  }
+
+  request("GET", base).then(function (info) {
+    show(info);
+
+    if (!info.requiresPassword) {
+      return open().catch(function (err) { fail(err.message); });
+    }
+    $("unlock").hidden = false;
+    $("unlock").addEventListener("submit", function (e) {
+      e.preventDefault();
+      open($("password").value).catch(function (err) { fail(err.message); });
+    });
+  }).catch(function (err) { fail(err.message); });
+})();
+</script>
+</body>
+</html>
+`
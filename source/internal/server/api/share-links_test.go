@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+// newShareLinkFixture is the access fixture with the share routes mounted the way serve does.
+func newShareLinkFixture(t *testing.T) *accessFixture {
+	t.Helper()
+	f := newAccessFixture(t)
+	RegisterShareLinkRoutes(f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil)), f.repoMgr)
+	RegisterPublicShareRoutes(f.router.Group("/api/v1"), f.repoMgr)
+	return f
+}
+
+// post performs a JSON POST as username; an empty username sends no session.
+func (f *accessFixture) post(username, target string, body interface{}, remoteAddr string) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	if remoteAddr != "" {
+		req.RemoteAddr = remoteAddr
+	}
+	if username != "" {
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: f.sessions[username]})
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func (f *accessFixture) createShareLink(t *testing.T, password string) ShareLinkResponse {
+	t.Helper()
+	w := f.post("root", "/api/v1/videos/pubvid/share", CreateShareLinkRequest{Password: password}, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create share link: got %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Data ShareLinkResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("create share link: %v", err)
+	}
+	return body.Data
+}
+
+func TestShareLinkPasswordGuessesAreThrottled(t *testing.T) {
+	f := newShareLinkFixture(t)
+	link := f.createShareLink(t, "hunter2")
+	open := "/api/v1/share/" + link.Token + "/open"
+
+	// A link allows 3 free wrong guesses, the 4th starts the backoff. The guesses come from
+	// changing IPs, so only the link's own counter can stop them.
+	var w *httptest.ResponseRecorder
+	for i := 1; i <= 4; i++ {
+		w = f.post("", open, OpenShareLinkRequest{Password: "wrong"}, fmt.Sprintf("192.0.2.%d:1234", i))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: got %d, want 401: %s", i, w.Code, w.Body.String())
+		}
+	}
+
+	w = f.post("", open, OpenShareLinkRequest{Password: "hunter2"}, "192.0.2.100:1234")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("correct password while throttled: got %d, want 429: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Errorf("throttled response has no Retry-After header")
+	}
+
+	// Other links are not affected by the throttled one
+	other := f.createShareLink(t, "swordfish")
+	w = f.post("", "/api/v1/share/"+other.Token+"/open", OpenShareLinkRequest{Password: "swordfish"}, "192.0.2.100:1234")
+	if w.Code != http.StatusOK {
+		t.Fatalf("open another link: got %d, want 200: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestShareLinkAuditHidesToken(t *testing.T) {
+	f := newShareLinkFixture(t)
+	link := f.createShareLink(t, "")
+
+	events, err := f.repoMgr.QueryAuditEvents(datatypes.AuditFilter{Action: datatypes.AuditShareCreate})
+	if err != nil {
+		t.Fatalf("query audit events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d share.create events, want 1", len(events))
+	}
+	after := string(events[0].After)
+	if strings.Contains(after, link.Token) {
+		t.Errorf("audit snapshot contains the token: %s", after)
+	}
+	if !strings.Contains(after, link.ID) {
+		t.Errorf("audit snapshot does not name the link: %s", after)
+	}
+}
+
+func TestLockedShareLinkInfoHidesVideo(t *testing.T) {
+	f := newShareLinkFixture(t)
+	link := f.createShareLink(t, "hunter2")
+	info := "/api/v1/share/" + link.Token
+
+	var locked map[string]interface{}
+	f.getData(t, "", info, &locked)
+	if len(locked) != 2 || locked["requiresPassword"] != true || locked["expiresAt"] == nil {
+		t.Errorf("locked link info: got %v, want only requiresPassword and expiresAt", locked)
+	}
+	f.getData(t, "", info+"?grant=forged", &locked)
+	if _, ok := locked["title"]; ok {
+		t.Errorf("forged grant got the title: %v", locked)
+	}
+
+	w := f.post("", info+"/open", OpenShareLinkRequest{Password: "hunter2"}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("open: got %d: %s", w.Code, w.Body.String())
+	}
+	var opened struct {
+		Data struct {
+			Grant string `json:"grant"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &opened); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	var unlocked ShareLinkInfo
+	f.getData(t, "", info+"?grant="+url.QueryEscape(opened.Data.Grant), &unlocked)
+	if unlocked.Title == "" || !unlocked.RequiresPassword {
+		t.Errorf("unlocked link info: got %+v, want the title", unlocked)
+	}
+}
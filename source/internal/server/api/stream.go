@@ -21,6 +21,9 @@ func RegisterStreamRoutes(rg *gin.RouterGroup, repoManager *repo.RepoManager) {
 func streamVideo(repoManager *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, repoManager, videoId) == nil {
+			return
+		}
 
 		videoPath, err := repoManager.GetVideoPathByID(videoId)
 		if err != nil {
@@ -28,29 +31,34 @@ func streamVideo(repoManager *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
-		file, err := os.Open(videoPath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				apitypes.RespondError(c, http.StatusNotFound, "Video file not found on disk")
-			} else {
-				apitypes.RespondError(c, http.StatusInternalServerError, "Error accessing video file")
-			}
-			return
-		}
-		defer file.Close()
+		serveVideoFile(c, videoPath)
+	}
+}
 
-		fi, err := file.Stat()
-		if err != nil {
-			apitypes.RespondError(c, http.StatusInternalServerError, "Unable to get file info")
-			return
+// serveVideoFile streams a video file with HTTP range support.
+func serveVideoFile(c *gin.Context, videoPath string) {
+	file, err := os.Open(videoPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			apitypes.RespondError(c, http.StatusNotFound, "Video file not found on disk")
+		} else {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Error accessing video file")
 		}
+		return
+	}
+	defer file.Close()
 
-		// Explicit headers
-		c.Header("Content-Type", "video/mp4") // or infer from file if needed
-		c.Header("Accept-Ranges", "bytes")
-		c.Header("Content-Length", fmt.Sprintf("%d", fi.Size()))
-
-		// Serve with range support
-		http.ServeContent(c.Writer, c.Request, videoPath, fi.ModTime(), file)
+	fi, err := file.Stat()
+	if err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, "Unable to get file info")
+		return
 	}
+
+	// Explicit headers
+	c.Header("Content-Type", "video/mp4") // or infer from file if needed
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("Content-Length", fmt.Sprintf("%d", fi.Size()))
+
+	// Serve with range support
+	http.ServeContent(c.Writer, c.Request, videoPath, fi.ModTime(), file)
 }
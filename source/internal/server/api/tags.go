@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 
@@ -35,9 +36,8 @@ func getVideoTags(repo *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoID := strings.TrimSpace(c.Param("videoID"))
 
-		video, err := repo.GetVideoByID(videoID)
-		if err != nil {
-			apitypes.RespondError(c, http.StatusNotFound, "Video not found")
+		video := requireVisibleVideo(c, repo, videoID)
+		if video == nil {
 			return
 		}
 
@@ -56,6 +56,12 @@ func addVideoTag(repo *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
+		before := requireVisibleVideo(c, repo, videoID)
+		if before == nil {
+			return
+		}
+		tagsBefore := before.Tags
+
 		if err := repo.AddTagToVideo(videoID, req.Tag); err != nil {
 			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to add tag")
 			return
@@ -67,6 +73,7 @@ func addVideoTag(repo *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
+		recordAudit(c, repo, datatypes.AuditTagAdd, []string{videoID}, gin.H{"tags": tagsBefore}, gin.H{"tags": video.Tags})
 		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"tags": video.Tags}, "Tag added successfully")
 	}
 }
@@ -82,6 +89,12 @@ func removeVideoTag(repo *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
+		before := requireVisibleVideo(c, repo, videoID)
+		if before == nil {
+			return
+		}
+		tagsBefore := before.Tags
+
 		if err := repo.RemoveTagFromVideo(videoID, req.Tag); err != nil {
 			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to remove tag")
 			return
@@ -93,6 +106,7 @@ func removeVideoTag(repo *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
+		recordAudit(c, repo, datatypes.AuditTagRemove, []string{videoID}, gin.H{"tags": tagsBefore}, gin.H{"tags": video.Tags})
 		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"tags": video.Tags}, "Tag removed successfully")
 	}
 }
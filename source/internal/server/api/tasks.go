@@ -0,0 +1,220 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminTaskRoutes sets up /admin/tasks for operators to follow and steer background tasks,
+// and /admin/workers to see the remote workers running them.
+func RegisterAdminTaskRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	admin := rg.Group("/admin/tasks", SessionOnlyMiddleware(), AdminOnlyMiddleware(repoMgr))
+	{
+		admin.GET("", listTasks(repoMgr, false))                 // GET /api/v1/admin/tasks?status=&type=&videoId=&account=&page=
+		admin.GET("/summary", getTaskSummary(repoMgr))           // GET /api/v1/admin/tasks/summary
+		admin.GET("/:taskId", getTask(repoMgr, false))           // GET /api/v1/admin/tasks/:taskId
+		admin.GET("/:taskId/logs", getTaskLogs(repoMgr))         // GET /api/v1/admin/tasks/:taskId/logs
+		admin.POST("/:taskId/cancel", cancelTask(repoMgr))       // POST /api/v1/admin/tasks/:taskId/cancel
+		admin.POST("/:taskId/retry", retryTask(repoMgr))         // POST /api/v1/admin/tasks/:taskId/retry
+		admin.PUT("/:taskId/priority", setTaskPriority(repoMgr)) // PUT /api/v1/admin/tasks/:taskId/priority
+	}
+	rg.GET("/admin/workers", SessionOnlyMiddleware(), AdminOnlyMiddleware(repoMgr), listRemoteWorkers(repoMgr)) // GET /api/v1/admin/workers
+}
+
+// RegisterUserTaskRoutes sets up /me/tasks for users to follow the processing of their uploads.
+func RegisterUserTaskRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	rg.GET("/me/tasks", listTasks(repoMgr, true))       // GET /api/v1/me/tasks?status=&type=&videoId=&page=
+	rg.GET("/me/tasks/:taskId", getTask(repoMgr, true)) // GET /api/v1/me/tasks/:taskId
+}
+
+// listTasks pages through tasks, newest first and without their logs. With own only the
+// caller's tasks are listed, as TaskData.ForOwner shows them.
+func listTasks(repoMgr *repo.RepoManager, own bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := datatypes.TaskFilter{
+			Status:    datatypes.TaskStatus(strings.ToUpper(c.Query("status"))),
+			Type:      datatypes.TaskType(strings.ToUpper(c.Query("type"))),
+			VideoID:   c.Query("videoId"),
+			AccountID: c.Query("account"),
+		}
+		if own {
+			filter.AccountID = c.GetString("accountId")
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid page parameter")
+			return
+		}
+		pageSize := repoMgr.GetConfigs().MaxBucketSize
+
+		tasks, err := repoMgr.QueryTasks(filter)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to load tasks")
+			return
+		}
+
+		start := min((page-1)*pageSize, len(tasks))
+		end := min(start+pageSize, len(tasks))
+		pageTasks := tasks[start:end]
+		for i := range pageTasks {
+			pageTasks[i].Logs = nil
+			if own {
+				pageTasks[i] = pageTasks[i].ForOwner()
+			}
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"tasks":       pageTasks,
+			"currentPage": page,
+			"pageSize":    pageSize,
+			"totalItems":  len(tasks),
+			"totalPages":  (len(tasks) + pageSize - 1) / pageSize,
+			"hasNextPage": end < len(tasks),
+		}, "Tasks retrieved successfully")
+	}
+}
+
+// getTaskSummary counts the tasks per status and per type.
+func getTaskSummary(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, err := repoMgr.GetTasks()
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to load tasks")
+			return
+		}
+
+		byStatus := map[datatypes.TaskStatus]int{}
+		byType := map[datatypes.TaskType]map[datatypes.TaskStatus]int{}
+		for _, task := range tasks {
+			byStatus[task.Status]++
+			if byType[task.Type] == nil {
+				byType[task.Type] = map[datatypes.TaskStatus]int{}
+			}
+			byType[task.Type][task.Status]++
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"total":    len(tasks),
+			"byStatus": byStatus,
+			"byType":   byType,
+		}, "Task summary retrieved successfully")
+	}
+}
+
+// findTask loads the task named in the URL, answering 404 when it does not exist or, with
+// own, belongs to someone else.
+func findTask(c *gin.Context, repoMgr *repo.RepoManager, own bool) *datatypes.TaskData {
+	task, err := repoMgr.GetTask(c.Param("taskId"))
+	if err != nil || (own && task.AccountID != c.GetString("accountId")) {
+		apitypes.RespondError(c, http.StatusNotFound, "Task not found")
+		return nil
+	}
+	return task
+}
+
+func getTask(repoMgr *repo.RepoManager, own bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		task := findTask(c, repoMgr, own)
+		if task == nil {
+			return
+		}
+		if own {
+			apitypes.RespondSuccess(c, http.StatusOK, task.ForOwner(), "Task retrieved successfully")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, task, "Task retrieved successfully")
+	}
+}
+
+func getTaskLogs(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		task := findTask(c, repoMgr, false)
+		if task == nil {
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"taskId": task.ID,
+			"status": task.Status,
+			"error":  task.Error,
+			"logs":   task.Logs,
+		}, "Task logs retrieved successfully")
+	}
+}
+
+func cancelTask(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		before := findTask(c, repoMgr, false)
+		if before == nil {
+			return
+		}
+
+		task, err := repoMgr.CancelTask(before.ID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditTaskCancel, []string{task.ID}, before.Status, task.Status)
+		apitypes.RespondSuccess(c, http.StatusOK, task, "Task cancelled")
+	}
+}
+
+func retryTask(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		before := findTask(c, repoMgr, false)
+		if before == nil {
+			return
+		}
+
+		task, err := repoMgr.RetryTask(before.ID)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditTaskRetry, []string{task.ID}, before.Status, task.Status)
+		apitypes.RespondSuccess(c, http.StatusOK, task, "Task queued again")
+	}
+}
+
+func setTaskPriority(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Priority *int `json:"priority" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || body.Priority == nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "priority is required")
+			return
+		}
+
+		before := findTask(c, repoMgr, false)
+		if before == nil {
+			return
+		}
+
+		task, err := repoMgr.SetTaskPriority(before.ID, *body.Priority)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditTaskPriority, []string{task.ID}, before.Priority, task.Priority)
+		apitypes.RespondSuccess(c, http.StatusOK, task, "Task priority changed")
+	}
+}
+
+// listRemoteWorkers lists the remote workers connected over gRPC and the tasks they hold.
+func listRemoteWorkers(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workers, err := repoMgr.GetRemoteWorkers()
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to load workers")
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"workers": workers}, "Workers retrieved successfully")
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+
+	"ova-cli/source/internal/datatypes"
+)
+
+func TestOwnTasksHideServerDetails(t *testing.T) {
+	f := newAccessFixture(t)
+	v1 := f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil))
+	RegisterUserTaskRoutes(v1, f.repoMgr)
+	RegisterAdminTaskRoutes(v1, f.repoMgr)
+
+	task, err := datatypes.NewTaskData(datatypes.TaskIndexing, datatypes.TaskPriorityNormal)
+	if err != nil {
+		t.Fatalf("new task: %v", err)
+	}
+	task.VideoPath = "/srv/videos/bob/clip.mp4"
+	task.AccountID = f.accountID(t, "bob")
+	if _, err := f.repoMgr.EnqueueTask(task); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	var list struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+	}
+	f.getData(t, "bob", "/api/v1/me/tasks", &list)
+	if len(list.Tasks) != 1 {
+		t.Fatalf("bob has %d tasks, want 1", len(list.Tasks))
+	}
+	var own map[string]interface{}
+	f.getData(t, "bob", "/api/v1/me/tasks/"+task.ID, &own)
+	for _, got := range []map[string]interface{}{list.Tasks[0], own} {
+		if got["id"] != task.ID {
+			t.Errorf("got task %v, want %s", got["id"], task.ID)
+		}
+		for _, field := range []string{"videoPath", "worker"} {
+			if _, ok := got[field]; ok {
+				t.Errorf("bob sees %s: %v", field, got[field])
+			}
+		}
+		if got["logs"] != nil {
+			t.Errorf("bob sees the logs: %v", got["logs"])
+		}
+	}
+
+	var admin datatypes.TaskData
+	f.getData(t, "root", "/api/v1/admin/tasks/"+task.ID, &admin)
+	if admin.VideoPath != task.VideoPath || len(admin.Logs) == 0 {
+		t.Errorf("admin sees path %q and %d log lines, want the full task", admin.VideoPath, len(admin.Logs))
+	}
+}
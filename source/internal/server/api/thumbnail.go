@@ -19,6 +19,9 @@ func RegisterThumbnailRoutes(rg *gin.RouterGroup, repo *repo.RepoManager) {
 func getThumbnail(repo *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoId := c.Param("videoId")
+		if requireVisibleVideo(c, repo, videoId) == nil {
+			return
+		}
 
 		// Attempt to fetch the thumbnail path using GetThumbnailFilePathByVideoID
 		thumbnailPath := repo.GetThumbnailFilePathByVideoID(videoId)
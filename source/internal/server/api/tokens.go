@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPITokenRequest represents the body for creating a personal API token.
+type CreateAPITokenRequest struct {
+	Name          string                 `json:"name"`
+	Scopes        []datatypes.TokenScope `json:"scopes"`
+	ExpiresInDays int                    `json:"expiresInDays"` // 0 uses the default lifetime
+}
+
+// APITokenResponse is the public view of a token; the secret hash is never returned.
+type APITokenResponse struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Scopes     []datatypes.TokenScope `json:"scopes"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	ExpiresAt  time.Time              `json:"expiresAt"`
+	LastUsedAt time.Time              `json:"lastUsedAt,omitzero"`
+	Token      string                 `json:"token,omitempty"` // Only set once, on creation
+}
+
+// RegisterAPITokenRoutes sets up /me/tokens for managing personal API tokens.
+// Tokens cannot be used to manage tokens, only a logged-in session can.
+func RegisterAPITokenRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	tokens := rg.Group("/me/tokens", SessionOnlyMiddleware())
+	{
+		tokens.GET("", listAPITokens(repoMgr))              // GET /api/v1/me/tokens
+		tokens.POST("", createAPIToken(repoMgr))            // POST /api/v1/me/tokens
+		tokens.DELETE("/:tokenId", revokeAPIToken(repoMgr)) // DELETE /api/v1/me/tokens/:tokenId
+	}
+}
+
+func listAPITokens(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		tokens, err := repoMgr.GetAPITokens(accountID.(string))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to load API tokens")
+			return
+		}
+
+		response := make([]APITokenResponse, 0, len(tokens))
+		for i := range tokens {
+			response = append(response, newAPITokenResponse(&tokens[i], ""))
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, response, "API tokens retrieved successfully")
+	}
+}
+
+func createAPIToken(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		var req CreateAPITokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+			return
+		}
+		if req.ExpiresInDays < 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "expiresInDays cannot be negative")
+			return
+		}
+
+		ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+		token, plain, err := repoMgr.CreateAPIToken(accountID.(string), req.Name, req.Scopes, ttl)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		recordAudit(c, repoMgr, datatypes.AuditTokenCreate, []string{token.ID}, nil, newAPITokenResponse(token, ""))
+		apitypes.RespondSuccess(c, http.StatusCreated, newAPITokenResponse(token, plain), "API token created, copy it now as it will not be shown again")
+	}
+}
+
+func revokeAPIToken(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, exists := c.Get("accountId")
+		if !exists {
+			apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+			return
+		}
+
+		if err := repoMgr.RevokeAPIToken(accountID.(string), c.Param("tokenId")); err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, "API token not found")
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditTokenRevoke, []string{c.Param("tokenId")}, nil, nil)
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{"tokenId": c.Param("tokenId")}, "API token revoked")
+	}
+}
+
+func newAPITokenResponse(token *datatypes.APITokenData, plain string) APITokenResponse {
+	return APITokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		Token:      plain,
+	}
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"net/http"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LoginTwoFactorRequest completes a login that returned twoFactorRequired.
+// Either Code or RecoveryCode must be set.
+type LoginTwoFactorRequest struct {
+	ChallengeID  string `json:"challengeId"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// TwoFactorCodeRequest carries a TOTP code, and the password where the action needs it.
+type TwoFactorCodeRequest struct {
+	Code     string `json:"code"`
+	Password string `json:"password"`
+}
+
+// loginTwoFactorHandler verifies the second factor of a pending login and issues the session.
+func loginTwoFactorHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	var req LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	accountID, username, err := repoMgr.GetTwoFactorChallenge(req.ChallengeID)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusUnauthorized, "Login challenge not found or expired")
+		return
+	}
+
+	clientIP := c.ClientIP()
+	wait, err := repoMgr.BeginLoginAttempt(username, clientIP)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, "Failed to check login attempts")
+		return
+	}
+	if wait > 0 {
+		respondLoginThrottled(c, wait)
+		return
+	}
+
+	if req.RecoveryCode != "" {
+		err = repoMgr.UseRecoveryCode(accountID, req.RecoveryCode)
+	} else {
+		err = repoMgr.VerifyTwoFactorCode(accountID, req.Code)
+	}
+	if err != nil {
+		repoMgr.FailTwoFactorChallenge(req.ChallengeID)
+		wait, regErr := repoMgr.RegisterLoginFailure(username, clientIP, "invalid two-factor code")
+		if regErr == nil && wait > 0 {
+			respondLoginThrottled(c, wait)
+			return
+		}
+		apitypes.RespondError(c, http.StatusUnauthorized, "Invalid two-factor code")
+		return
+	}
+
+	repoMgr.DeleteTwoFactorChallenge(req.ChallengeID)
+	repoMgr.RefundLoginAttempt(clientIP)
+
+	user, err := repoMgr.GetUserByAccountID(accountID)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusUnauthorized, "User not found")
+		return
+	}
+	startSession(c, repoMgr, user, clientIP)
+}
+
+func twoFactorStatusHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	accountID, exists := c.Get("accountId")
+	if !exists {
+		apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+		return
+	}
+
+	user, err := repoMgr.GetUserByAccountID(accountID.(string))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	remaining := 0
+	if user.IsTwoFactorEnabled() {
+		remaining = len(user.TwoFactor.RecoveryCodeHashes)
+	}
+
+	apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+		"enabled":                user.IsTwoFactorEnabled(),
+		"required":               repoMgr.IsTwoFactorRequired(user),
+		"recoveryCodesRemaining": remaining,
+	}, "Two-factor status retrieved")
+}
+
+// twoFactorSetupHandler starts enrollment and returns the secret and otpauth:// URI for a QR code.
+func twoFactorSetupHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	accountID, exists := c.Get("accountId")
+	if !exists {
+		apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+		return
+	}
+
+	secret, uri, err := repoMgr.BeginTwoFactorSetup(accountID.(string))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusConflict, err.Error())
+		return
+	}
+
+	apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+		"secret":          secret,
+		"provisioningUri": uri,
+	}, "Scan the provisioning URI with an authenticator app, then confirm with a code")
+}
+
+func twoFactorEnableHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	accountID, exists := c.Get("accountId")
+	if !exists {
+		apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	codes, err := repoMgr.EnableTwoFactor(accountID.(string), req.Code)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	recordAudit(c, repoMgr, datatypes.AuditTwoFactorEnable, []string{accountID.(string)}, nil, nil)
+	apitypes.RespondSuccess(c, http.StatusOK, gin.H{"recoveryCodes": codes}, "Two-factor authentication enabled, store the recovery codes safely")
+}
+
+// twoFactorDisableHandler turns TOTP off after checking both the password and a current code.
+func twoFactorDisableHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	accountID, exists := c.Get("accountId")
+	if !exists {
+		apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	user, err := repoMgr.GetUserByAccountID(accountID.(string))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+	if repoMgr.IsTwoFactorRequired(user) {
+		apitypes.RespondError(c, http.StatusForbidden, "Two-factor authentication is required for your role")
+		return
+	}
+	if !beginGuardedCheck(c, repoMgr, user) {
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		failGuardedCheck(c, repoMgr, user, "invalid password", "Invalid password")
+		return
+	}
+	if err := repoMgr.VerifyTwoFactorCode(user.AccountID, req.Code); err != nil {
+		failGuardedCheck(c, repoMgr, user, "invalid two-factor code", "Invalid two-factor code")
+		return
+	}
+	passGuardedCheck(c, repoMgr, user)
+
+	if err := repoMgr.DisableTwoFactor(user.AccountID); err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	recordAudit(c, repoMgr, datatypes.AuditTwoFactorDisable, []string{user.AccountID}, nil, nil)
+	apitypes.RespondSuccess(c, http.StatusOK, gin.H{"enabled": false}, "Two-factor authentication disabled")
+}
+
+func twoFactorRecoveryCodesHandler(c *gin.Context, repoMgr *repo.RepoManager) {
+	accountID, exists := c.Get("accountId")
+	if !exists {
+		apitypes.RespondError(c, http.StatusUnauthorized, ErrAccountIDNotFound)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apitypes.RespondError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	user, err := repoMgr.GetUserByAccountID(accountID.(string))
+	if err != nil {
+		apitypes.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+	if !beginGuardedCheck(c, repoMgr, user) {
+		return
+	}
+	if err := repoMgr.VerifyTwoFactorCode(user.AccountID, req.Code); err != nil {
+		failGuardedCheck(c, repoMgr, user, "invalid two-factor code", "Invalid two-factor code")
+		return
+	}
+	passGuardedCheck(c, repoMgr, user)
+
+	codes, err := repoMgr.RegenerateRecoveryCodes(user.AccountID)
+	if err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	apitypes.RespondSuccess(c, http.StatusOK, gin.H{"recoveryCodes": codes}, "Recovery codes regenerated")
+}
+
+// beginGuardedCheck admits a password or code check on the caller's own account through the
+// login throttle, so a session alone cannot guess codes or passwords at full speed. It
+// answers the request and returns false when the account or client IP has to wait.
+func beginGuardedCheck(c *gin.Context, repoMgr *repo.RepoManager, user *datatypes.UserData) bool {
+	wait, err := repoMgr.BeginLoginAttempt(user.Username, c.ClientIP())
+	if err != nil {
+		apitypes.RespondError(c, http.StatusInternalServerError, "Failed to check login attempts")
+		return false
+	}
+	if wait > 0 {
+		respondLoginThrottled(c, wait)
+		return false
+	}
+	return true
+}
+
+// failGuardedCheck records a failed check admitted by beginGuardedCheck and answers it.
+func failGuardedCheck(c *gin.Context, repoMgr *repo.RepoManager, user *datatypes.UserData, reason, message string) {
+	wait, err := repoMgr.RegisterLoginFailure(user.Username, c.ClientIP(), reason)
+	if err == nil && wait > 0 {
+		respondLoginThrottled(c, wait)
+		return
+	}
+	apitypes.RespondError(c, http.StatusUnauthorized, message)
+}
+
+// passGuardedCheck clears the streak once every check admitted by beginGuardedCheck passed.
+func passGuardedCheck(c *gin.Context, repoMgr *repo.RepoManager, user *datatypes.UserData) {
+	repoMgr.RefundLoginAttempt(c.ClientIP())
+	repoMgr.RegisterLoginSuccess(user.Username)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"ova-cli/source/internal/totp"
+)
+
+// newTwoFactorFixture is the access fixture with the auth routes mounted and TOTP enabled
+// for alice. It returns alice's secret.
+func newTwoFactorFixture(t *testing.T) (*accessFixture, string) {
+	t.Helper()
+	f := newAccessFixture(t)
+	RegisterAuthRoutes(f.router.Group("/api/v1", AuthMiddleware(f.repoMgr, nil, nil)), f.repoMgr)
+
+	accountID := f.accountID(t, "alice")
+	secret, _, err := f.repoMgr.BeginTwoFactorSetup(accountID)
+	if err != nil {
+		t.Fatalf("begin setup: %v", err)
+	}
+	// Enabling uses the previous step, so the current one is still fresh for the test
+	code, err := totp.CodeAt(secret, totp.Step(time.Now())-1)
+	if err != nil {
+		t.Fatalf("code: %v", err)
+	}
+	if _, err := f.repoMgr.EnableTwoFactor(accountID, code); err != nil {
+		t.Fatalf("enable two-factor: %v", err)
+	}
+	return f, secret
+}
+
+// wrongCode returns a code no step around now accepts.
+func wrongCode(t *testing.T, secret string) string {
+	t.Helper()
+	valid := map[string]bool{}
+	now := totp.Step(time.Now())
+	for step := now - totp.Skew - 1; step <= now+totp.Skew+1; step++ {
+		code, err := totp.CodeAt(secret, step)
+		if err != nil {
+			t.Fatalf("code: %v", err)
+		}
+		valid[code] = true
+	}
+	for _, code := range []string{"000000", "111111", "222222", "333333", "444444", "555555"} {
+		if !valid[code] {
+			return code
+		}
+	}
+	t.Fatalf("no wrong code found")
+	return ""
+}
+
+func TestTwoFactorCodeGuessesAreThrottled(t *testing.T) {
+	f, secret := newTwoFactorFixture(t)
+	wrong := wrongCode(t, secret)
+
+	// 3 free wrong guesses, the 4th starts the backoff
+	for i := 1; i <= 3; i++ {
+		w := f.post("alice", "/api/v1/auth/2fa/recovery-codes", TwoFactorCodeRequest{Code: wrong}, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: got %d, want 401: %s", i, w.Code, w.Body.String())
+		}
+	}
+	w := f.post("alice", "/api/v1/auth/2fa/recovery-codes", TwoFactorCodeRequest{Code: wrong}, "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("guess 4: got %d, want 429: %s", w.Code, w.Body.String())
+	}
+
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("code: %v", err)
+	}
+	if w := f.post("alice", "/api/v1/auth/2fa/recovery-codes", TwoFactorCodeRequest{Code: code}, ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("right code while throttled: got %d, want 429: %s", w.Code, w.Body.String())
+	}
+
+	// Disabling shares the counter, so it cannot be used to check passwords instead
+	req := TwoFactorCodeRequest{Code: code, Password: "alice-password"}
+	if w := f.post("alice", "/api/v1/auth/2fa/disable", req, ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("disable while throttled: got %d, want 429: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTwoFactorPasswordGuessesAreThrottled(t *testing.T) {
+	f, _ := newTwoFactorFixture(t)
+
+	for i := 1; i <= 4; i++ {
+		w := f.post("alice", "/api/v1/auth/2fa/disable", TwoFactorCodeRequest{Code: "123456", Password: "wrong"}, "")
+		want := http.StatusUnauthorized
+		if i == 4 {
+			want = http.StatusTooManyRequests
+		}
+		if w.Code != want {
+			t.Fatalf("guess %d: got %d, want %d: %s", i, w.Code, want, w.Body.String())
+		}
+	}
+}
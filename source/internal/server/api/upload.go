@@ -11,7 +11,6 @@ import (
 	apitypes "ova-cli/source/internal/server/api-types"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 func RegisterUploadRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
@@ -68,14 +67,25 @@ func uploadVideo(repoMgr *repo.RepoManager) gin.HandlerFunc {
 		}
 		log.Printf("File saved successfully to %s", savePath)
 
-		// Build minimal video metadata
-		video := datatypes.VideoData{
-			VideoID: uuid.New().String(), // You can still use UUID here for video metadata if needed
+		absPath, err := filepath.Abs(savePath)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to resolve video path")
+			return
+		}
+		videoID, err := repoMgr.GenerateVideoID(absPath)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to hash video file")
+			return
 		}
 
-		repoMgr.IndexVideo(savePath, accountIdStr)
-		repoMgr.CookOneVideo(savePath)
+		// Indexing and cooking run in the background; clients follow the task
+		task, err := repoMgr.EnqueueIndexing(absPath, accountIdStr, true, datatypes.TaskPriorityHigh)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to queue video processing")
+			return
+		}
+		recordAudit(c, repoMgr, datatypes.AuditVideoUpload, []string{videoID}, nil, task)
 
-		apitypes.RespondSuccess(c, http.StatusOK, video, "Video uploaded successfully")
+		apitypes.RespondSuccess(c, http.StatusAccepted, gin.H{"videoId": videoID, "task": task}, "Video uploaded, processing queued")
 	}
 }
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"ova-cli/source/internal/repo"
+	apitypes "ova-cli/source/internal/server/api-types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatsDays caps the days of daily statistics returned for one video.
+const maxStatsDays = 365
+
+// RegisterVideoFeedRoutes registers the global feeds. Each feed can be switched off in the
+// repository config, and then answers 404.
+func RegisterVideoFeedRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
+	videos := rg.Group("/videos")
+	{
+		videos.GET("/feeds", getFeedSettings(repoMgr))           // GET /api/v1/videos/feeds
+		videos.GET("/most-viewed", getMostViewedVideos(repoMgr)) // GET /api/v1/videos/most-viewed?page=&days=
+		videos.GET("/most-liked", getMostLikedVideos(repoMgr))   // GET /api/v1/videos/most-liked?page=&days=
+		videos.GET("/trending", getTrendingVideos(repoMgr))      // GET /api/v1/videos/trending?page=&days=
+		videos.GET("/random", getRandomVideos(repoMgr))          // GET /api/v1/videos/random?page=
+		videos.GET("/random/pick", pickRandomVideo(repoMgr))     // GET /api/v1/videos/random/pick
+		videos.GET("/:videoId/stats", getVideoStats(repoMgr))    // GET /api/v1/videos/{videoId}/stats?days=
+	}
+}
+
+// getFeedSettings tells clients which feeds are switched on and their default windows.
+func getFeedSettings(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feeds := gin.H{}
+		for _, name := range repo.FeedNames {
+			feed, _ := repoMgr.FeedConfig(name)
+			feeds[name] = feed
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, feeds, "Feed settings retrieved successfully")
+	}
+}
+
+func getMostViewedVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return videoFeedHandler(repoMgr, repo.FeedMostViewed, 0, repoMgr.GetMostViewedVideos, "Most viewed videos retrieved successfully")
+}
+
+func getMostLikedVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return videoFeedHandler(repoMgr, repo.FeedMostLiked, 0, repoMgr.GetMostLikedVideos, "Most liked videos retrieved successfully")
+}
+
+func getTrendingVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return videoFeedHandler(repoMgr, repo.FeedTrending, repo.DefaultTrendingDays, repoMgr.GetTrendingVideos, "Trending videos retrieved successfully")
+}
+
+// videoFeedHandler serves a ranked feed paginated like /videos/global. ?days= chooses the
+// window, falling back to the configured window and then to defaultDays.
+func videoFeedHandler(repoMgr *repo.RepoManager, name string, defaultDays int,
+	feed func(accountId string, days, page, limit int) ([]repo.RankedVideo, int, error), message string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireFeedEnabled(c, repoMgr, name) {
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page <= 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid page parameter")
+			return
+		}
+		// Per request, so a window configured and later removed does not stick
+		windowDays := defaultDays
+		if configured := repoMgr.FeedWindowDays(name); configured > 0 {
+			windowDays = configured
+		}
+		days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(windowDays)))
+		if err != nil || days < 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid days parameter")
+			return
+		}
+		maxPageSize := repoMgr.GetConfigs().MaxBucketSize
+
+		videos, total, err := feed(c.GetString("accountId"), days, page, maxPageSize)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve videos")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"videos":      videos,
+			"days":        days,
+			"currentPage": page,
+			"pageSize":    maxPageSize,
+			"totalItems":  total,
+			"totalPages":  (total + maxPageSize - 1) / maxPageSize,
+			"hasNextPage": page*maxPageSize < total,
+		}, message)
+	}
+}
+
+func getRandomVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireFeedEnabled(c, repoMgr, repo.FeedRandom) {
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page <= 0 {
+			apitypes.RespondError(c, http.StatusBadRequest, "Invalid page parameter")
+			return
+		}
+		maxPageSize := repoMgr.GetConfigs().MaxBucketSize
+
+		videos, total, err := repoMgr.GetRandomVideos(c.GetString("accountId"), page, maxPageSize)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve videos")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"videos":      videos,
+			"currentPage": page,
+			"pageSize":    maxPageSize,
+			"totalItems":  total,
+			"totalPages":  (total + maxPageSize - 1) / maxPageSize,
+			"hasNextPage": page*maxPageSize < total,
+		}, "Random videos retrieved successfully")
+	}
+}
+
+// pickRandomVideo backs the "surprise me" button.
+func pickRandomVideo(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireFeedEnabled(c, repoMgr, repo.FeedRandom) {
+			return
+		}
+
+		video, err := repoMgr.PickRandomVideo(c.GetString("accountId"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusNotFound, ErrVideoNotFound)
+			return
+		}
+		apitypes.RespondSuccess(c, http.StatusOK, video, "Random video picked successfully")
+	}
+}
+
+// requireFeedEnabled answers 404 and returns false when the feed is switched off.
+func requireFeedEnabled(c *gin.Context, repoMgr *repo.RepoManager, name string) bool {
+	if !repoMgr.IsFeedEnabled(name) {
+		apitypes.RespondError(c, http.StatusNotFound, "This feed is disabled")
+		return false
+	}
+	return true
+}
+
+func getVideoStats(repoMgr *repo.RepoManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		videoId := c.Param("videoId")
+		video := requireVisibleVideo(c, repoMgr, videoId)
+		if video == nil {
+			return
+		}
+
+		days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+		if err != nil || days < 1 || days > maxStatsDays {
+			apitypes.RespondError(c, http.StatusBadRequest, "days must be between 1 and 365")
+			return
+		}
+
+		daily, err := repoMgr.GetVideoDailyStats(videoId, days)
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve video stats")
+			return
+		}
+
+		likes, _, err := repoMgr.GetVideoLikes(c.GetString("accountId"))
+		if err != nil {
+			apitypes.RespondError(c, http.StatusInternalServerError, "Failed to retrieve video stats")
+			return
+		}
+
+		apitypes.RespondSuccess(c, http.StatusOK, gin.H{
+			"videoId": videoId,
+			"total":   apitypes.VideoStats{Views: video.TotalViews, Downloads: video.TotalDownloads, Likes: likes[videoId]},
+			"daily":   daily,
+		}, "Video stats retrieved successfully")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	apitypes "ova-cli/source/internal/server/api-types"
 
@@ -18,13 +19,22 @@ func RegisterVideoRoutes(rg *gin.RouterGroup, repoMgr *repo.RepoManager) {
 		videos.GET("/:videoId", getVideoByID(repoMgr))       // GET /api/v1/videos/{videoId}
 		videos.DELETE("/:videoId", deleteVideoByID(repoMgr)) // GET /api/v1/videos/{videoId}
 		videos.GET("/:videoId/similar", getSimilarVideos(repoMgr))
+		videos.PUT("/:videoId/visibility", setVideoVisibility(repoMgr)) // PUT /api/v1/videos/{videoId}/visibility
 	}
 }
 
 func deleteVideoByID(repoMgr *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoId := c.Param("videoId")
-		fmt.Printf("called")
+		before := requireVisibleVideo(c, repoMgr, videoId)
+		if before == nil {
+			return
+		}
+		if !repoMgr.CanManageVideo(c.GetString("accountId"), before) {
+			apitypes.RespondError(c, http.StatusForbidden, "Only the uploader or an admin can delete this video")
+			return
+		}
+
 		err := repoMgr.RemoveVideo(videoId)
 		if err != nil {
 			if fmt.Sprintf("%v", err) == "data storage is not initialized" {
@@ -35,6 +45,8 @@ func deleteVideoByID(repoMgr *repo.RepoManager) gin.HandlerFunc {
 			return
 		}
 
+		recordAudit(c, repoMgr, datatypes.AuditVideoDelete, []string{videoId}, before, nil)
+
 		// Respond with success
 		apitypes.RespondSuccess(c, http.StatusOK, nil, "Video deleted successfully")
 	}
@@ -46,9 +58,8 @@ func getVideoByID(repoMgr *repo.RepoManager) gin.HandlerFunc {
 		videoId := c.Param("videoId")
 
 		// Retrieve the video by ID
-		video, err := repoMgr.GetVideoByID(videoId)
-		if err != nil {
-			apitypes.RespondError(c, http.StatusNotFound, "Video not found")
+		video := requireVisibleVideo(c, repoMgr, videoId)
+		if video == nil {
 			return
 		}
 
@@ -85,7 +96,11 @@ func getSimilarVideos(repoMgr *repo.RepoManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		videoId := c.Param("videoId")
 
-		similarVideos, err := repoMgr.GetSimilarVideos(videoId)
+		if requireVisibleVideo(c, repoMgr, videoId) == nil {
+			return
+		}
+
+		similarVideos, err := repoMgr.GetSimilarVideos(c.GetString("accountId"), videoId)
 		if err != nil {
 			apitypes.RespondError(c, http.StatusNotFound, "Video not found or no similar videos")
 			return
@@ -31,7 +31,11 @@ func (s *OvaGrpcServer) Run() error {
 		return err
 	}
 
-	s.grpcServer = grpc.NewServer()
+	// Everything but SayHello requires the worker token
+	s.grpcServer = grpc.NewServer(
+		grpc.UnaryInterceptor(s.handler.UnaryAuth),
+		grpc.StreamInterceptor(s.handler.StreamAuth),
+	)
 
 	// Register the handler
 	ovaproto.RegisterOvaServiceServer(s.grpcServer, s.handler)
@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"errors"
+	"io"
+	"ova-cli/ovaproto"
+	"ova-cli/source/internal/repo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UploadArtifacts stores the chunks of output files a worker streams for its jobs.
+func (h *Handler) UploadArtifacts(stream ovaproto.OvaService_UploadArtifactsServer) error {
+	files := map[string]bool{}
+	summary := &ovaproto.UploadSummary{}
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			summary.Files = int32(len(files))
+			return stream.SendAndClose(summary)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = h.RepoManager.StoreRemoteArtifact(in.WorkerId, in.JobId, in.Name, in.Offset, in.Data)
+		if errors.Is(err, repo.ErrTaskNotLeased) {
+			return taskStatusError(err)
+		}
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		files[in.JobId+"/"+in.Name] = true
+		summary.Bytes += int64(len(in.Data))
+	}
+}
@@ -0,0 +1,55 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"ova-cli/ovaproto"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// openMethods can be called without the worker token.
+var openMethods = map[string]bool{
+	ovaproto.OvaService_SayHello_FullMethodName: true,
+}
+
+// UnaryAuth refuses calls that do not carry the worker token of the repository as
+// "authorization: Bearer <token>" metadata.
+func (h *Handler) UnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := h.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamAuth is UnaryAuth for streaming calls.
+func (h *Handler) StreamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := h.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+func (h *Handler) authorize(ctx context.Context, method string) error {
+	if openMethods[method] {
+		return nil
+	}
+
+	token := h.RepoManager.GetConfigs().WorkerToken
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "remote workers are disabled: no worker token is configured")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		given, found := strings.CutPrefix(value, "Bearer ")
+		if found && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid worker token")
+}
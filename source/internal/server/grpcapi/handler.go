@@ -1,16 +1,18 @@
 package grpcapi
 
 import (
+	"errors"
 	"ova-cli/ovaproto"
 	"ova-cli/source/internal/repo"
-	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Handler implements the OvaServiceServer interface
 type Handler struct {
 	ovaproto.UnimplementedOvaServiceServer
-	RepoManager  *repo.RepoManager
-	WorkerHealth sync.Map
+	RepoManager *repo.RepoManager
 }
 
 func NewHandler(repo *repo.RepoManager) *Handler {
@@ -18,3 +20,11 @@ func NewHandler(repo *repo.RepoManager) *Handler {
 		RepoManager: repo,
 	}
 }
+
+// taskStatusError tells a worker whether it lost the task or the server failed.
+func taskStatusError(err error) error {
+	if errors.Is(err, repo.ErrTaskNotLeased) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
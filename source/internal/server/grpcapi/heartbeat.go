@@ -2,19 +2,30 @@ package grpcapi
 
 import (
 	"context"
-	"log"
 	"ova-cli/ovaproto"
-	"time"
+	"ova-cli/source/internal/repo"
 )
 
 func (h *Handler) Heartbeat(ctx context.Context, in *ovaproto.HeartbeatRequest) (*ovaproto.HeartbeatResponse, error) {
-	// 1. Record the current time for this worker ID
-	h.WorkerHealth.Store(in.WorkerId, time.Now())
+	worker := repo.RemoteWorker{
+		ID:           in.WorkerId,
+		Hostname:     in.Hostname,
+		Capabilities: taskTypes(in.Capabilities),
+		MaxJobs:      int(in.MaxJobs),
+		ActiveJobs:   int(in.ActiveJobs),
+		CPUUsage:     float64(in.CpuUsage),
+		RAMUsage:     float64(in.RamUsage),
+	}
 
-	// 2. Log it (optional, for debugging)
-	log.Printf("[gRPC] Heartbeat received from %s (CPU: %.2f%%)", in.WorkerId, in.CpuUsage)
+	// Renew the leases of the running jobs and tell the worker which ones to drop
+	stop, err := h.RepoManager.RemoteWorkerHeartbeat(worker, in.JobIds)
+	if err != nil {
+		return nil, taskStatusError(err)
+	}
 
 	return &ovaproto.HeartbeatResponse{
-		Status: true,
+		Status:          true,
+		CancelledJobIds: stop,
+		LeaseSeconds:    leaseSeconds,
 	}, nil
 }
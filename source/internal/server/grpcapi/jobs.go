@@ -3,15 +3,70 @@ package grpcapi
 import (
 	"context"
 	"log"
+	"os"
 	"ova-cli/ovaproto"
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+	"strings"
+	"time"
 )
 
+// leaseSeconds tells workers how long a job stays theirs without a heartbeat.
+const leaseSeconds = int32(repo.RemoteTaskLease / time.Second)
+
 func (h *Handler) GetNextJob(ctx context.Context, in *ovaproto.WorkerInfo) (*ovaproto.Job, error) {
-	log.Printf("[gRPC] Worker %s requesting job", in.WorkerId)
+	worker := repo.RemoteWorker{
+		ID:           in.WorkerId,
+		Hostname:     in.Hostname,
+		Capabilities: taskTypes(in.Capabilities),
+		MaxJobs:      int(in.MaxJobs),
+		ActiveJobs:   int(in.ActiveJobs),
+		CPUUsage:     float64(in.CpuUsage),
+		RAMUsage:     float64(in.RamUsage),
+	}
+
+	task, err := h.RepoManager.LeaseRemoteTask(ctx, worker)
+	if err != nil {
+		return nil, taskStatusError(err)
+	}
+	// An empty job tells the worker to ask again later
+	if task == nil {
+		return &ovaproto.Job{}, nil
+	}
+
+	log.Printf("[gRPC] Leased task %s (%s) to worker %s", task.ID, task.Type, in.WorkerId)
+	job := &ovaproto.Job{
+		JobId:        task.ID,
+		VideoPath:    task.VideoPath,
+		TaskType:     string(task.Type),
+		Attempt:      int32(task.Attempts),
+		LeaseSeconds: leaseSeconds,
+	}
+	if len(task.VideoIDs) > 0 {
+		job.VideoId = task.VideoIDs[0]
+	}
+	if info, err := os.Stat(task.VideoPath); err == nil {
+		job.VideoSize = info.Size()
+	}
+	if _, profile, err := h.RepoManager.GetCookingProfile(task.Profile); err == nil {
+		s := profile.Storyboard
+		job.Storyboard = &ovaproto.StoryboardSettings{
+			TileWidth:   int32(s.TileWidth),
+			TileHeight:  int32(s.TileHeight),
+			Columns:     int32(s.Columns),
+			Rows:        int32(s.Rows),
+			IntervalSec: s.IntervalSec,
+			Quality:     int32(s.Quality),
+		}
+	}
+	return job, nil
+}
 
-	// You can now use h.RepoManager to query your database
-	return &ovaproto.Job{
-		JobId:     "task-101",
-		VideoPath: "/data/input.mp4",
-	}, nil
+// taskTypes reads the capabilities a worker announces.
+func taskTypes(capabilities []string) []datatypes.TaskType {
+	types := make([]datatypes.TaskType, 0, len(capabilities))
+	for _, capability := range capabilities {
+		types = append(types, datatypes.TaskType(strings.ToUpper(capability)))
+	}
+	return types
 }
@@ -1,25 +1,40 @@
 package grpcapi
 
 import (
-	"log"
+	"errors"
+	"io"
 	"ova-cli/ovaproto"
-	"time"
+	"ova-cli/source/internal/repo"
 )
 
-func (h *Handler) StreamProgress(in *ovaproto.ProgressUpdate, stream ovaproto.OvaService_StreamProgressServer) error {
-	log.Printf("[gRPC] Streaming progress for %s", in.JobId)
+// StreamProgress records the progress updates a worker streams and answers each one. A job
+// the worker lost is answered with accepted false without ending the stream, since the
+// stream may carry other jobs.
+func (h *Handler) StreamProgress(stream ovaproto.OvaService_StreamProgressServer) error {
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 
-	for i := 1; i <= 5; i++ {
-		if stream.Context().Err() != nil {
-			return stream.Context().Err()
+		ack := &ovaproto.ProgressAck{JobId: in.JobId, Accepted: true}
+		cancelled, err := h.RepoManager.ReportRemoteProgress(in.WorkerId, in.JobId, int(in.Percentage), in.Message)
+		switch {
+		case errors.Is(err, repo.ErrTaskNotLeased):
+			ack.Accepted = false
+			ack.Message = err.Error()
+		case err != nil:
+			return taskStatusError(err)
+		case cancelled:
+			ack.Cancelled = true
+			ack.Message = "cancelled"
 		}
 
-		resp := &ovaproto.StreamResponse{
-			Success:  true,
-			Progress: float32(i) * 0.2,
+		if err := stream.Send(ack); err != nil {
+			return err
 		}
-		stream.Send(resp)
-		time.Sleep(time.Second)
 	}
-	return nil
 }
@@ -0,0 +1,33 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"ova-cli/ovaproto"
+	"ova-cli/source/internal/repo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CompleteJob installs what the worker uploaded for a job and completes its task. When an
+// artifact it lists is missing nothing changes, so the worker can upload it again.
+func (h *Handler) CompleteJob(ctx context.Context, in *ovaproto.CompleteJobRequest) (*ovaproto.JobAck, error) {
+	task, err := h.RepoManager.CompleteRemoteTask(in.WorkerId, in.JobId, in.Artifacts, in.Outputs)
+	if errors.Is(err, repo.ErrArtifactMissing) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, taskStatusError(err)
+	}
+	return &ovaproto.JobAck{JobId: task.ID, Status: string(task.Status)}, nil
+}
+
+// FailJob records that a job failed on the worker.
+func (h *Handler) FailJob(ctx context.Context, in *ovaproto.FailJobRequest) (*ovaproto.JobAck, error) {
+	task, err := h.RepoManager.FailRemoteTask(in.WorkerId, in.JobId, in.Error, in.Retryable, in.Interrupted)
+	if err != nil {
+		return nil, taskStatusError(err)
+	}
+	return &ovaproto.JobAck{JobId: task.ID, Status: string(task.Status)}, nil
+}
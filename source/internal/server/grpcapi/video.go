@@ -0,0 +1,40 @@
+package grpcapi
+
+import (
+	"io"
+	"ova-cli/ovaproto"
+)
+
+// videoChunkSize is how much of a video each message of DownloadVideo carries.
+const videoChunkSize = 1 << 20
+
+// DownloadVideo streams the video file of a leased job to a worker that has no access to it.
+func (h *Handler) DownloadVideo(in *ovaproto.VideoRequest, stream ovaproto.OvaService_DownloadVideoServer) error {
+	file, err := h.RepoManager.OpenRemoteTaskVideo(in.WorkerId, in.JobId)
+	if err != nil {
+		return taskStatusError(err)
+	}
+	defer file.Close()
+
+	offset := in.Offset
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return taskStatusError(err)
+	}
+
+	buffer := make([]byte, videoChunkSize)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			if err := stream.Send(&ovaproto.VideoChunk{Offset: offset, Data: buffer[:n]}); err != nil {
+				return err
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return taskStatusError(err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo"
 	"ova-cli/source/internal/server/api"
 
@@ -31,9 +32,12 @@ func NewBackendServer(
 
 	repoManager.AuthEnabled = enableAuth
 
+	router := gin.New()
+	router.Use(api.LoggerMiddleware(), gin.Recovery())
+
 	return &OvaServer{
 		RepoManager:   repoManager,
-		router:        gin.Default(),
+		router:        router,
 		ServeFrontend: serveFrontend,
 		UseHttps:      useHttps,
 		Addr:          addr,
@@ -44,38 +48,66 @@ func (s *OvaServer) initRoutes() {
 	s.router.Use(api.CORSMiddleware())
 
 	publicPaths := map[string]bool{
-		"/api/v1/auth/login": true,
-		"/api/v1/status":     true,
+		"/api/v1/auth/login":     true,
+		"/api/v1/auth/login/2fa": true,
+		"/api/v1/auth/providers": true,
+		"/api/v1/status":         true,
 	}
 	publicPrefixes := []string{
-		"/api/v1/download/",
+		"/api/v1/auth/oidc/",
+		"/api/v1/share/",
 	}
 
 	v1 := s.router.Group("/api/v1")
 	v1.Use(api.AuthMiddleware(s.RepoManager, publicPaths, publicPrefixes))
 
-	api.RegisterAuthRoutes(v1, s.RepoManager)
-	api.RegisterUserPlaylistRoutes(v1, s.RepoManager)
-	api.RegisterUserSavedRoutes(v1, s.RepoManager)
-	api.RegisterVideoRoutes(v1, s.RepoManager)
-	api.RegisterSearchRoutes(v1, s.RepoManager)
-	api.RegisterVideoTagRoutes(v1, s.RepoManager)
-	api.RegisterStreamRoutes(v1, s.RepoManager)
-	api.RegisterDownloadRoutes(v1, s.RepoManager)
-	api.RegisterUploadRoutes(v1, s.RepoManager)
-	api.RegisterGlobalFiltersRoute(v1, s.RepoManager)
-	api.RegisterProfileRoutes(v1, s.RepoManager)
-	api.RegisterThumbnailRoutes(v1, s.RepoManager)
-	api.RegisterPreviewRoutes(v1, s.RepoManager)
-	api.RegisterUserWatchedRoutes(v1, s.RepoManager)
-	api.RegisterUserPlaylistContentRoutes(v1, s.RepoManager)
-	api.RegisterStoryboardRoutes(v1, s.RepoManager)
-	api.RegisterMarkerRoutes(v1, s.RepoManager)
-	api.RegisterLatestVideoRoute(v1, s.RepoManager)
-	api.RegisterQuickSearchRoutes(v1, s.RepoManager)
-	api.RegisterRepoRoutes(v1, s.RepoManager)
-	api.RegisterBatchRoutes(v1, s.RepoManager)
+	api.RegisterAuthRoutes(v1.Group("", api.SessionOnlyMiddleware()), s.RepoManager)
 	api.RegisterStatusRoute(v1)
+	api.RegisterPublicShareRoutes(v1, s.RepoManager)
+	api.RegisterSharePlayerPage(s.router)
+
+	// Everything below is closed to accounts that still have to enroll in 2FA.
+	enrolled := v1.Group("", api.TwoFactorPolicyMiddleware(s.RepoManager))
+	api.RegisterAPITokenRoutes(enrolled, s.RepoManager)
+	api.RegisterAdminUserRoutes(enrolled, s.RepoManager)
+	api.RegisterAdminAuditRoutes(enrolled, s.RepoManager)
+	api.RegisterAdminTaskRoutes(enrolled, s.RepoManager)
+	api.RegisterAdminCookingRoutes(enrolled, s.RepoManager)
+
+	// Route groups below are gated by API token scope. Sessions are unrestricted.
+	readOnly := enrolled.Group("", api.TokenScopeMiddleware(datatypes.TokenScopeRead, datatypes.TokenScopeRead))
+	readWrite := enrolled.Group("", api.TokenScopeMiddleware(datatypes.TokenScopeRead, datatypes.TokenScopeWrite))
+	upload := enrolled.Group("", api.TokenScopeMiddleware(datatypes.TokenScopeUpload, datatypes.TokenScopeUpload))
+
+	api.RegisterUserPlaylistRoutes(readWrite, s.RepoManager)
+	api.RegisterUserSavedRoutes(readWrite, s.RepoManager)
+	api.RegisterVideoRoutes(readWrite, s.RepoManager)
+	api.RegisterSearchRoutes(readOnly, s.RepoManager)
+	api.RegisterVideoTagRoutes(readWrite, s.RepoManager)
+	api.RegisterStreamRoutes(readOnly, s.RepoManager)
+	api.RegisterDownloadRoutes(readOnly, s.RepoManager)
+	api.RegisterUploadRoutes(upload, s.RepoManager)
+	api.RegisterUserTaskRoutes(readOnly, s.RepoManager)
+	api.RegisterGlobalFiltersRoute(readOnly, s.RepoManager)
+	api.RegisterProfileRoutes(readOnly, s.RepoManager)
+	api.RegisterThumbnailRoutes(readOnly, s.RepoManager)
+	api.RegisterPreviewRoutes(readOnly, s.RepoManager)
+	api.RegisterUserWatchedRoutes(readWrite, s.RepoManager)
+	api.RegisterPlaybackRoutes(readWrite, s.RepoManager)
+	api.RegisterUserPlaylistContentRoutes(readWrite, s.RepoManager)
+	api.RegisterPlaylistSharingRoutes(readWrite, s.RepoManager)
+	api.RegisterPlaylistEditingRoutes(readWrite, s.RepoManager)
+	api.RegisterPlaylistTransferRoutes(readWrite, s.RepoManager)
+	api.RegisterStoryboardRoutes(readOnly, s.RepoManager)
+	api.RegisterMarkerRoutes(readWrite, s.RepoManager)
+	api.RegisterLatestVideoRoute(readOnly, s.RepoManager)
+	api.RegisterVideoFeedRoutes(readOnly, s.RepoManager)
+	api.RegisterVideoLikeRoutes(readWrite, s.RepoManager)
+	api.RegisterRecommendationRoutes(readOnly, s.RepoManager)
+	api.RegisterQuickSearchRoutes(readOnly, s.RepoManager)
+	api.RegisterRepoRoutes(readOnly, s.RepoManager)
+	api.RegisterBatchRoutes(readOnly, s.RepoManager)
+	api.RegisterShareLinkRoutes(readWrite, s.RepoManager)
 
 	if s.ServeFrontend {
 		s.serveFrontendStatic()
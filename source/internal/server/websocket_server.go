@@ -3,10 +3,14 @@ package server
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
+	"ova-cli/source/internal/datatypes"
 	"ova-cli/source/internal/repo" // Ensure this path is correct
 	wstypes "ova-cli/source/internal/server/ws-types"
 
@@ -14,26 +18,55 @@ import (
 )
 
 var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for development
-	},
+	CheckOrigin: sameHostOrigin,
+}
+
+// sameHostOrigin accepts clients without an Origin, which are not browsers, and pages served
+// from the host the WebSocket server is reached on, whatever their port. Other sites cannot
+// open the feed with the session cookie of a user visiting them.
+func sameHostOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	return strings.EqualFold(u.Hostname(), host)
 }
 
 type WsServer struct {
 	Addr        string
 	RepoManager *repo.RepoManager // Added RepoManager access
-	clients     map[*websocket.Conn]bool
-	broadcast   chan interface{}
+	clients     map[*websocket.Conn]*wsClient
+	broadcast   chan wsBroadcast
 	mu          sync.Mutex
 }
 
+// wsClient is who a connection was authenticated as.
+type wsClient struct {
+	accountID string
+	fullFeed  bool // Gets every task, not only the account's own
+}
+
+// wsBroadcast is a message for the clients that to accepts, or for all of them when to is nil.
+type wsBroadcast struct {
+	msg interface{}
+	to  func(client *wsClient) bool
+}
+
 // NewWsServer now accepts the repoManager
 func NewWsServer(repoManager *repo.RepoManager, addr string) *WsServer {
 	return &WsServer{
 		Addr:        addr,
 		RepoManager: repoManager,
-		clients:     make(map[*websocket.Conn]bool),
-		broadcast:   make(chan interface{}),
+		clients:     make(map[*websocket.Conn]*wsClient),
+		broadcast:   make(chan wsBroadcast),
 	}
 }
 
@@ -43,6 +76,8 @@ func (s *WsServer) Run() error {
 
 	go s.startHeartbeat()
 
+	go s.forwardTaskUpdates()
+
 	mux := http.NewServeMux()
 
 	// WebSocket endpoint
@@ -64,20 +99,30 @@ func (s *WsServer) Run() error {
 
 func (s *WsServer) listenToBroadcast() {
 	for {
-		msg := <-s.broadcast
+		b := <-s.broadcast
 		s.mu.Lock()
-		for client := range s.clients {
-			err := client.WriteJSON(msg)
+		for conn, client := range s.clients {
+			if b.to != nil && !b.to(client) {
+				continue
+			}
+			err := conn.WriteJSON(b.msg)
 			if err != nil {
 				log.Printf("[WS] Error writing JSON: %v", err)
-				client.Close()
-				delete(s.clients, client)
+				conn.Close()
+				delete(s.clients, conn)
 			}
 		}
 		s.mu.Unlock()
 	}
 }
+
 func (s *WsServer) HandleConnections(w http.ResponseWriter, r *http.Request) {
+	client, status, message := s.authenticate(r)
+	if client == nil {
+		http.Error(w, message, status)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("[WS] Upgrade error: %v", err)
@@ -86,7 +131,7 @@ func (s *WsServer) HandleConnections(w http.ResponseWriter, r *http.Request) {
 	defer conn.Close()
 
 	s.mu.Lock()
-	s.clients[conn] = true
+	s.clients[conn] = client
 	s.mu.Unlock()
 
 	log.Printf("[WS] new connection: %s", r.RemoteAddr)
@@ -113,8 +158,45 @@ func (s *WsServer) HandleConnections(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// authenticate checks the connection request the way the REST API does: an API token with the
+// read scope in "Authorization: Bearer", or else the session cookie. Only admins logged in
+// with a session get every task, like under /api/v1/admin/tasks. Without auth everyone does.
+func (s *WsServer) authenticate(r *http.Request) (*wsClient, int, string) {
+	if !s.RepoManager.AuthEnabled {
+		return &wsClient{fullFeed: true}, 0, ""
+	}
+
+	var client *wsClient
+	if scheme, bearer, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token, err := s.RepoManager.AuthenticateAPIToken(strings.TrimSpace(bearer))
+		if err != nil {
+			return nil, http.StatusUnauthorized, "Invalid or expired API token"
+		}
+		if !token.HasScope(datatypes.TokenScopeRead) {
+			return nil, http.StatusForbidden, "API token is missing the 'read' scope"
+		}
+		client = &wsClient{accountID: token.AccountID}
+	} else {
+		cookie, err := r.Cookie("session_id")
+		if err != nil {
+			return nil, http.StatusUnauthorized, "Authentication required"
+		}
+		accountID, err := s.RepoManager.GetAccountIDBySession(cookie.Value)
+		if err != nil {
+			return nil, http.StatusUnauthorized, "Invalid session"
+		}
+		client = &wsClient{accountID: accountID, fullFeed: s.RepoManager.IsAdmin(accountID)}
+	}
+
+	if s.RepoManager.IsTwoFactorEnrollmentPending(client.accountID) {
+		return nil, http.StatusForbidden, "Two-factor authentication must be enabled for your account"
+	}
+	return client, 0, ""
+}
+
 func (s *WsServer) SendUpdate(data interface{}) {
-	s.broadcast <- data
+	s.broadcast <- wsBroadcast{msg: data}
 }
 
 func (s *WsServer) startHeartbeat() {
@@ -127,3 +209,39 @@ func (s *WsServer) startHeartbeat() {
 		s.SendUpdate(msg)
 	}
 }
+
+// forwardTaskUpdates sends every change to a background task as a "task_update" event to the
+// account that asked for the task and to the clients with the full feed. The account gets
+// the task as TaskData.ForOwner shows it. Updates are dropped rather than held up when the
+// clients fall behind, so a slow client never stalls the tasks.
+func (s *WsServer) forwardTaskUpdates() {
+	updates := make(chan repo.TaskUpdate, 256)
+	s.RepoManager.OnTaskUpdate(func(update repo.TaskUpdate) {
+		select {
+		case updates <- update:
+		default:
+		}
+	})
+
+	for update := range updates {
+		owner := update.Task.AccountID
+		message := fmt.Sprintf("Task %s %s", update.Task.ID, update.Change)
+		s.broadcast <- wsBroadcast{
+			msg: wstypes.NewWsSuccess("task_update", update, message),
+			to: func(client *wsClient) bool {
+				return client.fullFeed
+			},
+		}
+		if owner == "" {
+			continue
+		}
+		ownerUpdate := update
+		ownerUpdate.Task = update.Task.ForOwner()
+		s.broadcast <- wsBroadcast{
+			msg: wstypes.NewWsSuccess("task_update", ownerUpdate, message),
+			to: func(client *wsClient) bool {
+				return !client.fullFeed && client.accountID == owner
+			},
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/repo"
+
+	"github.com/gorilla/websocket"
+)
+
+// newWsFixture serves the WebSocket endpoint of a repository with the admin root and the users
+// alice and bob, each logged in with the session "session-<username>".
+func newWsFixture(t *testing.T) (*WsServer, string, map[string]string) {
+	t.Helper()
+	repoMgr, err := repo.NewRepoManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("create repo: %v", err)
+	}
+
+	accounts := make(map[string]string)
+	for _, username := range []string{"root", "alice", "bob"} {
+		user, err := datatypes.NewUserData(username, username+"-password")
+		if err != nil {
+			t.Fatalf("new user %s: %v", username, err)
+		}
+		if username == "root" {
+			user.Role = datatypes.RoleAdmin
+		}
+		if err := repoMgr.CreateUser(&user); err != nil {
+			t.Fatalf("create user %s: %v", username, err)
+		}
+		repoMgr.AddSession("session-"+username, user.AccountID)
+		accounts[username] = user.AccountID
+	}
+
+	s := NewWsServer(repoMgr, "")
+	go s.listenToBroadcast()
+	go s.forwardTaskUpdates()
+	server := httptest.NewServer(http.HandlerFunc(s.HandleConnections))
+	t.Cleanup(server.Close)
+	return s, "ws" + strings.TrimPrefix(server.URL, "http"), accounts
+}
+
+func dialWs(wsURL string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return conn, resp, err
+}
+
+func dialAs(t *testing.T, wsURL, username string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := dialWs(wsURL, http.Header{"Cookie": {"session_id=session-" + username}})
+	if err != nil {
+		t.Fatalf("dial as %s: %v", username, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// nextTask reads the next task update of conn and returns its task.
+func nextTask(t *testing.T, conn *websocket.Conn) datatypes.TaskData {
+	t.Helper()
+	var msg struct {
+		Event string          `json:"event"`
+		Data  repo.TaskUpdate `json:"data"`
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read task update: %v", err)
+	}
+	if msg.Event != "task_update" {
+		t.Fatalf("got event %q, want task_update", msg.Event)
+	}
+	return msg.Data.Task
+}
+
+func TestWsRequiresAuthentication(t *testing.T) {
+	_, wsURL, _ := newWsFixture(t)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"no credentials", nil, http.StatusUnauthorized},
+		{"unknown session", http.Header{"Cookie": {"session_id=nope"}}, http.StatusUnauthorized},
+		{"invalid api token", http.Header{"Authorization": {"Bearer ova_x.y"}}, http.StatusUnauthorized},
+		{"other site", http.Header{"Cookie": {"session_id=session-alice"}, "Origin": {"https://evil.example"}}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		conn, resp, err := dialWs(wsURL, tt.header)
+		if err == nil {
+			conn.Close()
+			t.Errorf("%s: connected, want %d", tt.name, tt.want)
+			continue
+		}
+		if resp == nil || resp.StatusCode != tt.want {
+			t.Errorf("%s: got %v, want %d", tt.name, resp, tt.want)
+		}
+	}
+
+	// The web app is served from the same host on another port
+	conn, _, err := dialWs(wsURL, http.Header{"Cookie": {"session_id=session-alice"}, "Origin": {"http://127.0.0.1:4200"}})
+	if err != nil {
+		t.Fatalf("dial from the same host: %v", err)
+	}
+	conn.Close()
+}
+
+func TestWsSendsTasksToTheirOwnerAndAdmins(t *testing.T) {
+	s, wsURL, accounts := newWsFixture(t)
+	root := dialAs(t, wsURL, "root")
+	alice := dialAs(t, wsURL, "alice")
+	bob := dialAs(t, wsURL, "bob")
+
+	// The connections are registered once the handlers run
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		s.mu.Lock()
+		connected := len(s.clients)
+		s.mu.Unlock()
+		if connected == 3 || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	for _, username := range []string{"alice", "bob"} {
+		task, err := datatypes.NewTaskData(datatypes.TaskIndexing, datatypes.TaskPriorityNormal)
+		if err != nil {
+			t.Fatalf("new task: %v", err)
+		}
+		task.VideoPath = username + "/clip.mp4"
+		task.AccountID = accounts[username]
+		if _, err := s.RepoManager.EnqueueTask(task); err != nil {
+			t.Fatalf("enqueue for %s: %v", username, err)
+		}
+	}
+
+	// Updates arrive in order, so bob's first one being his own means he never got alice's
+	for _, want := range []string{"alice", "bob"} {
+		if got := nextTask(t, root); got.AccountID != accounts[want] || got.VideoPath == "" {
+			t.Errorf("root got the task of %s on %q, want the one of %s with its path", got.AccountID, got.VideoPath, accounts[want])
+		}
+	}
+	// Owners do not see where the file is on the server
+	if got := nextTask(t, alice); got.AccountID != accounts["alice"] || got.VideoPath != "" {
+		t.Errorf("alice got the task of %s on %q", got.AccountID, got.VideoPath)
+	}
+	if got := nextTask(t, bob); got.AccountID != accounts["bob"] || got.VideoPath != "" {
+		t.Errorf("bob got the task of %s on %q", got.AccountID, got.VideoPath)
+	}
+}
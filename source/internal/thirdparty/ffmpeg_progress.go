@@ -0,0 +1,104 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FFmpegProgress is one report of a running ffmpeg, parsed from its -progress output.
+type FFmpegProgress struct {
+	OutTime  float64 // Seconds of the input processed so far
+	Duration float64 // Seconds ffmpeg will process, 0 when unknown
+	Speed    float64 // Times realtime, 0 until ffmpeg knows
+	FPS      float64 // Frames written per second
+	Done     bool    // ffmpeg finished writing
+}
+
+// Percent is how far ffmpeg is, from 0 to 100, or 0 when the duration is unknown.
+func (p FFmpegProgress) Percent() float64 {
+	if p.Done {
+		return 100
+	}
+	if p.Duration <= 0 {
+		return 0
+	}
+	return max(0, min(p.OutTime/p.Duration*100, 100))
+}
+
+// ProgressFunc receives the progress of an ffmpeg run, about twice a second. It is
+// called from the goroutine reading ffmpeg's output and should not block.
+type ProgressFunc func(FFmpegProgress)
+
+// runFFmpeg runs ffmpeg like runCommand. With a progress func, ffmpeg writes its progress
+// to stdout, which is parsed against duration; ffmpeg's output must then not be stdout.
+func runFFmpeg(ctx context.Context, timeout time.Duration, duration float64, progress ProgressFunc, ffmpegPath string, args ...string) (string, error) {
+	if progress == nil {
+		_, stderr, err := runCommand(ctx, timeout, ffmpegPath, args...)
+		return stderr, err
+	}
+	args = append([]string{"-progress", "pipe:1", "-nostats"}, args...)
+	parser := &progressParser{report: FFmpegProgress{Duration: duration}, progress: progress}
+	stderr, err := runCommandTo(ctx, timeout, parser, ffmpegPath, args...)
+	return stderr, err
+}
+
+// progressDuration is the length of the video at videoPath for progress reports, or 0
+// when no one listens or ffprobe cannot tell.
+func progressDuration(ctx context.Context, videoPath string, progress ProgressFunc) float64 {
+	if progress == nil {
+		return 0
+	}
+	duration, err := GetVideoDuration(ctx, videoPath)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// progressParser reads ffmpeg's key=value progress blocks. Each block ends with a
+// progress=continue or progress=end line.
+type progressParser struct {
+	pending  []byte
+	report   FFmpegProgress
+	progress ProgressFunc
+}
+
+func (p *progressParser) Write(data []byte) (int, error) {
+	p.pending = append(p.pending, data...)
+	for {
+		i := bytes.IndexByte(p.pending, '\n')
+		if i < 0 {
+			break
+		}
+		p.parseLine(strings.TrimSpace(string(p.pending[:i])))
+		p.pending = p.pending[i+1:]
+	}
+	return len(data), nil
+}
+
+func (p *progressParser) parseLine(line string) {
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return
+	}
+	switch key {
+	case "out_time_us", "out_time_ms": // both are microseconds
+		if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+			p.report.OutTime = float64(us) / 1e6
+		}
+	case "fps":
+		if fps, err := strconv.ParseFloat(value, 64); err == nil {
+			p.report.FPS = fps
+		}
+	case "speed":
+		if speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "x"), 64); err == nil {
+			p.report.Speed = speed
+		}
+	case "progress":
+		p.report.Done = value == "end"
+		p.progress(p.report)
+	}
+}
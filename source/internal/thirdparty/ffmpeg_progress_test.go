@@ -0,0 +1,89 @@
+package thirdparty
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProgressParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration float64
+		input    string
+		want     []FFmpegProgress
+	}{
+		{
+			name:     "out_time_us",
+			duration: 10,
+			input: "frame=30\nfps=29.97\nout_time_us=2500000\nspeed=2.5x\nprogress=continue\n" +
+				"out_time_us=10000000\nspeed=3x\nprogress=end\n",
+			want: []FFmpegProgress{
+				{OutTime: 2.5, Duration: 10, Speed: 2.5, FPS: 29.97},
+				{OutTime: 10, Duration: 10, Speed: 3, FPS: 29.97, Done: true},
+			},
+		},
+		{
+			name:     "out_time_ms is microseconds too",
+			duration: 4,
+			input:    "out_time_ms=1000000\nprogress=continue\n",
+			want:     []FFmpegProgress{{OutTime: 1, Duration: 4}},
+		},
+		{
+			name:  "speed N/A before ffmpeg knows",
+			input: "out_time_us=0\nspeed=N/A\nprogress=continue\nspeed= 1.5x\nprogress=continue\n",
+			want:  []FFmpegProgress{{}, {Speed: 1.5}},
+		},
+		{
+			name:  "negative out_time at the start",
+			input: "out_time_us=-9223372036854775807\nprogress=continue\n",
+			want:  []FFmpegProgress{{}},
+		},
+		{
+			name:  "CRLF line ends",
+			input: "out_time_us=500000\r\nprogress=end\r\n",
+			want:  []FFmpegProgress{{OutTime: 0.5, Done: true}},
+		},
+		{
+			name:  "unfinished block",
+			input: "out_time_us=500000\nprogress=continue\nout_time_us=900000\nprogr",
+			want:  []FFmpegProgress{{OutTime: 0.5}},
+		},
+	}
+
+	for _, tt := range tests {
+		// Pipes hand over ffmpeg's output in pieces that split lines anywhere
+		for _, chunk := range []int{1, 3, 7, len(tt.input)} {
+			var got []FFmpegProgress
+			parser := &progressParser{
+				report:   FFmpegProgress{Duration: tt.duration},
+				progress: func(p FFmpegProgress) { got = append(got, p) },
+			}
+			for start := 0; start < len(tt.input); start += chunk {
+				data := []byte(tt.input[start:min(start+chunk, len(tt.input))])
+				if n, err := parser.Write(data); n != len(data) || err != nil {
+					t.Fatalf("%s: Write returned %d, %v", tt.name, n, err)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%s in chunks of %d: got %+v, want %+v", tt.name, chunk, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFFmpegProgressPercent(t *testing.T) {
+	tests := []struct {
+		progress FFmpegProgress
+		want     float64
+	}{
+		{FFmpegProgress{OutTime: 5, Duration: 20}, 25},
+		{FFmpegProgress{OutTime: 5}, 0},
+		{FFmpegProgress{OutTime: 25, Duration: 20}, 100},
+		{FFmpegProgress{OutTime: 1, Done: true}, 100},
+	}
+	for _, tt := range tests {
+		if got := tt.progress.Percent(); got != tt.want {
+			t.Errorf("%+v: got %v, want %v", tt.progress, got, tt.want)
+		}
+	}
+}
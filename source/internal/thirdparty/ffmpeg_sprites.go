@@ -1,15 +1,13 @@
 package thirdparty
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/jpeg"
-	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -17,8 +15,10 @@ import (
 	"sync"
 )
 
-// ExtractKeyframes uses ffmpeg to extract evenly spaced keyframes (1 every 10 seconds)
-func ExtractKeyframes(videoPath, outputDir string, thumbWidth, thumbHeight int) error {
+// ExtractKeyframes uses ffmpeg to extract the keyframes of a video, or with intervalSec above
+// 0 one frame every intervalSec seconds, starting at 0. progress, when not nil, follows
+// ffmpeg through the video.
+func ExtractKeyframes(ctx context.Context, videoPath, outputDir string, thumbWidth, thumbHeight int, intervalSec float64, progress ProgressFunc) error {
 	ffmpegPath, err := GetFFmpegPath()
 	if err != nil {
 		return err
@@ -31,32 +31,38 @@ func ExtractKeyframes(videoPath, outputDir string, thumbWidth, thumbHeight int)
 
 	outputPattern := filepath.Join(outputDir, "keyframe_%04d.jpg")
 
+	scale := fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=decrease", thumbWidth, thumbHeight)
 	args := []string{
 		"-threads", "8", // Use CPU threads
 		"-skip_frame", "nokey", // Extract only keyframes
 		"-i", videoPath, // Input file
 		"-vsync", "passthrough", // Preserve timestamps
-		"-vf", fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=decrease", thumbWidth, thumbHeight),
+		"-vf", scale,
 		"-q:v", "5", // Quality parameter for JPEG
 		outputPattern, // Output filename pattern
 	}
+	if intervalSec > 0 {
+		// Every frame has to be decoded to pick evenly spaced ones
+		args = []string{
+			"-threads", "8",
+			"-i", videoPath,
+			"-vf", fmt.Sprintf("fps=1/%g:round=down,%s", intervalSec, scale),
+			"-q:v", "5",
+			outputPattern,
+		}
+	}
 
-	cmd := exec.Command(ffmpegPath, args...)
-
-	// Capture stderr to get ffmpeg error messages
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	cmd.Stdout = io.Discard
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("ffmpeg command failed: %w: %s", err, stderr.String())
+	duration := progressDuration(ctx, videoPath, progress)
+	if _, err := runFFmpeg(ctx, EncodeTimeout, duration, progress, ffmpegPath, args...); err != nil {
+		return fmt.Errorf("keyframe extraction failed: %w", err)
 	}
 
 	return nil
 }
 
 // GenerateSpriteSheetsFromFolder loads all images from keyframe folder and assembles sprite sheets
-func GenerateSpriteSheetsFromFolder(inputDir, outputPattern, tile string, thumbWidth, thumbHeight int) error {
+// with JPEG quality from 1 to 100.
+func GenerateSpriteSheetsFromFolder(inputDir, outputPattern, tile string, thumbWidth, thumbHeight, quality int) error {
 	parts := strings.Split(tile, "x")
 	if len(parts) != 2 {
 		return fmt.Errorf("invalid tile format: %s", tile)
@@ -143,7 +149,7 @@ func GenerateSpriteSheetsFromFolder(inputDir, outputPattern, tile string, thumbW
 		if err != nil {
 			return fmt.Errorf("create sprite file: %w", err)
 		}
-		err = jpeg.Encode(outFile, spriteSheet, &jpeg.Options{Quality: 75})
+		err = jpeg.Encode(outFile, spriteSheet, &jpeg.Options{Quality: quality})
 		outFile.Close()
 		if err != nil {
 			return fmt.Errorf("encode sprite file: %w", err)
@@ -1,15 +1,17 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-// GenerateImageFromVideo uses FFmpeg to create a image at a specific time position (in seconds).
-func GenerateImageFromVideo(videoPath, outputImagePath string, timePos float64) error {
+// GenerateImageFromVideo uses FFmpeg to create a image at a specific time position (in seconds),
+// scaled to width with JPEG quality from 1 to 100.
+func GenerateImageFromVideo(ctx context.Context, videoPath, outputImagePath string, timePos float64, width, quality int) error {
 	ffmpegPath, err := GetFFmpegPath()
 	if err != nil {
 		return fmt.Errorf("ffmpeg path error: %w", err)
@@ -25,29 +27,29 @@ func GenerateImageFromVideo(videoPath, outputImagePath string, timePos float64)
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	cmd := exec.Command(
+	_, stderr, err := runCommand(ctx, FrameTimeout,
 		ffmpegPath,
 		"-y",
 		"-ss", timePosStr,
 		"-i", videoPath,
 		"-frames:v", "1",
-		"-q:v", "2",
-		"-vf", "scale=320:-1",
+		"-q:v", strconv.Itoa(jpegQScale(quality)),
+		"-vf", fmt.Sprintf("scale=%d:-1", width),
 		"-pix_fmt", "yuvj420p",
 		"-f", "image2",
 		outputImagePath,
 	)
-
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
+	if ctx.Err() != nil {
+		return err
+	}
 
 	// Clean error if FFmpeg fails with empty output (likely due to overshoot)
-	if strings.Contains(outputStr, "Output file is empty") || strings.Contains(outputStr, "nothing was encoded") {
+	if strings.Contains(stderr, "Output file is empty") || strings.Contains(stderr, "nothing was encoded") {
 		return fmt.Errorf("thumbnail time exceeds video duration")
 	}
 
 	if err != nil {
-		return fmt.Errorf("ffmpeg error: %v, output: %s", err, outputStr)
+		return fmt.Errorf("ffmpeg error: %w", err)
 	}
 
 	// Verify output file
@@ -57,3 +59,9 @@ func GenerateImageFromVideo(videoPath, outputImagePath string, timePos float64)
 
 	return nil
 }
+
+// jpegQScale turns a JPEG quality from 1 to 100 into ffmpeg's -q:v scale, 31 (worst) to 2 (best).
+func jpegQScale(quality int) int {
+	quality = max(1, min(quality, 100))
+	return 31 - (quality-1)*29/99
+}
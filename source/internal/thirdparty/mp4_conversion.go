@@ -1,14 +1,15 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 )
 
-// ConvertToMP4 remuxes a video file (e.g., .ts) into an MP4 container without re-encoding
-func ConvertToMP4(inputPath, outputPath string) error {
+// ConvertToMP4 remuxes a video file (e.g., .ts) into an MP4 container without re-encoding.
+// progress, when not nil, follows ffmpeg through the input.
+func ConvertToMP4(ctx context.Context, inputPath, outputPath string, progress ProgressFunc) error {
 	ffmpegPath, err := GetFFmpegPath()
 	if err != nil {
 		return err
@@ -19,7 +20,7 @@ func ConvertToMP4(inputPath, outputPath string) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	cmd := exec.Command(
+	_, err = runFFmpeg(ctx, EncodeTimeout, progressDuration(ctx, inputPath, progress), progress,
 		ffmpegPath,
 		"-i", inputPath,
 		"-c", "copy", // remux video/audio as-is
@@ -27,9 +28,8 @@ func ConvertToMP4(inputPath, outputPath string) error {
 		outputPath,
 	)
 
-	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("ffmpeg conversion error: %v, output: %s", err, string(output))
+		return fmt.Errorf("ffmpeg conversion error: %w", err)
 	}
 
 	return nil
@@ -1,11 +1,10 @@
 package thirdparty
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
-	"os/exec"
 	"path"
 )
 
@@ -27,7 +26,7 @@ type VideoDetails struct {
 }
 
 // GetVideoDetails returns a struct containing the duration, FPS, resolution, and codec details of a video.
-func GetVideoDetails(videoPath string) (VideoDetails, error) {
+func GetVideoDetails(ctx context.Context, videoPath string) (VideoDetails, error) {
 
 	// Extract file extension (e.g., .mp4)
 	ext := path.Ext(videoPath)
@@ -39,25 +38,19 @@ func GetVideoDetails(videoPath string) (VideoDetails, error) {
 	}
 
 	// Run mp4info with the --fast option to get details in JSON format
-	cmd := exec.Command(
+	out, _, err := runCommand(ctx, ProbeTimeout,
 		mp4infoPath,
 		"--fast",           // Using the fast option for quicker analysis
 		"--format", "json", // Requesting JSON format
 		videoPath,
 	)
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return VideoDetails{}, fmt.Errorf("mp4info execution failed: %s, error: %w", stderr.String(), err)
+	if err != nil {
+		return VideoDetails{}, fmt.Errorf("mp4info execution failed: %w", err)
 	}
 
 	// Parse mp4info JSON output
 	var result map[string]interface{}
-	err = json.Unmarshal(out.Bytes(), &result)
+	err = json.Unmarshal(out, &result)
 	if err != nil {
 		return VideoDetails{}, fmt.Errorf("failed to parse mp4info output: %w", err)
 	}
@@ -113,7 +106,7 @@ func GetVideoDetails(videoPath string) (VideoDetails, error) {
 	}
 
 	// Get FPS using GetVideoFPS function
-	fps, err := GetVideoFPS(videoPath)
+	fps, err := GetVideoFPS(ctx, videoPath)
 	if err != nil {
 		return VideoDetails{}, fmt.Errorf("failed to get video FPS: %w", err)
 	}
@@ -1,21 +1,20 @@
 package thirdparty
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 )
 
 // GetVideoDuration returns the duration of a video in seconds (float64, as ffprobe can return decimals).
-func GetVideoDuration(videoPath string) (float64, error) {
+func GetVideoDuration(ctx context.Context, videoPath string) (float64, error) {
 	ffprobePath, err := GetFFprobePath()
 	if err != nil {
 		return 0, fmt.Errorf("failed to find ffprobe: %w", err) // More specific error message
 	}
 
-	cmd := exec.Command(
+	out, _, err := runCommand(ctx, ProbeTimeout,
 		ffprobePath,
 		"-v", "error",
 		"-show_entries", "format=duration",
@@ -23,17 +22,12 @@ func GetVideoDuration(videoPath string) (float64, error) {
 		videoPath,
 	)
 
-	var out bytes.Buffer
-	var stderr bytes.Buffer // Capture stderr for better error debugging
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr // Assign stderr buffer
-
-	if err := cmd.Run(); err != nil {
-		// Include stderr output in the error message for debugging
-		return 0, fmt.Errorf("ffprobe execution failed for %s: %s, error: %w", videoPath, stderr.String(), err)
+	if err != nil {
+		// runCommand already carries the ffprobe stderr tail
+		return 0, fmt.Errorf("ffprobe execution failed for %s: %w", videoPath, err)
 	}
 
-	durationStr := strings.TrimSpace(out.String())
+	durationStr := strings.TrimSpace(string(out))
 	if durationStr == "" { // Handle case where ffprobe might return empty string
 		return 0, fmt.Errorf("ffprobe returned empty duration for %s", videoPath)
 	}
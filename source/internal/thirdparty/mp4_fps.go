@@ -1,21 +1,20 @@
 package thirdparty
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 )
 
 // GetVideoFPS returns the FPS (frames per second) of a video.
-func GetVideoFPS(videoPath string) (float64, error) {
+func GetVideoFPS(ctx context.Context, videoPath string) (float64, error) {
 	ffprobePath, err := GetFFprobePath()
 	if err != nil {
 		return 0, err
 	}
 
-	cmd := exec.Command(
+	out, _, err := runCommand(ctx, ProbeTimeout,
 		ffprobePath,
 		"-v", "error",
 		"-select_streams", "v:0", // Select the first video stream
@@ -24,15 +23,12 @@ func GetVideoFPS(videoPath string) (float64, error) {
 		videoPath,
 	)
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	if err := cmd.Run(); err != nil {
+	if err != nil {
 		return 0, fmt.Errorf("ffprobe execution failed: %w", err)
 	}
 
 	// e.g., "30/1" or "30000/1001" for FPS
-	fpsStr := strings.TrimSpace(out.String())
+	fpsStr := strings.TrimSpace(string(out))
 	fpsParts := strings.Split(fpsStr, "/")
 	var fps float64
 
@@ -1,22 +1,21 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
 
 // IsFragmentedMP4 checks if the given MP4 file is a fragmented MP4 (fMP4).
-func IsFragmentedMP4(videoPath string) (bool, error) {
+func IsFragmentedMP4(ctx context.Context, videoPath string) (bool, error) {
 	mp4infoPath, err := GetBentoMP4InfoPath()
 	if err != nil {
 		return false, err
 	}
 
-	cmd := exec.Command(mp4infoPath, videoPath)
-	output, err := cmd.CombinedOutput()
+	output, _, err := runCommand(ctx, ProbeTimeout, mp4infoPath, videoPath)
 	if err != nil {
 		return false, err
 	}
@@ -32,8 +31,9 @@ func IsFragmentedMP4(videoPath string) (bool, error) {
 }
 
 // ConvertMP4ToFragmentedMP4InPlace converts a standard MP4 to a fragmented MP4 file,
-// safely overwriting the input file by writing to a temp file first.
-func ConvertMP4ToFragmentedMP4InPlace(filePath string) error {
+// safely overwriting the input file by writing to a temp file first. progress, when not nil,
+// follows ffmpeg through the file.
+func ConvertMP4ToFragmentedMP4InPlace(ctx context.Context, filePath string, progress ProgressFunc) error {
 	ffmpegPath, err := GetFFmpegPath()
 	if err != nil {
 		return fmt.Errorf("failed to get ffmpeg path: %w", err)
@@ -47,7 +47,7 @@ func ConvertMP4ToFragmentedMP4InPlace(filePath string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	cmd := exec.Command(
+	_, err = runFFmpeg(ctx, EncodeTimeout, progressDuration(ctx, filePath, progress), progress,
 		ffmpegPath,
 		"-i", filePath,
 		"-c", "copy",
@@ -55,11 +55,8 @@ func ConvertMP4ToFragmentedMP4InPlace(filePath string) error {
 		tmpFile,
 	)
 
-	// Suppress output unless error occurs
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
+	if err != nil {
+		os.Remove(tmpFile) // a killed ffmpeg leaves a half-written file
 		return fmt.Errorf("ffmpeg fragmenting failed: %w", err)
 	}
 
@@ -72,8 +69,9 @@ func ConvertMP4ToFragmentedMP4InPlace(filePath string) error {
 }
 
 // ConvertFragmentedMP4ToUnfragmentedMP4InPlace converts a fragmented MP4 (fMP4) to a standard MP4,
-// safely overwriting the input file by writing to a temp file first.
-func ConvertFragmentedMP4ToUnfragmentedMP4InPlace(filePath string) error {
+// safely overwriting the input file by writing to a temp file first. progress, when not nil,
+// follows ffmpeg through the file.
+func ConvertFragmentedMP4ToUnfragmentedMP4InPlace(ctx context.Context, filePath string, progress ProgressFunc) error {
 	ffmpegPath, err := GetFFmpegPath()
 	if err != nil {
 		return fmt.Errorf("failed to get ffmpeg path: %w", err)
@@ -87,7 +85,7 @@ func ConvertFragmentedMP4ToUnfragmentedMP4InPlace(filePath string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	cmd := exec.Command(
+	_, err = runFFmpeg(ctx, EncodeTimeout, progressDuration(ctx, filePath, progress), progress,
 		ffmpegPath,
 		"-i", filePath,
 		"-c", "copy",
@@ -95,10 +93,8 @@ func ConvertFragmentedMP4ToUnfragmentedMP4InPlace(filePath string) error {
 		tmpFile,
 	)
 
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
+	if err != nil {
+		os.Remove(tmpFile) // a killed ffmpeg leaves a half-written file
 		return fmt.Errorf("ffmpeg unfragmenting failed: %w", err)
 	}
 
@@ -1,21 +1,20 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 )
 
 // PrintMP4Info runs mp4info on the provided video path and prints the output.
-func GetMP4Info(videoPath string) (string, error) {
+func GetMP4Info(ctx context.Context, videoPath string) (string, error) {
 	mp4infoPath, err := GetBentoMP4InfoPath()
 	if err != nil {
 		return "", fmt.Errorf("could not resolve mp4info path: %w", err)
 	}
 
-	cmd := exec.Command(mp4infoPath, videoPath)
-	output, err := cmd.CombinedOutput()
+	output, _, err := runCommand(ctx, ProbeTimeout, mp4infoPath, videoPath)
 	if err != nil {
-		return "", fmt.Errorf("mp4info execution failed: %w\nOutput: %s", err, string(output))
+		return "", fmt.Errorf("mp4info execution failed: %w", err)
 	}
 
 	return string(output), nil
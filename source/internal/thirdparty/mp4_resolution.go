@@ -1,22 +1,21 @@
 package thirdparty
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"ova-cli/source/internal/datatypes"
 	"strconv"
 	"strings"
 )
 
 // GetVideoResolution returns the width and height of a video.
-func GetVideoResolution(videoPath string) (datatypes.VideoResolution, error) {
+func GetVideoResolution(ctx context.Context, videoPath string) (datatypes.VideoResolution, error) {
 	ffprobePath, err := GetFFprobePath()
 	if err != nil {
 		return datatypes.VideoResolution{}, err
 	}
 
-	cmd := exec.Command(
+	out, _, err := runCommand(ctx, ProbeTimeout,
 		ffprobePath,
 		"-v", "error",
 		"-select_streams", "v:0",
@@ -25,14 +24,11 @@ func GetVideoResolution(videoPath string) (datatypes.VideoResolution, error) {
 		videoPath,
 	)
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	if err := cmd.Run(); err != nil {
+	if err != nil {
 		return datatypes.VideoResolution{}, fmt.Errorf("ffprobe execution failed: %w", err)
 	}
 
-	resolutionStr := strings.TrimSpace(out.String()) // e.g. "1920x1080"
+	resolutionStr := strings.TrimSpace(string(out)) // e.g. "1920x1080"
 	parts := strings.Split(resolutionStr, "x")
 	if len(parts) != 2 {
 		return datatypes.VideoResolution{}, fmt.Errorf("unexpected resolution format: %s", resolutionStr)
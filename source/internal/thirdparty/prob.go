@@ -1,13 +1,13 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 )
 
-func GetKeyframePacketTimestamps(videoPath string) ([]float64, error) {
+func GetKeyframePacketTimestamps(ctx context.Context, videoPath string) ([]float64, error) {
 	ffprobePath, err := GetFFprobePath()
 	if err != nil {
 		return nil, err
@@ -21,8 +21,7 @@ func GetKeyframePacketTimestamps(videoPath string) ([]float64, error) {
 		videoPath,
 	}
 
-	cmd := exec.Command(ffprobePath, args...)
-	out, err := cmd.Output()
+	out, _, err := runCommand(ctx, EncodeTimeout, ffprobePath, args...)
 	if err != nil {
 		return nil, fmt.Errorf("ffprobe failed: %w", err)
 	}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package thirdparty
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the tool in its own process group so a cancel can
+// take down anything it spawned along with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
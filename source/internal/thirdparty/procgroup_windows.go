@@ -0,0 +1,17 @@
+//go:build windows
+
+package thirdparty
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows; the bundled tools do not fork.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
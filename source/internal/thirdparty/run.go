@@ -0,0 +1,87 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Default time limits for external tools, applied only when the caller's
+// context carries no deadline of its own.
+const (
+	ProbeTimeout  = 2 * time.Minute // ffprobe / mp4info metadata reads
+	FrameTimeout  = 5 * time.Minute // single frames and short preview clips
+	EncodeTimeout = 6 * time.Hour   // whole-file passes (keyframes, remux)
+)
+
+// killGrace is how long a cancelled tool gets to release its pipes after its
+// process group has been killed before Wait gives up on it.
+const killGrace = 5 * time.Second
+
+// stderrTailSize caps how much of a tool's stderr is kept for error messages.
+const stderrTailSize = 4 << 10
+
+// runCommand runs an external tool bound to ctx and returns its stdout and the tail of its
+// stderr. When ctx has no deadline, timeout is applied. On cancellation the whole process
+// group is killed (ffmpeg may spawn helpers) and the returned error wraps ctx.Err(), so
+// callers can test it with errors.Is(err, context.Canceled) or context.DeadlineExceeded.
+func runCommand(ctx context.Context, timeout time.Duration, path string, args ...string) ([]byte, string, error) {
+	var stdout bytes.Buffer
+	stderr, err := runCommandTo(ctx, timeout, &stdout, path, args...)
+	return stdout.Bytes(), stderr, err
+}
+
+// runCommandTo is runCommand with stdout written to w while the tool runs.
+func runCommandTo(ctx context.Context, timeout time.Duration, w io.Writer, path string, args ...string) (string, error) {
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = killGrace
+
+	stderr := &tailBuffer{limit: stderrTailSize}
+	cmd.Stdout = w
+	cmd.Stderr = stderr
+
+	name := filepath.Base(path)
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return stderr.String(), fmt.Errorf("%s stopped: %w", name, ctxErr)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stderr.String(), fmt.Errorf("%s failed: %w: %s", name, err, msg)
+		}
+		return stderr.String(), fmt.Errorf("%s failed: %w", name, err)
+	}
+	return stderr.String(), nil
+}
+
+// tailBuffer keeps only the last limit bytes written to it; ffmpeg prints the
+// actual error after a long banner, so the tail is the useful part.
+type tailBuffer struct {
+	buf   []byte
+	limit int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.limit {
+		t.buf = append(t.buf[:0], t.buf[len(t.buf)-t.limit:]...)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return string(t.buf)
+}
@@ -1,6 +1,7 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -97,3 +98,21 @@ func formatDuration(d time.Duration) string {
 	ms := int(d.Milliseconds()) % 1000
 	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
 }
+
+// StoryboardFrameTimes returns the time of each frame ExtractKeyframes wrote to framesDir.
+// With intervalSec above 0 the frames are evenly spaced from 0, otherwise they are the
+// keyframes of the video, whose times are read with ffprobe.
+func StoryboardFrameTimes(ctx context.Context, videoPath, framesDir string, intervalSec float64) ([]float64, error) {
+	if intervalSec <= 0 {
+		return GetKeyframePacketTimestamps(ctx, videoPath)
+	}
+	frames, err := filepath.Glob(filepath.Join(framesDir, "keyframe_*.jpg"))
+	if err != nil {
+		return nil, err
+	}
+	times := make([]float64, len(frames))
+	for i := range times {
+		times[i] = float64(i) * intervalSec
+	}
+	return times, nil
+}
@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// TrimVideoTo re-encodes duration seconds of a video from start into a fragmented mp4 and
+// writes it to w while ffmpeg makes it, so it can be streamed. Cancelling ctx kills ffmpeg.
+func TrimVideoTo(ctx context.Context, w io.Writer, videoPath string, start, duration float64) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return err
+	}
+
+	_, err = runCommandTo(ctx, EncodeTimeout, w,
+		ffmpegPath,
+		"-ss", fmt.Sprintf("%.2f", start),
+		"-i", videoPath,
+		"-t", fmt.Sprintf("%.2f", duration),
+		"-c:v", "libx264",
+		"-c:a", "aac",
+		"-preset", "fast",
+		"-movflags", "frag_keyframe+empty_moov",
+		"-f", "mp4",
+		"pipe:1",
+	)
+	if err != nil {
+		return fmt.Errorf("ffmpeg error: %w", err)
+	}
+	return nil
+}
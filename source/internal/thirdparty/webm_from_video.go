@@ -1,14 +1,16 @@
 package thirdparty
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 )
 
-// GenerateWebMFromVideo generates a short webm preview from a given video
-func GenerateWebMFromVideo(videoPath, outputPath string, startTime float64, duration float64) error {
+// GenerateWebMFromVideo generates a short webm preview from a given video, scaled to width and
+// encoded with codec (vp8 or vp9) at bitrate. progress, when not nil, follows ffmpeg through
+// the clip.
+func GenerateWebMFromVideo(ctx context.Context, videoPath, outputPath string, startTime float64, duration float64, width int, codec, bitrate string, progress ProgressFunc) error {
 	ffmpegPath, err := GetFFmpegPath()
 	if err != nil {
 		return err
@@ -18,29 +20,38 @@ func GenerateWebMFromVideo(videoPath, outputPath string, startTime float64, dura
 		startTime = 0
 	}
 
+	encoder := "libvpx"
+	switch codec {
+	case "", "vp8":
+	case "vp9":
+		encoder = "libvpx-vp9"
+	default:
+		return fmt.Errorf("unsupported WebM codec %q, use vp8 or vp9", codec)
+	}
+
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	cmd := exec.Command(
+	_, err = runFFmpeg(ctx, FrameTimeout, duration, progress,
 		ffmpegPath,
+		"-y",
 		"-ss", fmt.Sprintf("%.2f", startTime),
 		"-i", videoPath,
 		"-t", fmt.Sprintf("%.2f", duration),
 		"-an",
-		"-vf", "scale=320:-1",
-		"-c:v", "libvpx",
+		"-vf", fmt.Sprintf("scale=%d:-1", width),
+		"-c:v", encoder,
 		"-quality", "realtime",
 		"-cpu-used", "7",
 		"-threads", "2", // 0 = auto threads
-		"-b:v", "500K",
+		"-b:v", bitrate,
 		outputPath,
 	)
 
-	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("ffmpeg error: %v, output: %s", err, string(output))
+		return fmt.Errorf("ffmpeg error: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,57 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 appendix B test vectors, base32 encoded.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// TestCodeAtRFC6238 checks the SHA1 test vectors of RFC 6238 appendix B. The RFC lists 8
+// digits; a 6 digit code is their last 6.
+func TestCodeAtRFC6238(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, want := range vectors {
+		got, err := CodeAt(rfc6238Secret, Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt at %d: %v", unix, err)
+		}
+		if got != want[len(want)-Digits:] {
+			t.Errorf("CodeAt at %d = %s, want %s", unix, got, want[len(want)-Digits:])
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := CodeAt(rfc6238Secret, Step(now))
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+
+	if step, ok := Validate(rfc6238Secret, code[:3]+" "+code[3:], now); !ok || step != Step(now) {
+		t.Errorf("Validate of the current code = %d, %v, want %d, true", step, ok, Step(now))
+	}
+	if _, ok := Validate(rfc6238Secret, code, now.Add(Period*time.Second)); !ok {
+		t.Errorf("code of the previous step is rejected")
+	}
+	if _, ok := Validate(rfc6238Secret, code, now.Add(2*Period*time.Second)); ok {
+		t.Errorf("code of two steps ago is accepted")
+	}
+	if _, ok := Validate(rfc6238Secret, code[:Digits-1], now); ok {
+		t.Errorf("short code is accepted")
+	}
+	if _, ok := Validate(strings.ToLower(rfc6238Secret), code, now); !ok {
+		t.Errorf("lower case secret is rejected")
+	}
+}
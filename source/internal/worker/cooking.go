@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"ova-cli/ovaproto"
+	"ova-cli/source/internal/thirdparty"
+)
+
+// cookVideo makes the preview thumbnails of the video at videoPath like the server would:
+// sprite sheets of keyframes and the VTT file pointing into them, laid out as the job's
+// storyboard settings say.
+func cookVideo(ctx context.Context, job *ovaproto.Job, videoPath, dir string, progress *progressReporter) (map[string]string, error) {
+	if job.VideoId == "" {
+		return nil, permanentJobFailure(fmt.Errorf("job has no video ID"))
+	}
+	name := filepath.Base(job.VideoPath)
+
+	keyframeDir, err := os.MkdirTemp("", "ova-keyframes-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(keyframeDir)
+
+	settings := storyboardSettings(job.Storyboard)
+	tile := fmt.Sprintf("%dx%d", settings.Columns, settings.Rows)
+	width, height := int(settings.TileWidth), int(settings.TileHeight)
+
+	progress.Report(20, "Extracting keyframes from %s", name)
+	reported := 20
+	onFFmpeg := func(p thirdparty.FFmpegProgress) {
+		if percent := 20 + int(p.Percent()*0.3); percent > reported {
+			reported = percent
+			progress.Report(percent, "")
+		}
+	}
+	if err := thirdparty.ExtractKeyframes(ctx, videoPath, keyframeDir, width, height, settings.IntervalSec, onFFmpeg); err != nil {
+		return nil, fmt.Errorf("keyframe extraction error for %s: %w", name, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	progress.Report(50, "Making sprite sheets")
+	spritePattern := filepath.Join(dir, "thumb_L0_%03d.jpg")
+	if err := thirdparty.GenerateSpriteSheetsFromFolder(keyframeDir, spritePattern, tile, width, height, int(settings.Quality)); err != nil {
+		return nil, fmt.Errorf("sprite generation error for %s: %w", name, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	progress.Report(80, "")
+	keyframeTimes, err := thirdparty.StoryboardFrameTimes(ctx, videoPath, keyframeDir, settings.IntervalSec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyframe timestamps for %s: %w", name, err)
+	}
+	if len(keyframeTimes) == 0 {
+		return nil, permanentJobFailure(fmt.Errorf("no keyframes found for %s", name))
+	}
+
+	vttPattern := filepath.Join("/api/v1/preview-thumbnails", job.VideoId, "thumb_L0_%03d.jpg")
+	if err := thirdparty.GenerateVTT(keyframeTimes, tile, width, height, vttPattern, filepath.Join(dir, "thumbnails.vtt"), ""); err != nil {
+		return nil, fmt.Errorf("VTT generation error for %s: %w", name, err)
+	}
+
+	sprites, _ := filepath.Glob(filepath.Join(dir, "thumb_L0_*.jpg"))
+	progress.Report(95, "Made %d sprite sheets from %d keyframes", len(sprites), len(keyframeTimes))
+	return map[string]string{
+		"keyframes": strconv.Itoa(len(keyframeTimes)),
+		"sprites":   strconv.Itoa(len(sprites)),
+	}, nil
+}
+
+// storyboardSettings fills what a job leaves out of its storyboard settings with the layout
+// servers used before they sent one.
+func storyboardSettings(s *ovaproto.StoryboardSettings) *ovaproto.StoryboardSettings {
+	filled := &ovaproto.StoryboardSettings{TileWidth: 160, TileHeight: 90, Columns: 5, Rows: 5, Quality: 75}
+	if s == nil {
+		return filled
+	}
+	if s.TileWidth > 0 && s.TileHeight > 0 {
+		filled.TileWidth, filled.TileHeight = s.TileWidth, s.TileHeight
+	}
+	if s.Columns > 0 && s.Rows > 0 {
+		filled.Columns, filled.Rows = s.Columns, s.Rows
+	}
+	if s.Quality > 0 {
+		filled.Quality = s.Quality
+	}
+	filled.IntervalSec = max(0, s.IntervalSec)
+	return filled
+}
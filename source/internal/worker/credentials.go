@@ -0,0 +1,15 @@
+package worker
+
+import "context"
+
+// tokenCredentials sends the worker token with every call.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// The server has no TLS; the token is as safe as the network between them.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"ova-cli/ovaproto"
+)
+
+// progressReporter streams the progress of one job to the server and stops the job when
+// the server answers that it was cancelled or is no longer the worker's.
+type progressReporter struct {
+	stream   ovaproto.OvaService_StreamProgressClient
+	jobId    string
+	workerId string
+
+	mu       sync.Mutex
+	percent  int
+	lastSent time.Time
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func (w *Worker) openProgress(ctx context.Context, job *ovaproto.Job, stop context.CancelFunc) (*progressReporter, error) {
+	stream, err := w.client.StreamProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := &progressReporter{
+		stream:   stream,
+		jobId:    job.JobId,
+		workerId: w.id,
+		done:     make(chan struct{}),
+	}
+
+	p.wg.Add(2)
+	go func() {
+		defer p.wg.Done()
+		for {
+			ack, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if ack.Cancelled || !ack.Accepted {
+				workerLogger.Info("Stopping job %s: %s", ack.JobId, ack.Message)
+				stop()
+			}
+		}
+	}()
+	// Idle steps still renew the lease
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(maxProgressDelay)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				stale := time.Since(p.lastSent) >= maxProgressDelay
+				percent := p.percent
+				p.mu.Unlock()
+				if stale {
+					p.Report(percent, "")
+				}
+			}
+		}
+	}()
+	return p, nil
+}
+
+// Report sends how far the job is, from 0 to 100, and an optional line for the task log.
+func (p *progressReporter) Report(percent int, format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.percent = percent
+	p.lastSent = time.Now()
+
+	update := &ovaproto.ProgressUpdate{
+		JobId:      p.jobId,
+		WorkerId:   p.workerId,
+		Percentage: float32(percent),
+	}
+	if format != "" {
+		update.Message = fmt.Sprintf(format, args...)
+	}
+	if err := p.stream.Send(update); err != nil && p.stream.Context().Err() == nil {
+		workerLogger.Warn("Failed to send progress of job %s: %v", p.jobId, err)
+	}
+}
+
+// close ends the stream once the server answered everything sent.
+func (p *progressReporter) close() {
+	close(p.done)
+	p.mu.Lock()
+	_ = p.stream.CloseSend()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// loadSampler measures the CPU and RAM use of the machine, in percent. It reads /proc and
+// reports 0 on other systems than Linux, which the server treats as idle.
+type loadSampler struct {
+	mu        sync.Mutex
+	lastIdle  uint64
+	lastTotal uint64
+}
+
+// Sample returns the CPU use since the previous sample and the RAM in use now.
+func (s *loadSampler) Sample() (cpu, ram float32) {
+	if runtime.GOOS != "linux" {
+		return 0, 0
+	}
+	return s.cpuUsage(), memoryUsage()
+}
+
+func (s *loadSampler) cpuUsage() float32 {
+	idle, total, ok := readCPUTimes()
+	if !ok {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idleDelta, totalDelta := idle-s.lastIdle, total-s.lastTotal
+	first := s.lastTotal == 0
+	s.lastIdle, s.lastTotal = idle, total
+	if first || totalDelta == 0 {
+		return 0
+	}
+	return 100 * (1 - float32(idleDelta)/float32(totalDelta))
+}
+
+// readCPUTimes sums the jiffies of all CPUs from the first line of /proc/stat.
+func readCPUTimes() (idle, total uint64, ok bool) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return 0, 0, false
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, false
+	}
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total += value
+		// idle and iowait
+		if i == 3 || i == 4 {
+			idle += value
+		}
+	}
+	return idle, total, true
+}
+
+// memoryUsage is the share of RAM not available to new programs, from /proc/meminfo.
+func memoryUsage() float32 {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var memTotal, memAvailable float32
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 32)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			memTotal = float32(value)
+		case "MemAvailable:":
+			memAvailable = float32(value)
+		}
+	}
+	if memTotal == 0 {
+		return 0
+	}
+	return 100 * (1 - memAvailable/memTotal)
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"ova-cli/ovaproto"
+)
+
+// videoSource returns where the worker reads the video of a job: the server's path when the
+// worker sees the same file there, or a copy downloaded into workDir.
+func (w *Worker) videoSource(ctx context.Context, job *ovaproto.Job, workDir string, progress *progressReporter) (string, error) {
+	if w.transfer != TransferAlways {
+		info, err := os.Stat(job.VideoPath)
+		switch {
+		case err == nil && (job.VideoSize == 0 || info.Size() == job.VideoSize):
+			return job.VideoPath, nil
+		case w.transfer == TransferNever && err != nil:
+			// Another worker may see the file
+			return "", fmt.Errorf("video file is not reachable from this worker: %w", err)
+		case w.transfer == TransferNever:
+			return "", fmt.Errorf("video file on this worker has %d bytes, the server's has %d", info.Size(), job.VideoSize)
+		}
+	}
+
+	path := filepath.Join(workDir, "source"+filepath.Ext(job.VideoPath))
+	progress.Report(0, "Downloading the video (%.1f MB) from the server", float64(job.VideoSize)/(1<<20))
+	if err := w.download(ctx, job, path, progress); err != nil {
+		return "", fmt.Errorf("failed to download the video: %w", err)
+	}
+	return path, nil
+}
+
+// download copies the video of a job into path, resuming once when the stream breaks.
+// Download progress counts as the first 20 percent of the job.
+func (w *Worker) download(ctx context.Context, job *ovaproto.Job, path string, progress *progressReporter) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	offset := int64(0)
+	reported := 0
+	for attempt := 0; attempt < 2; attempt++ {
+		stream, err := w.client.DownloadVideo(ctx, &ovaproto.VideoRequest{JobId: job.JobId, WorkerId: w.id, Offset: offset})
+		if err != nil {
+			return err
+		}
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				if job.VideoSize > 0 && offset != job.VideoSize {
+					return fmt.Errorf("got %d of %d bytes", offset, job.VideoSize)
+				}
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				workerLogger.Warn("Download of job %s broke at %d bytes: %v", job.JobId, offset, err)
+				break
+			}
+			if chunk.Offset != offset {
+				return fmt.Errorf("chunk at %d, expected %d", chunk.Offset, offset)
+			}
+			if _, err := file.Write(chunk.Data); err != nil {
+				return err
+			}
+			offset += int64(len(chunk.Data))
+
+			if job.VideoSize > 0 {
+				if percent := int(20 * offset / job.VideoSize); percent > reported {
+					reported = percent
+					progress.Report(percent, "")
+				}
+			}
+		}
+	}
+	return fmt.Errorf("download broke at %d bytes", offset)
+}
@@ -0,0 +1,431 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ova-cli/ovaproto"
+	"ova-cli/source/internal/datatypes"
+	"ova-cli/source/internal/logs"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var workerLogger = logs.Loggers("Worker")
+
+const (
+	pollInterval     = 5 * time.Second  // How often an idle worker asks for a job
+	defaultLease     = time.Minute      // Assumed until the server says otherwise
+	reportTimeout    = 30 * time.Second // For calls that end a job, which run after a shutdown too
+	uploadChunkSize  = 1 << 20
+	maxProgressDelay = 10 * time.Second // Progress is sent at least this often, renewing the lease
+)
+
+// Capabilities are the task types this worker runs.
+var Capabilities = []datatypes.TaskType{datatypes.TaskCooking}
+
+// How a worker gets the video of a job.
+const (
+	TransferAuto   = "auto"   // Read the server's path when it holds the same file, else download
+	TransferAlways = "always" // Always download
+	TransferNever  = "never"  // Only read the server's path
+)
+
+// Options configure a worker.
+type Options struct {
+	Server   string // host:port of the gRPC service of "ovacli serve" or "ovacli grpc"
+	Token    string // The repository's worker token
+	ID       string // Defaults to hostname-pid
+	Jobs     int    // Jobs run at once, 0 for 1
+	Transfer string // TransferAuto when empty
+}
+
+// Worker leases jobs from a server, runs them and reports back.
+type Worker struct {
+	id       string
+	hostname string
+	maxJobs  int
+	transfer string
+	conn     *grpc.ClientConn
+	client   ovaproto.OvaServiceClient
+	load     loadSampler
+	finished chan struct{} // Signalled when a job ends
+
+	mu    sync.Mutex
+	jobs  map[string]context.CancelFunc // Running jobs
+	lease time.Duration
+}
+
+// permanentJobError marks a failure that retrying cannot fix.
+type permanentJobError struct {
+	err error
+}
+
+func (e *permanentJobError) Error() string { return e.err.Error() }
+func (e *permanentJobError) Unwrap() error { return e.err }
+
+func permanentJobFailure(err error) error {
+	return &permanentJobError{err: err}
+}
+
+// New connects a worker to its server. The connection is made lazily by the first call.
+func New(opts Options) (*Worker, error) {
+	if opts.Server == "" {
+		return nil, fmt.Errorf("server address is required")
+	}
+	if opts.Token == "" {
+		return nil, fmt.Errorf("worker token is required")
+	}
+	switch opts.Transfer {
+	case "":
+		opts.Transfer = TransferAuto
+	case TransferAuto, TransferAlways, TransferNever:
+	default:
+		return nil, fmt.Errorf("transfer must be %s, %s or %s", TransferAuto, TransferAlways, TransferNever)
+	}
+
+	hostname, _ := os.Hostname()
+	id := opts.ID
+	if id == "" {
+		id = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	conn, err := grpc.NewClient(opts.Server,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(tokenCredentials(opts.Token)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", opts.Server, err)
+	}
+
+	return &Worker{
+		id:       id,
+		hostname: hostname,
+		maxJobs:  max(1, opts.Jobs),
+		transfer: opts.Transfer,
+		conn:     conn,
+		client:   ovaproto.NewOvaServiceClient(conn),
+		finished: make(chan struct{}, 1),
+		jobs:     map[string]context.CancelFunc{},
+		lease:    defaultLease,
+	}, nil
+}
+
+// ID returns the name the worker reports to the server.
+func (w *Worker) ID() string {
+	return w.id
+}
+
+// Close closes the connection to the server.
+func (w *Worker) Close() error {
+	return w.conn.Close()
+}
+
+// Run leases and runs jobs until ctx is done. Jobs interrupted by ctx are handed back to the
+// server to be retried.
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.heartbeatLoop(ctx)
+	}()
+	defer wg.Wait()
+
+	// Prime the CPU sampler, which measures between two calls
+	w.load.Sample()
+
+	workerLogger.Info("Worker %s asking %s for up to %d jobs", w.id, w.conn.Target(), w.maxJobs)
+	for {
+		if w.activeJobs() < w.maxJobs {
+			job, err := w.client.GetNextJob(ctx, w.info())
+			switch {
+			case ctx.Err() != nil:
+				return nil
+			case err != nil:
+				workerLogger.Warn("Failed to get a job: %v", err)
+			case job.JobId != "":
+				w.startJob(ctx, job, &wg)
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.finished:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// startJob registers a leased job and runs it in the background.
+func (w *Worker) startJob(ctx context.Context, job *ovaproto.Job, wg *sync.WaitGroup) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	w.mu.Lock()
+	w.jobs[job.JobId] = cancel
+	if job.LeaseSeconds > 0 {
+		w.lease = time.Duration(job.LeaseSeconds) * time.Second
+	}
+	w.mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			cancel()
+			w.mu.Lock()
+			delete(w.jobs, job.JobId)
+			w.mu.Unlock()
+			select {
+			case w.finished <- struct{}{}:
+			default:
+			}
+		}()
+		w.runJob(jobCtx, cancel, job)
+	}()
+}
+
+func (w *Worker) activeJobs() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.jobs)
+}
+
+func (w *Worker) info() *ovaproto.WorkerInfo {
+	active := w.activeJobs()
+	cpu, ram := w.load.Sample()
+
+	capabilities := make([]string, 0, len(Capabilities))
+	for _, capability := range Capabilities {
+		capabilities = append(capabilities, string(capability))
+	}
+	return &ovaproto.WorkerInfo{
+		WorkerId:     w.id,
+		Hostname:     w.hostname,
+		Capabilities: capabilities,
+		MaxJobs:      int32(w.maxJobs),
+		ActiveJobs:   int32(active),
+		CpuUsage:     cpu,
+		RamUsage:     ram,
+	}
+}
+
+// heartbeatLoop keeps the worker registered, renews the leases of its jobs and stops the
+// ones the server gave up or cancelled.
+func (w *Worker) heartbeatLoop(ctx context.Context) {
+	for {
+		w.mu.Lock()
+		interval := w.lease / 3
+		jobIds := make([]string, 0, len(w.jobs))
+		for id := range w.jobs {
+			jobIds = append(jobIds, id)
+		}
+		w.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		info := w.info()
+		resp, err := w.client.Heartbeat(ctx, &ovaproto.HeartbeatRequest{
+			WorkerId:     w.id,
+			Hostname:     w.hostname,
+			Capabilities: info.Capabilities,
+			MaxJobs:      info.MaxJobs,
+			ActiveJobs:   int32(len(jobIds)),
+			CpuUsage:     info.CpuUsage,
+			RamUsage:     info.RamUsage,
+			JobIds:       jobIds,
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				workerLogger.Warn("Heartbeat failed: %v", err)
+			}
+			continue
+		}
+
+		w.mu.Lock()
+		if resp.LeaseSeconds > 0 {
+			w.lease = time.Duration(resp.LeaseSeconds) * time.Second
+		}
+		for _, id := range resp.CancelledJobIds {
+			if cancel := w.jobs[id]; cancel != nil {
+				workerLogger.Info("Job %s was cancelled or given up by the server", id)
+				cancel()
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+// runJob runs a leased job and reports how it ended. stop cancels ctx.
+func (w *Worker) runJob(ctx context.Context, stop context.CancelFunc, job *ovaproto.Job) {
+	workerLogger.Info("Running job %s (%s) for video %s", job.JobId, job.TaskType, job.VideoId)
+
+	// The output folder only holds what is uploaded; a downloaded video goes next to it
+	workDir, err := os.MkdirTemp("", "ova-job-*")
+	if err != nil {
+		w.fail(job, err)
+		return
+	}
+	defer os.RemoveAll(workDir)
+	dir := filepath.Join(workDir, "out")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		w.fail(job, err)
+		return
+	}
+
+	progress, err := w.openProgress(ctx, job, stop)
+	if err != nil {
+		w.fail(job, err)
+		return
+	}
+	outputs, err := w.runJobType(ctx, job, workDir, dir, progress)
+	progress.close()
+
+	switch {
+	case err == nil && ctx.Err() != nil:
+		err = ctx.Err()
+	case err == nil:
+		err = w.complete(ctx, job, dir, outputs)
+	}
+	if err != nil {
+		w.fail(job, err)
+	}
+}
+
+// runJobType runs the handler of the job's task type, writing its output files to dir.
+func (w *Worker) runJobType(ctx context.Context, job *ovaproto.Job, workDir, dir string, progress *progressReporter) (map[string]string, error) {
+	switch datatypes.TaskType(job.TaskType) {
+	case datatypes.TaskCooking:
+		videoPath, err := w.videoSource(ctx, job, workDir, progress)
+		if err != nil {
+			return nil, err
+		}
+		return cookVideo(ctx, job, videoPath, dir, progress)
+	default:
+		return nil, permanentJobFailure(fmt.Errorf("this worker cannot run %q jobs", job.TaskType))
+	}
+}
+
+// complete uploads the files in dir and completes the job.
+func (w *Worker) complete(ctx context.Context, job *ovaproto.Job, dir string, outputs map[string]string) error {
+	artifacts, err := w.upload(ctx, job, dir)
+	if err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	ack, err := w.client.CompleteJob(ctx, &ovaproto.CompleteJobRequest{
+		JobId:     job.JobId,
+		WorkerId:  w.id,
+		Artifacts: artifacts,
+		Outputs:   outputs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete: %w", err)
+	}
+	workerLogger.Info("Job %s finished, task is %s", job.JobId, ack.Status)
+	return nil
+}
+
+// upload streams the files in dir to the server and returns their names.
+func (w *Worker) upload(ctx context.Context, job *ovaproto.Job, dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := w.client.UploadArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	buffer := make([]byte, uploadChunkSize)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := sendFile(stream, job.JobId, w.id, filepath.Join(dir, entry.Name()), buffer); err != nil {
+			return nil, err
+		}
+		names = append(names, entry.Name())
+	}
+
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	workerLogger.Info("Uploaded %d files (%d bytes) for job %s", summary.Files, summary.Bytes, job.JobId)
+	return names, nil
+}
+
+func sendFile(stream ovaproto.OvaService_UploadArtifactsClient, jobId, workerId, path string, buffer []byte) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	offset := int64(0)
+	for {
+		n, err := file.Read(buffer)
+		// Empty files are sent as one empty chunk so the server creates them
+		if n > 0 || offset == 0 {
+			chunk := &ovaproto.ArtifactChunk{
+				JobId:    jobId,
+				WorkerId: workerId,
+				Name:     filepath.Base(path),
+				Offset:   offset,
+				Data:     buffer[:n],
+			}
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// fail reports a failed or interrupted job. A job stopped because the worker shuts down is
+// handed back without using up an attempt; one the server cancelled or gave up is settled
+// by the server before that.
+func (w *Worker) fail(job *ovaproto.Job, err error) {
+	var permanent *permanentJobError
+	retryable := !errors.As(err, &permanent)
+	interrupted := errors.Is(err, context.Canceled)
+	if interrupted {
+		err = fmt.Errorf("stopped on worker %s", w.id)
+	}
+	workerLogger.Warn("Job %s failed: %v", job.JobId, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	ack, reportErr := w.client.FailJob(ctx, &ovaproto.FailJobRequest{
+		JobId:       job.JobId,
+		WorkerId:    w.id,
+		Error:       err.Error(),
+		Retryable:   retryable,
+		Interrupted: interrupted,
+	})
+	if reportErr != nil {
+		workerLogger.Warn("Failed to report the failure of job %s: %v", job.JobId, reportErr)
+		return
+	}
+	workerLogger.Info("Job %s reported as failed, task is %s", job.JobId, ack.Status)
+}
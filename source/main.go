@@ -27,6 +27,7 @@ including features for video processing, metadata management, and more.`,
 	cmd.InitCommandChecksum(rootCmd)
 	cmd.InitCommandTest(rootCmd)
 	cmd.InitCommandGrpc(rootCmd)
+	cmd.InitCommandWorker(rootCmd)
 	cmd.InitCommandWebsocket(rootCmd)
 
 	cmd.InitCommandSSL(rootCmd)
@@ -37,6 +38,10 @@ including features for video processing, metadata management, and more.`,
 	// storage commands
 	cmd.InitCommandVideo(rootCmd)
 	cmd.InitCommandUsers(rootCmd)
+	cmd.InitCommandAudit(rootCmd)
+	cmd.InitCommandSpace(rootCmd)
+	cmd.InitCommandShare(rootCmd)
+	cmd.InitCommandRecommendations(rootCmd)
 
 	cmd.InitCommandConfig(rootCmd)
 